@@ -3,7 +3,9 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync/atomic"
 )
 
 // Provider name constants.
@@ -12,23 +14,76 @@ const (
 	ProviderOpenAI = "openai"
 	ProviderGemini = "gemini"
 	ProviderOllama = "ollama"
+
+	// ProviderOpenAICompatible is the type of provider profiles for
+	// servers that speak the OpenAI API; see NewOpenAICompatible.
+	ProviderOpenAICompatible = "openai-compatible"
 )
 
-// StreamChunk is a single token or error delivered during streaming.
+// StreamChunk is a single token or error delivered during streaming. When
+// the model asks for tools to be run, the calls arrive in one chunk after
+// any text, and the stream then ends.
 type StreamChunk struct {
-	Text  string
-	Error error
+	Text      string
+	ToolCalls []ToolCall
+	Error     error
+}
+
+// Message roles used in CompletionRequest.History and ToolSteps.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Message is one turn of an earlier exchange in a conversation.
+type Message struct {
+	Role    string // RoleUser, RoleAssistant or RoleTool
+	Content string
+	// ToolCalls holds the tools an assistant message asked to run.
+	ToolCalls []ToolCall
+	// ToolCallID is the call a RoleTool message holds the result of.
+	ToolCallID string
+}
+
+// Tool describes a function the model may ask to run.
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters is the JSON Schema of the arguments: an object schema
+	// using only type, description, properties, items, enum and required,
+	// which every provider accepts.
+	Parameters json.RawMessage
+}
+
+// ToolCall is a request from the model to run a tool.
+type ToolCall struct {
+	// ID pairs the call with its result. Adapters for providers that don't
+	// assign IDs generate them.
+	ID        string
+	Name      string
+	Arguments json.RawMessage // JSON object
 }
 
 // CompletionRequest holds the parameters for a completion call.
 type CompletionRequest struct {
 	SystemPrompt string
 	Context      string
-	UserMessage  string
-	Model        string
-	MaxTokens    int
-	Temperature  float64
-	Stream       bool
+	// History holds the earlier turns of the conversation, oldest first.
+	// UserMessage is the new turn that follows them; Context is attached to
+	// it, not to the history.
+	History     []Message
+	UserMessage string
+	// Tools are the functions the model may call while answering.
+	Tools []Tool
+	// ToolSteps holds the model's tool calls since UserMessage and their
+	// results, in order: RoleAssistant messages with ToolCalls, each
+	// followed by a RoleTool message per call.
+	ToolSteps   []Message
+	Model       string
+	MaxTokens   int
+	Temperature float64
+	Stream      bool
 }
 
 // ModelInfo describes the capabilities of a model.
@@ -36,8 +91,9 @@ type ModelInfo struct {
 	Name               string
 	Provider           string
 	MaxContextWindow   int
-	SupportsStreaming   bool
-	EmbeddingDimension int // 0 if not an embedding model
+	SupportsStreaming  bool
+	EmbeddingDimension int    // 0 if not an embedding model or unknown
+	EmbeddingModel     string // model used by Embed; empty if embeddings are unsupported
 }
 
 // LLMAdapter is the common interface all provider adapters implement.
@@ -55,28 +111,53 @@ type LLMAdapter interface {
 // New constructs the LLMAdapter for the named provider.
 //
 //   - provider: "claude", "openai", "gemini", "ollama"
-//   - embedModel: embedding model name (used by Ollama; ignored by others)
+//   - models: completion and embedding models (empty = the provider's defaults)
 //   - apiKey: provider API key (empty = read from env in the concrete adapter)
 //   - ollamaHost: base URL for the Ollama server (used only when provider == "ollama")
-func New(provider, embedModel, apiKey, ollamaHost string) (LLMAdapter, error) {
+func New(provider string, models Models, apiKey, ollamaHost string) (LLMAdapter, error) {
 	switch provider {
 	case ProviderClaude:
-		return NewClaude(apiKey), nil
+		return NewClaude(apiKey, models), nil
 	case ProviderOpenAI:
-		return NewOpenAI(apiKey), nil
+		return NewOpenAI(apiKey, models), nil
 	case ProviderGemini:
-		return NewGemini(apiKey), nil
+		return NewGemini(apiKey, models), nil
 	case ProviderOllama:
 		host := ollamaHost
 		if host == "" {
 			host = "http://localhost:11434"
 		}
-		model := embedModel
-		if model == "" {
-			model = "nomic-embed-text"
-		}
-		return NewOllama(host, model), nil
+		return NewOllama(host, models), nil
 	default:
 		return nil, fmt.Errorf("adapter: unknown provider %q; valid providers: claude, openai, gemini, ollama", provider)
 	}
 }
+
+// toolArguments returns args, or an empty JSON object when the provider sent
+// no arguments.
+func toolArguments(args json.RawMessage) json.RawMessage {
+	if len(args) == 0 || string(args) == "null" {
+		return json.RawMessage("{}")
+	}
+	return args
+}
+
+// toolCallNames maps the IDs of the tool calls in msgs to the tools called,
+// for providers whose tool results are matched by name rather than ID.
+func toolCallNames(msgs []Message) map[string]string {
+	names := make(map[string]string)
+	for _, m := range msgs {
+		for _, c := range m.ToolCalls {
+			names[c.ID] = c.Name
+		}
+	}
+	return names
+}
+
+// toolCallSeq numbers the tool calls of providers that don't assign IDs.
+var toolCallSeq atomic.Int64
+
+// newToolCallID returns a process-unique ID for such a tool call.
+func newToolCallID() string {
+	return fmt.Sprintf("call_%d", toolCallSeq.Add(1))
+}
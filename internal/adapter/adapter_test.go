@@ -8,6 +8,10 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"github.com/memvra/memvra/internal/config"
 )
 
 func TestNew_ValidProviders(t *testing.T) {
@@ -22,7 +26,7 @@ func TestNew_ValidProviders(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.provider, func(t *testing.T) {
-			a, err := New(tt.provider, "", "test-key", "")
+			a, err := New(tt.provider, Models{}, "test-key", "")
 			if err != nil {
 				t.Fatalf("New(%q) error: %v", tt.provider, err)
 			}
@@ -38,14 +42,14 @@ func TestNew_ValidProviders(t *testing.T) {
 }
 
 func TestNew_InvalidProvider(t *testing.T) {
-	_, err := New("invalid", "", "key", "")
+	_, err := New("invalid", Models{}, "key", "")
 	if err == nil {
 		t.Error("expected error for invalid provider")
 	}
 }
 
 func TestNew_OllamaDefaults(t *testing.T) {
-	a, err := New(ProviderOllama, "", "", "")
+	a, err := New(ProviderOllama, Models{}, "", "")
 	if err != nil {
 		t.Fatalf("New(ollama) error: %v", err)
 	}
@@ -101,7 +105,7 @@ func TestGeminiComplete_NonStreaming(t *testing.T) {
 	}
 
 	// Test doGenerate helper directly against the mock server.
-	text, err := adapter.doGenerate(
+	text, _, err := adapter.doGenerate(
 		context.Background(),
 		server.URL+"/v1beta/models/gemini-2.0-flash:generateContent?key=test-key",
 		[]byte(`{"contents":[{"role":"user","parts":[{"text":"Hello"}]}]}`),
@@ -202,7 +206,7 @@ func TestGeminiComplete_APIError(t *testing.T) {
 		client: server.Client(),
 	}
 
-	_, err := adapter.doGenerate(
+	_, _, err := adapter.doGenerate(
 		context.Background(),
 		server.URL+"/v1beta/models/gemini-2.0-flash:generateContent?key=bad-key",
 		[]byte(`{"contents":[{"role":"user","parts":[{"text":"Hello"}]}]}`),
@@ -214,3 +218,542 @@ func TestGeminiComplete_APIError(t *testing.T) {
 		t.Errorf("error should mention status code 403: %v", err)
 	}
 }
+
+func TestEmbeddingInfo_ReportedDimension(t *testing.T) {
+	name, dim, err := EmbeddingInfo(context.Background(), NewOpenAI("test-key", Models{}))
+	if err != nil {
+		t.Fatalf("EmbeddingInfo: %v", err)
+	}
+	if name != "openai/text-embedding-3-small" || dim != 1536 {
+		t.Errorf("got %s (%d), want openai/text-embedding-3-small (1536)", name, dim)
+	}
+
+	name, dim, _ = EmbeddingInfo(context.Background(), NewOllama("http://localhost:11434", Models{Embedding: "nomic-embed-text:latest"}))
+	if name != "ollama/nomic-embed-text:latest" || dim != 768 {
+		t.Errorf("got %s (%d), want ollama/nomic-embed-text:latest (768)", name, dim)
+	}
+}
+
+func TestEmbeddingInfo_ProbesUnknownModel(t *testing.T) {
+	probes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes++
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": [][]float32{make([]float32, 512)}})
+	}))
+	defer srv.Close()
+
+	name, dim, err := EmbeddingInfo(context.Background(), NewOllama(srv.URL, Models{Embedding: "custom-embedder"}))
+	if err != nil {
+		t.Fatalf("EmbeddingInfo: %v", err)
+	}
+	if name != "ollama/custom-embedder" || dim != 512 {
+		t.Errorf("got %s (%d), want ollama/custom-embedder (512)", name, dim)
+	}
+
+	if _, dim, _ = EmbeddingInfo(context.Background(), NewOllama(srv.URL, Models{Embedding: "custom-embedder"})); dim != 512 || probes != 1 {
+		t.Errorf("second call: dimension %d after %d probes, want 512 after 1", dim, probes)
+	}
+}
+
+func TestCompletionRequest_HistoryOrder(t *testing.T) {
+	req := CompletionRequest{
+		SystemPrompt: "sys",
+		Context:      "ctx",
+		History: []Message{
+			{Role: RoleUser, Content: "first question"},
+			{Role: RoleAssistant, Content: "first answer"},
+		},
+		UserMessage: "follow-up",
+	}
+
+	claude := claudeMessages(req)
+	if len(claude) != 3 || claude[1].Role != "assistant" || claude[1].Content[0].GetText() != "first answer" {
+		t.Errorf("claude messages: %+v", claude)
+	}
+	if last := claude[2].Content[0].GetText(); !strings.Contains(last, "<context>\nctx\n</context>") || !strings.HasSuffix(last, "follow-up") {
+		t.Errorf("claude: context should be attached to the new message, got %q", last)
+	}
+	if strings.Contains(claude[0].Content[0].GetText(), "ctx") {
+		t.Error("claude: context should not be attached to the history")
+	}
+
+	oa := openaiMessages(req)
+	var roles []string
+	for _, m := range oa {
+		roles = append(roles, m.Role)
+	}
+	if got := strings.Join(roles, ","); got != "system,system,user,assistant,user" || oa[4].Content != "follow-up" {
+		t.Errorf("openai roles: %s", got)
+	}
+
+	gem := geminiContents(req)
+	if len(gem) != 3 || gem[0].Role != "user" || gem[1].Role != "model" || gem[2].Parts[0].Text != "follow-up" {
+		t.Errorf("gemini contents: %+v", gem)
+	}
+
+	roles = nil
+	for _, m := range ollamaMessages(req) {
+		roles = append(roles, m.Role)
+	}
+	if got := strings.Join(roles, ","); got != "system,system,user,assistant,user" {
+		t.Errorf("ollama roles: %s", got)
+	}
+}
+
+func TestOllamaComplete_SendsHistory(t *testing.T) {
+	var got ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"ok"},"done":true}`)
+	}))
+	defer server.Close()
+
+	a := NewOllama(server.URL, Models{Embedding: "nomic-embed-text"})
+	ch, err := a.Complete(context.Background(), CompletionRequest{
+		Model:       "llama3.2",
+		History:     []Message{{Role: RoleUser, Content: "hi"}, {Role: RoleAssistant, Content: "hello"}},
+		UserMessage: "and then?",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range ch {
+		if chunk.Error != nil {
+			t.Fatal(chunk.Error)
+		}
+	}
+	if len(got.Messages) != 3 || got.Messages[1].Content != "hello" || got.Messages[2].Content != "and then?" {
+		t.Errorf("sent messages: %+v", got.Messages)
+	}
+}
+
+func TestCompletionRequest_ToolSteps(t *testing.T) {
+	req := CompletionRequest{
+		UserMessage: "what does main do?",
+		Tools:       []Tool{{Name: "read_file", Description: "Read a file", Parameters: json.RawMessage(`{"type":"object"}`)}},
+		ToolSteps: []Message{
+			{Role: RoleAssistant, Content: "Let me look.", ToolCalls: []ToolCall{
+				{ID: "a", Name: "read_file", Arguments: json.RawMessage(`{"path":"main.go"}`)},
+				{ID: "b", Name: "git_diff"},
+			}},
+			{Role: RoleTool, ToolCallID: "a", Content: "package main"},
+			{Role: RoleTool, ToolCallID: "b", Content: "(no changes)"},
+		},
+	}
+
+	claude := claudeMessages(req)
+	if len(claude) != 3 || len(claude[1].Content) != 3 || claude[1].Content[2].MessageContentToolUse.ID != "b" ||
+		string(claude[1].Content[2].MessageContentToolUse.Input) != "{}" {
+		t.Errorf("claude tool calls: %+v", claude)
+	}
+	if len(claude[2].Content) != 2 || *claude[2].Content[1].ToolUseID != "b" {
+		t.Errorf("claude: results of one round should share a user message: %+v", claude[2])
+	}
+
+	oa := openaiMessages(req)
+	if len(oa) != 4 || len(oa[1].ToolCalls) != 2 || oa[2].ToolCallID != "a" || oa[3].Role != "tool" {
+		t.Errorf("openai messages: %+v", oa)
+	}
+
+	gem := geminiContents(req)
+	if len(gem) != 3 || gem[1].Parts[1].FunctionCall.Name != "read_file" || len(gem[2].Parts) != 2 ||
+		gem[2].Parts[1].FunctionResponse.Name != "git_diff" {
+		t.Errorf("gemini contents: %+v", gem)
+	}
+
+	ol := ollamaMessages(req)
+	if len(ol) != 4 || len(ol[1].ToolCalls) != 2 || ol[2].ToolName != "read_file" || ol[3].ToolName != "git_diff" {
+		t.Errorf("ollama messages: %+v", ol)
+	}
+}
+
+func TestOpenAIToolCallDeltas(t *testing.T) {
+	zero, one := 0, 1
+	var calls []openai.ToolCall
+	calls = mergeOpenAIToolCallDeltas(calls, []openai.ToolCall{{Index: &zero, ID: "c1", Function: openai.FunctionCall{Name: "read_file", Arguments: `{"pa`}}})
+	calls = mergeOpenAIToolCallDeltas(calls, []openai.ToolCall{{Index: &zero, Function: openai.FunctionCall{Arguments: `th":"a.go"}`}}})
+	calls = mergeOpenAIToolCallDeltas(calls, []openai.ToolCall{{Index: &one, ID: "c2", Function: openai.FunctionCall{Name: "git_diff"}}})
+
+	got := openaiToolCalls(calls)
+	if len(got) != 2 || got[0].ID != "c1" || string(got[0].Arguments) != `{"path":"a.go"}` || string(got[1].Arguments) != "{}" {
+		t.Errorf("assembled calls: %+v", got)
+	}
+
+	// Without indexes, fragments continue the last call until one brings
+	// a new ID.
+	calls = nil
+	calls = mergeOpenAIToolCallDeltas(calls, []openai.ToolCall{{ID: "c1", Function: openai.FunctionCall{Name: "read_file", Arguments: `{"pa`}}})
+	calls = mergeOpenAIToolCallDeltas(calls, []openai.ToolCall{{Function: openai.FunctionCall{Arguments: `th":"a.go"}`}}})
+	calls = mergeOpenAIToolCallDeltas(calls, []openai.ToolCall{{ID: "c2", Function: openai.FunctionCall{Name: "git_diff"}}})
+	got = openaiToolCalls(calls)
+	if len(got) != 2 || got[0].ID != "c1" || string(got[0].Arguments) != `{"path":"a.go"}` || got[1].Name != "git_diff" {
+		t.Errorf("assembled calls without indexes: %+v", got)
+	}
+}
+
+func TestOllamaComplete_ToolCalls(t *testing.T) {
+	var got ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"read_file","arguments":{"path":"a.go"}}}]},"done":false}`)
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":""},"done":true}`)
+	}))
+	defer server.Close()
+
+	a := NewOllama(server.URL, Models{Embedding: "nomic-embed-text"})
+	ch, err := a.Complete(context.Background(), CompletionRequest{
+		Model:       "llama3.2",
+		UserMessage: "read a.go",
+		Tools:       []Tool{{Name: "read_file", Parameters: json.RawMessage(`{"type":"object"}`)}},
+		Stream:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []ToolCall
+	for chunk := range ch {
+		if chunk.Error != nil {
+			t.Fatal(chunk.Error)
+		}
+		calls = append(calls, chunk.ToolCalls...)
+	}
+	if len(got.Tools) != 1 || got.Tools[0].Type != "function" || got.Tools[0].Function.Name != "read_file" {
+		t.Errorf("sent tools: %+v", got.Tools)
+	}
+	if len(calls) != 1 || calls[0].ID == "" || calls[0].Name != "read_file" || string(calls[0].Arguments) != `{"path":"a.go"}` {
+		t.Errorf("tool calls: %+v", calls)
+	}
+}
+
+func TestOllamaComplete_ModelWithoutTools(t *testing.T) {
+	var requests []ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		if len(req.Tools) > 0 {
+			http.Error(w, `{"error":"registry.ollama.ai/library/gemma:latest does not support tools"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"plain answer"},"done":true}`)
+	}))
+	defer server.Close()
+
+	ch, _ := NewOllama(server.URL, Models{}).Complete(context.Background(), CompletionRequest{
+		Model:       "gemma",
+		UserMessage: "hi",
+		Tools:       []Tool{{Name: "read_file"}},
+	})
+	var text string
+	for chunk := range ch {
+		if chunk.Error != nil {
+			t.Fatal(chunk.Error)
+		}
+		text += chunk.Text
+	}
+	if text != "plain answer" || len(requests) != 2 {
+		t.Errorf("expected a retry without tools, got %q after %d requests", text, len(requests))
+	}
+}
+
+func TestOpenAICompatible_CompleteAndEmbed(t *testing.T) {
+	var paths, models, teams, auths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.Path)
+		models = append(models, body.Model)
+		teams = append(teams, r.Header.Get("X-Team"))
+		auths = append(auths, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"hello"}}]}`)
+		case "/v1/embeddings":
+			fmt.Fprint(w, `{"data":[{"embedding":[0.1,0.2,0.3]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	a, err := NewOpenAICompatible(OpenAICompatibleConfig{
+		BaseURL:         server.URL + "/v1/",
+		Headers:         map[string]string{"X-Team": "platform"},
+		CompletionModel: "qwen2.5-coder",
+		EmbeddingModel:  "bge-m3",
+	})
+	if err != nil {
+		t.Fatalf("NewOpenAICompatible: %v", err)
+	}
+
+	ch, err := a.Complete(context.Background(), CompletionRequest{UserMessage: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var text string
+	for chunk := range ch {
+		if chunk.Error != nil {
+			t.Fatal(chunk.Error)
+		}
+		text += chunk.Text
+	}
+	if text != "hello" {
+		t.Errorf("response = %q", text)
+	}
+
+	vecs, err := a.Embed(context.Background(), []string{"x"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != 1 || len(vecs[0]) != 3 {
+		t.Errorf("embeddings = %v", vecs)
+	}
+
+	wantPaths := []string{"/v1/chat/completions", "/v1/embeddings"}
+	wantModels := []string{"qwen2.5-coder", "bge-m3"}
+	for i := range wantPaths {
+		if paths[i] != wantPaths[i] || models[i] != wantModels[i] {
+			t.Errorf("request %d: %s model %q, want %s model %q", i, paths[i], models[i], wantPaths[i], wantModels[i])
+		}
+		if teams[i] != "platform" {
+			t.Errorf("request %d: X-Team = %q", i, teams[i])
+		}
+		if auths[i] != "" {
+			t.Errorf("request %d: Authorization = %q, want none without an API key", i, auths[i])
+		}
+	}
+
+	info := a.Info()
+	if info.Provider != ProviderOpenAICompatible || info.Name != "qwen2.5-coder" || info.EmbeddingModel != "bge-m3" {
+		t.Errorf("Info() = %+v", info)
+	}
+}
+
+func TestOpenAICompatible_MissingModel(t *testing.T) {
+	a, err := NewOpenAICompatible(OpenAICompatibleConfig{BaseURL: "http://localhost:1/v1", EmbeddingModel: "bge-m3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Complete(context.Background(), CompletionRequest{UserMessage: "hi"}); err == nil {
+		t.Error("expected an error completing without a completion model")
+	}
+	if _, err := NewOpenAICompatible(OpenAICompatibleConfig{BaseURL: "http://localhost:1/v1"}); err == nil {
+		t.Error("expected an error for a profile without models")
+	}
+	if _, err := NewOpenAICompatible(OpenAICompatibleConfig{CompletionModel: "m"}); err == nil {
+		t.Error("expected an error without a base URL")
+	}
+}
+
+func TestFromConfig_Profiles(t *testing.T) {
+	cfg := config.DefaultGlobal()
+	cfg.Providers = map[string]config.ProviderConfig{
+		"lmstudio": {Type: ProviderOpenAICompatible, BaseURL: "http://localhost:1234/v1", CompletionModel: "qwen2.5-coder"},
+		"broken":   {Type: "anthropic-compatible", BaseURL: "http://localhost:1/v1"},
+	}
+
+	a, err := FromConfig(cfg, "lmstudio")
+	if err != nil {
+		t.Fatalf("FromConfig(lmstudio): %v", err)
+	}
+	if a.Info().Provider != ProviderOpenAICompatible {
+		t.Errorf("provider = %q", a.Info().Provider)
+	}
+	if _, err := FromConfig(cfg, "broken"); err == nil || !strings.Contains(err.Error(), "unknown type") {
+		t.Errorf("expected an unknown type error, got %v", err)
+	}
+	if _, err := FromConfig(cfg, "nope"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+	if a, err := FromConfig(cfg, ProviderOllama); err != nil || a.Info().EmbeddingModel != cfg.Ollama.EmbedModel {
+		t.Errorf("FromConfig(ollama) = %v, %v", a, err)
+	}
+}
+
+func TestOllamaComplete_DefaultsToCompletionModel(t *testing.T) {
+	var got ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"ok"},"done":true}`)
+	}))
+	defer server.Close()
+
+	a := NewOllama(server.URL, Models{Completion: "qwen2.5-coder", Embedding: "nomic-embed-text"})
+	ch, err := a.Complete(context.Background(), CompletionRequest{UserMessage: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range ch {
+	}
+	if got.Model != "qwen2.5-coder" {
+		t.Errorf("model = %q, want the completion model", got.Model)
+	}
+}
+
+func TestLookupModelInfo(t *testing.T) {
+	tests := []struct {
+		provider  string
+		models    Models
+		name      string
+		window    int
+		embedding string
+		dimension int
+	}{
+		{ProviderOpenAI, Models{}, "gpt-4o", 128000, "text-embedding-3-small", 1536},
+		{ProviderOpenAI, Models{Completion: "gpt-4.1-mini", Embedding: "text-embedding-3-large"}, "gpt-4.1-mini", 1047576, "text-embedding-3-large", 3072},
+		{ProviderClaude, Models{Completion: "claude-opus-4-1"}, "claude-opus-4-1", 200000, "", 0},
+		{ProviderGemini, Models{Completion: "gemini-1.5-pro"}, "gemini-1.5-pro", 2097152, "text-embedding-004", 768},
+		{ProviderOllama, Models{Embedding: "mxbai-embed-large:latest"}, "llama3.2", 32768, "mxbai-embed-large:latest", 1024},
+		{ProviderOllama, Models{Embedding: "my-embedder"}, "llama3.2", 32768, "my-embedder", 0},
+	}
+	for _, tt := range tests {
+		info := LookupModelInfo(tt.provider, tt.models)
+		if info.Name != tt.name || info.MaxContextWindow != tt.window || info.EmbeddingModel != tt.embedding || info.EmbeddingDimension != tt.dimension {
+			t.Errorf("LookupModelInfo(%s, %+v) = %+v", tt.provider, tt.models, info)
+		}
+	}
+}
+
+func TestWithModel(t *testing.T) {
+	var got ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"ok"},"done":true}`)
+	}))
+	defer server.Close()
+
+	base := NewOllama(server.URL, Models{})
+	if WithModel(base, "") != base {
+		t.Error("WithModel with no model should return the adapter unchanged")
+	}
+	a := WithModel(base, "qwen2.5-coder:14b")
+	if info := a.Info(); info.Name != "qwen2.5-coder:14b" || info.Provider != ProviderOllama || info.EmbeddingModel != "nomic-embed-text" {
+		t.Errorf("Info() = %+v", info)
+	}
+	ch, err := a.Complete(context.Background(), CompletionRequest{UserMessage: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range ch {
+	}
+	if got.Model != "qwen2.5-coder:14b" {
+		t.Errorf("model = %q", got.Model)
+	}
+}
+
+func TestOpenAICompatible_ServerWithoutTools(t *testing.T) {
+	// vLLM without --enable-auto-tool-choice and llama.cpp without --jinja
+	// reject requests that offer tools, in their own error formats.
+	rejections := map[string]string{
+		"vllm":      `{"object":"error","message":"\"auto\" tool choice requires --enable-auto-tool-choice and --tool-call-parser to be set","type":"BadRequestError","code":400}`,
+		"llama.cpp": `{"error":{"code":400,"message":"tools param requires --jinja flag","type":"invalid_request_error"}}`,
+	}
+	for name, rejection := range rejections {
+		for _, stream := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/stream=%v", name, stream), func(t *testing.T) {
+				var withTools, withoutTools int
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var body struct {
+						Tools []any `json:"tools"`
+					}
+					json.NewDecoder(r.Body).Decode(&body)
+					if len(body.Tools) > 0 {
+						withTools++
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(http.StatusBadRequest)
+						fmt.Fprint(w, rejection)
+						return
+					}
+					withoutTools++
+					if stream {
+						w.Header().Set("Content-Type", "text/event-stream")
+						fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"plain answer\"}}]}\n\ndata: [DONE]\n\n")
+						return
+					}
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"plain answer"}}]}`)
+				}))
+				defer server.Close()
+
+				a, _ := NewOpenAICompatible(OpenAICompatibleConfig{BaseURL: server.URL + "/v1", CompletionModel: "m"})
+				ch, err := a.Complete(context.Background(), CompletionRequest{
+					UserMessage: "hi",
+					Tools:       []Tool{{Name: "read_file", Parameters: json.RawMessage(`{"type":"object"}`)}},
+					Stream:      stream,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var text string
+				for chunk := range ch {
+					if chunk.Error != nil {
+						t.Fatal(chunk.Error)
+					}
+					text += chunk.Text
+				}
+				if text != "plain answer" || withTools != 1 || withoutTools != 1 {
+					t.Errorf("got %q after %d requests with tools and %d without", text, withTools, withoutTools)
+				}
+			})
+		}
+	}
+}
+
+func TestOpenAICompatible_ToolsDisabled(t *testing.T) {
+	var sentTools bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Tools []any `json:"tools"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		sentTools = len(body.Tools) > 0
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`)
+	}))
+	defer server.Close()
+
+	off := false
+	cfg := config.DefaultGlobal()
+	cfg.Providers = map[string]config.ProviderConfig{
+		"llamacpp": {Type: ProviderOpenAICompatible, BaseURL: server.URL + "/v1", CompletionModel: "m", Tools: &off},
+	}
+	a, err := FromConfig(cfg, "llamacpp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, err := a.Complete(context.Background(), CompletionRequest{UserMessage: "hi", Tools: []Tool{{Name: "read_file"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range ch {
+	}
+	if sentTools {
+		t.Error("tools were offered to a profile with tools = false")
+	}
+}
+
+func TestOpenAICompatible_OtherBadRequest(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"object":"error","message":"maximum context length exceeded","type":"BadRequestError","code":400}`)
+	}))
+	defer server.Close()
+
+	a, _ := NewOpenAICompatible(OpenAICompatibleConfig{BaseURL: server.URL + "/v1", CompletionModel: "m"})
+	ch, _ := a.Complete(context.Background(), CompletionRequest{UserMessage: "hi", Tools: []Tool{{Name: "read_file"}}})
+	var err error
+	for chunk := range ch {
+		if chunk.Error != nil {
+			err = chunk.Error
+		}
+	}
+	if err == nil || !strings.Contains(err.Error(), "maximum context length") || requests != 1 {
+		t.Errorf("expected the server's error without a retry, got %v after %d requests", err, requests)
+	}
+}
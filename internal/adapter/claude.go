@@ -13,26 +13,24 @@ import (
 // claudeAdapter implements LLMAdapter for Anthropic Claude.
 type claudeAdapter struct {
 	client *anthropic.Client
+	info   ModelInfo
 }
 
-// NewClaude creates a Claude adapter. If apiKey is empty, ANTHROPIC_API_KEY is used.
-func NewClaude(apiKey string) LLMAdapter {
+// NewClaude creates a Claude adapter running models.Completion. If apiKey is
+// empty, ANTHROPIC_API_KEY is used.
+func NewClaude(apiKey string, models Models) LLMAdapter {
 	if apiKey == "" {
 		apiKey = os.Getenv("ANTHROPIC_API_KEY")
 	}
+	models.Embedding = "" // Claude does not provide embeddings
 	return &claudeAdapter{
 		client: anthropic.NewClient(apiKey),
+		info:   LookupModelInfo(ProviderClaude, models),
 	}
 }
 
 func (c *claudeAdapter) Info() ModelInfo {
-	return ModelInfo{
-		Name:               "claude-sonnet-4-6",
-		Provider:           ProviderClaude,
-		MaxContextWindow:   200000,
-		SupportsStreaming:  true,
-		EmbeddingDimension: 0, // Claude does not provide embeddings
-	}
+	return c.info
 }
 
 func (c *claudeAdapter) Embed(_ context.Context, _ []string) ([][]float32, error) {
@@ -42,13 +40,7 @@ func (c *claudeAdapter) Embed(_ context.Context, _ []string) ([][]float32, error
 func (c *claudeAdapter) Complete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
 	model := req.Model
 	if model == "" {
-		model = "claude-sonnet-4-6"
-	}
-
-	// Build the user message, prepending the injected context if present.
-	userContent := req.UserMessage
-	if req.Context != "" {
-		userContent = fmt.Sprintf("<context>\n%s\n</context>\n\n%s", req.Context, req.UserMessage)
+		model = c.info.Name
 	}
 
 	maxTokens := req.MaxTokens
@@ -56,12 +48,8 @@ func (c *claudeAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 		maxTokens = 4096
 	}
 
-	messages := []anthropic.Message{
-		{
-			Role:    anthropic.RoleUser,
-			Content: []anthropic.MessageContent{anthropic.NewTextMessageContent(userContent)},
-		},
-	}
+	messages := claudeMessages(req)
+	tools := claudeTools(req.Tools)
 
 	ch := make(chan StreamChunk, 64)
 
@@ -74,13 +62,21 @@ func (c *claudeAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 				Messages:  messages,
 				MaxTokens: maxTokens,
 				System:    req.SystemPrompt,
+				Tools:     tools,
 			})
 			if err != nil {
 				ch <- StreamChunk{Error: fmt.Errorf("claude complete: %w", err)}
 				return
 			}
-			if len(resp.Content) > 0 {
-				ch <- StreamChunk{Text: resp.Content[0].GetText()}
+			var text string
+			for _, c := range resp.Content {
+				text += c.GetText()
+			}
+			if text != "" {
+				ch <- StreamChunk{Text: text}
+			}
+			if calls := claudeToolCalls(resp.Content); len(calls) > 0 {
+				ch <- StreamChunk{ToolCalls: calls}
 			}
 		}()
 		return ch, nil
@@ -96,6 +92,7 @@ func (c *claudeAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 				Messages:  messages,
 				MaxTokens: maxTokens,
 				System:    req.SystemPrompt,
+				Tools:     tools,
 			},
 			OnContentBlockDelta: func(delta anthropic.MessagesEventContentBlockDeltaData) {
 				if delta.Delta.Type == anthropic.MessagesContentTypeTextDelta {
@@ -104,11 +101,91 @@ func (c *claudeAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 			},
 		}
 
-		_, err := c.client.CreateMessagesStream(ctx, streamReq)
+		resp, err := c.client.CreateMessagesStream(ctx, streamReq)
 		if err != nil && !errors.Is(err, io.EOF) {
 			ch <- StreamChunk{Error: fmt.Errorf("claude stream: %w", err)}
+			return
+		}
+		// The library assembles tool_use blocks from the streamed deltas.
+		if calls := claudeToolCalls(resp.Content); len(calls) > 0 {
+			ch <- StreamChunk{ToolCalls: calls}
 		}
 	}()
 
 	return ch, nil
 }
+
+// claudeMessages builds the conversation for req: the history, the new user
+// message with the injected context prepended, then the tool steps. The
+// results of one round of tool calls go in a single user message.
+func claudeMessages(req CompletionRequest) []anthropic.Message {
+	messages := make([]anthropic.Message, 0, len(req.History)+1)
+	for _, m := range req.History {
+		role := anthropic.RoleUser
+		if m.Role == RoleAssistant {
+			role = anthropic.RoleAssistant
+		}
+		messages = append(messages, anthropic.Message{
+			Role:    role,
+			Content: []anthropic.MessageContent{anthropic.NewTextMessageContent(m.Content)},
+		})
+	}
+
+	userContent := req.UserMessage
+	if req.Context != "" {
+		userContent = fmt.Sprintf("<context>\n%s\n</context>\n\n%s", req.Context, req.UserMessage)
+	}
+	messages = append(messages, anthropic.Message{
+		Role:    anthropic.RoleUser,
+		Content: []anthropic.MessageContent{anthropic.NewTextMessageContent(userContent)},
+	})
+
+	for _, m := range req.ToolSteps {
+		if m.Role == RoleTool {
+			result := anthropic.NewToolResultMessageContent(m.ToolCallID, m.Content, false)
+			if last := &messages[len(messages)-1]; last.Role == anthropic.RoleUser && len(last.Content) > 0 &&
+				last.Content[0].Type == anthropic.MessagesContentTypeToolResult {
+				last.Content = append(last.Content, result)
+			} else {
+				messages = append(messages, anthropic.Message{Role: anthropic.RoleUser, Content: []anthropic.MessageContent{result}})
+			}
+			continue
+		}
+		var content []anthropic.MessageContent
+		if m.Content != "" {
+			content = append(content, anthropic.NewTextMessageContent(m.Content))
+		}
+		for _, c := range m.ToolCalls {
+			content = append(content, anthropic.NewToolUseMessageContent(c.ID, c.Name, toolArguments(c.Arguments)))
+		}
+		messages = append(messages, anthropic.Message{Role: anthropic.RoleAssistant, Content: content})
+	}
+	return messages
+}
+
+// claudeTools converts tools to Anthropic tool definitions.
+func claudeTools(tools []Tool) []anthropic.ToolDefinition {
+	if len(tools) == 0 {
+		return nil
+	}
+	defs := make([]anthropic.ToolDefinition, len(tools))
+	for i, t := range tools {
+		defs[i] = anthropic.ToolDefinition{Name: t.Name, Description: t.Description, InputSchema: t.Parameters}
+	}
+	return defs
+}
+
+// claudeToolCalls returns the tool_use blocks of a response.
+func claudeToolCalls(content []anthropic.MessageContent) []ToolCall {
+	var calls []ToolCall
+	for _, c := range content {
+		if c.Type == anthropic.MessagesContentTypeToolUse && c.MessageContentToolUse != nil {
+			calls = append(calls, ToolCall{
+				ID:        c.MessageContentToolUse.ID,
+				Name:      c.MessageContentToolUse.Name,
+				Arguments: toolArguments(c.MessageContentToolUse.Input),
+			})
+		}
+	}
+	return calls
+}
@@ -0,0 +1,138 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// defaultCompatibleContextWindow is assumed for OpenAI-compatible models
+// whose context window isn't configured.
+const defaultCompatibleContextWindow = 32768
+
+// OpenAICompatibleConfig describes a server that speaks the OpenAI chat
+// completions and embeddings API, such as LM Studio, vLLM, the llama.cpp
+// server or a LiteLLM gateway.
+type OpenAICompatibleConfig struct {
+	// BaseURL is the API root, usually ending in /v1
+	// (e.g. "http://localhost:1234/v1").
+	BaseURL string
+	// APIKey is sent as a bearer token; servers that need none may leave
+	// it empty.
+	APIKey string
+	// Headers are added to every request, e.g. for gateway routing or
+	// authentication schemes other than a bearer token.
+	Headers map[string]string
+	// CompletionModel is used when a request names no model. Without one,
+	// only Embed works.
+	CompletionModel string
+	// EmbeddingModel is used by Embed. Without one, only Complete works.
+	EmbeddingModel string
+	// EmbeddingDimension is the size of EmbeddingModel's vectors; 0 means
+	// it is looked up for known models and otherwise measured when needed.
+	EmbeddingDimension int
+	// ContextWindow is CompletionModel's context size in tokens; 0 means it
+	// is looked up for known models and otherwise taken to be 32768.
+	ContextWindow int
+	// NoTools stops tools being offered to the model, for servers that
+	// reject them in a way that isn't recognised.
+	NoTools bool
+}
+
+// NewOpenAICompatible creates an adapter for an OpenAI-compatible server.
+func NewOpenAICompatible(cfg OpenAICompatibleConfig) (LLMAdapter, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("adapter: %s provider needs a base URL", ProviderOpenAICompatible)
+	}
+	if cfg.CompletionModel == "" && cfg.EmbeddingModel == "" {
+		return nil, fmt.Errorf("adapter: %s provider needs a completion or embedding model", ProviderOpenAICompatible)
+	}
+
+	clientCfg := openai.DefaultConfig(cfg.APIKey)
+	clientCfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	clientCfg.HTTPClient = &http.Client{
+		Transport: &headerTransport{headers: cfg.Headers, noAuth: cfg.APIKey == ""},
+	}
+
+	info := LookupModelInfo(ProviderOpenAICompatible, Models{
+		Completion: cfg.CompletionModel,
+		Embedding:  cfg.EmbeddingModel,
+	})
+	if cfg.ContextWindow > 0 {
+		info.MaxContextWindow = cfg.ContextWindow
+	}
+	if cfg.EmbeddingDimension > 0 {
+		info.EmbeddingDimension = cfg.EmbeddingDimension
+	}
+	return &openaiAdapter{
+		client:  openai.NewClientWithConfig(clientCfg),
+		info:    info,
+		noTools: cfg.NoTools,
+	}, nil
+}
+
+// headerTransport adds configured headers to each request. With noAuth it
+// drops the empty bearer token the OpenAI client always sends, which some
+// servers reject. Error bodies are rewritten into OpenAI's shape, so the
+// client keeps their message.
+type headerTransport struct {
+	headers map[string]string
+	noAuth  bool
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.noAuth {
+		req.Header.Del("Authorization")
+	}
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(normalizeErrorBody(body)))
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// normalizeErrorBody wraps an error body that has its message at the top
+// level, as vLLM's {"object":"error","message":...} and FastAPI's
+// {"detail":...} do, in the {"error":{"message":...}} object OpenAI uses.
+// Other bodies are returned unchanged.
+func normalizeErrorBody(body []byte) []byte {
+	var top map[string]json.RawMessage
+	if json.Unmarshal(body, &top) != nil {
+		return body
+	}
+	if _, ok := top["error"]; ok {
+		return body
+	}
+	var message, errType string
+	for _, key := range []string{"message", "detail"} {
+		if json.Unmarshal(top[key], &message) == nil && message != "" {
+			break
+		}
+	}
+	if message == "" {
+		return body
+	}
+	_ = json.Unmarshal(top["type"], &errType)
+	out, err := json.Marshal(map[string]any{"error": map[string]string{"message": message, "type": errType}})
+	if err != nil {
+		return body
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/memvra/memvra/internal/config"
+)
+
+// FromConfig constructs the adapter for provider, which names a built-in
+// provider or a [providers.<name>] profile in cfg, running the completion
+// and embedding models configured in [models.<provider>].
+func FromConfig(cfg config.GlobalConfig, provider string) (LLMAdapter, error) {
+	m := cfg.ModelsFor(provider)
+	models := Models{Completion: m.Completion, Embedding: m.Embedding}
+	if p, ok := cfg.Providers[provider]; ok {
+		return fromProfile(provider, p, models)
+	}
+	switch provider {
+	case ProviderClaude:
+		return New(provider, models, cfg.Keys.Anthropic, "")
+	case ProviderOpenAI:
+		return New(provider, models, cfg.Keys.OpenAI, "")
+	case ProviderGemini:
+		return New(provider, models, cfg.Keys.Gemini, "")
+	case ProviderOllama:
+		return New(provider, models, "", cfg.Ollama.Host)
+	default:
+		return nil, fmt.Errorf("adapter: unknown provider %q; valid providers: claude, openai, gemini, ollama, or a [providers.<name>] profile", provider)
+	}
+}
+
+// ConflictChecker returns the default completion model for confirming that
+// a new memory contradicts a similar one, or nil if dedupe.llm_check is off
+// or the model can't be constructed.
+func ConflictChecker(cfg config.GlobalConfig) LLMAdapter {
+	if !cfg.Dedupe.LLMCheck {
+		return nil
+	}
+	llm, err := FromConfig(cfg, cfg.DefaultModel)
+	if err != nil {
+		return nil
+	}
+	return llm
+}
+
+// fromProfile constructs the adapter for the profile called name. Models
+// set in [models.<name>] take precedence over the profile's own.
+func fromProfile(name string, p config.ProviderConfig, models Models) (LLMAdapter, error) {
+	if models.Completion != "" {
+		p.CompletionModel = models.Completion
+	}
+	if models.Embedding != "" {
+		p.EmbeddingModel = models.Embedding
+	}
+	switch p.Type {
+	case ProviderOpenAICompatible:
+		llm, err := NewOpenAICompatible(OpenAICompatibleConfig{
+			BaseURL:            p.BaseURL,
+			APIKey:             p.APIKey,
+			Headers:            p.Headers,
+			CompletionModel:    p.CompletionModel,
+			EmbeddingModel:     p.EmbeddingModel,
+			EmbeddingDimension: p.EmbeddingDimension,
+			ContextWindow:      p.ContextWindow,
+			NoTools:            p.Tools != nil && !*p.Tools,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w (profile %q)", err, name)
+		}
+		return llm, nil
+	case "":
+		return nil, fmt.Errorf("adapter: profile %q has no type; set type = %q", name, ProviderOpenAICompatible)
+	default:
+		return nil, fmt.Errorf("adapter: profile %q has unknown type %q; valid types: %s", name, p.Type, ProviderOpenAICompatible)
+	}
+}
@@ -1,9 +1,51 @@
 package adapter
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sync"
+)
 
 // Embedder is a narrower interface for components that only need embedding,
 // not full chat completion. An LLMAdapter satisfies this interface.
 type Embedder interface {
 	Embed(ctx context.Context, texts []string) ([][]float32, error)
 }
+
+// EmbeddingInfo returns the name and vector dimension of the model behind e.
+// The name is qualified by provider (e.g. "ollama/nomic-embed-text"). When
+// the adapter doesn't report a dimension it is measured by embedding a short
+// probe text, once per model per process.
+func EmbeddingInfo(ctx context.Context, e Embedder) (name string, dimension int, err error) {
+	name = "custom"
+	if a, ok := e.(interface{ Info() ModelInfo }); ok {
+		info := a.Info()
+		name = info.Provider + "/" + info.EmbeddingModel
+		dimension = info.EmbeddingDimension
+	}
+	if dimension > 0 {
+		return name, dimension, nil
+	}
+	if name != "custom" {
+		if dim, ok := probedDimensions.Load(name); ok {
+			return name, dim.(int), nil
+		}
+	}
+
+	vecs, err := e.Embed(ctx, []string{"dimension probe"})
+	if err != nil {
+		return name, 0, fmt.Errorf("probe embedding dimension: %w", err)
+	}
+	if len(vecs) == 0 || len(vecs[0]) == 0 {
+		return name, 0, fmt.Errorf("probe embedding dimension: empty embedding")
+	}
+	if name != "custom" {
+		probedDimensions.Store(name, len(vecs[0]))
+	}
+	return name, len(vecs[0]), nil
+}
+
+// probedDimensions caches measured dimensions by qualified model name.
+// Embedders without a name ("custom") are probed every time, since two of
+// them needn't share a model.
+var probedDimensions sync.Map
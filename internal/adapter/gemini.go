@@ -16,27 +16,24 @@ import (
 type geminiAdapter struct {
 	apiKey string
 	client *http.Client
+	info   ModelInfo
 }
 
-// NewGemini creates a Gemini adapter. If apiKey is empty, GEMINI_API_KEY is used.
-func NewGemini(apiKey string) LLMAdapter {
+// NewGemini creates a Gemini adapter running the given models. If apiKey is
+// empty, GEMINI_API_KEY is used.
+func NewGemini(apiKey string, models Models) LLMAdapter {
 	if apiKey == "" {
 		apiKey = os.Getenv("GEMINI_API_KEY")
 	}
 	return &geminiAdapter{
 		apiKey: apiKey,
 		client: &http.Client{},
+		info:   LookupModelInfo(ProviderGemini, models),
 	}
 }
 
 func (g *geminiAdapter) Info() ModelInfo {
-	return ModelInfo{
-		Name:               "gemini-2.0-flash",
-		Provider:           ProviderGemini,
-		MaxContextWindow:   1000000,
-		SupportsStreaming:   true,
-		EmbeddingDimension: 768, // text-embedding-004
-	}
+	return g.info
 }
 
 // ---------- Embedding types ----------
@@ -65,7 +62,7 @@ func (g *geminiAdapter) Embed(ctx context.Context, texts []string) ([][]float32,
 		return nil, nil
 	}
 
-	const model = "text-embedding-004"
+	model := g.info.EmbeddingModel
 	baseURL := fmt.Sprintf(
 		"https://generativelanguage.googleapis.com/v1beta/models/%s:embedContent?key=%s",
 		model, g.apiKey,
@@ -116,6 +113,7 @@ type geminiGenerateRequest struct {
 	Contents         []geminiContent         `json:"contents"`
 	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
 	GenerationConfig *geminiGenerationConfig `json:"generationConfig,omitempty"`
+	Tools            []geminiTool            `json:"tools,omitempty"`
 }
 
 type geminiContent struct {
@@ -124,7 +122,34 @@ type geminiContent struct {
 }
 
 type geminiPart struct {
-	Text string `json:"text"`
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	Name     string                `json:"name"`
+	Response geminiFunctionContent `json:"response"`
+}
+
+// geminiFunctionContent wraps a tool result, since Gemini wants an object.
+type geminiFunctionContent struct {
+	Content string `json:"content"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
 }
 
 type geminiGenerationConfig struct {
@@ -150,7 +175,7 @@ type geminiError struct {
 func (g *geminiAdapter) Complete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
 	model := req.Model
 	if model == "" {
-		model = "gemini-2.0-flash"
+		model = g.info.Name
 	}
 
 	maxTokens := req.MaxTokens
@@ -171,17 +196,13 @@ func (g *geminiAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 	}
 
 	genReq := geminiGenerateRequest{
-		Contents: []geminiContent{
-			{
-				Role:  "user",
-				Parts: []geminiPart{{Text: req.UserMessage}},
-			},
-		},
+		Contents:          geminiContents(req),
 		SystemInstruction: sysInstruction,
 		GenerationConfig: &geminiGenerationConfig{
 			MaxOutputTokens: maxTokens,
 			Temperature:     req.Temperature,
 		},
+		Tools: geminiTools(req.Tools),
 	}
 
 	body, err := json.Marshal(genReq)
@@ -200,12 +221,17 @@ func (g *geminiAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 
 		go func() {
 			defer close(ch)
-			text, err := g.doGenerate(ctx, url, body)
+			text, calls, err := g.doGenerate(ctx, url, body)
 			if err != nil {
 				ch <- StreamChunk{Error: err}
 				return
 			}
-			ch <- StreamChunk{Text: text}
+			if text != "" {
+				ch <- StreamChunk{Text: text}
+			}
+			if len(calls) > 0 {
+				ch <- StreamChunk{ToolCalls: calls}
+			}
 		}()
 		return ch, nil
 	}
@@ -238,7 +264,9 @@ func (g *geminiAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 			return
 		}
 
-		// Gemini SSE: each event is "data: {json}\n\n".
+		// Gemini SSE: each event is "data: {json}\n\n". Function calls
+		// arrive whole and are passed on when the stream ends.
+		var calls []ToolCall
 		scanner := bufio.NewScanner(resp.Body)
 		for scanner.Scan() {
 			line := scanner.Text()
@@ -265,51 +293,123 @@ func (g *geminiAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 						ch <- StreamChunk{Text: part.Text}
 					}
 				}
+				calls = append(calls, geminiToolCalls(cand.Content.Parts)...)
 			}
 		}
 		if err := scanner.Err(); err != nil {
 			ch <- StreamChunk{Error: fmt.Errorf("gemini stream scan: %w", err)}
+			return
+		}
+		if len(calls) > 0 {
+			ch <- StreamChunk{ToolCalls: calls}
 		}
 	}()
 
 	return ch, nil
 }
 
-// doGenerate makes a non-streaming generateContent call and returns the text.
-func (g *geminiAdapter) doGenerate(ctx context.Context, url string, body []byte) (string, error) {
+// geminiContents builds the conversation for req: the history, the new user
+// message, then the tool steps. Gemini calls the assistant role "model" and
+// matches function responses to calls by name; the responses to one round
+// of calls go in a single user turn.
+func geminiContents(req CompletionRequest) []geminiContent {
+	contents := make([]geminiContent, 0, len(req.History)+1)
+	for _, m := range req.History {
+		role := "user"
+		if m.Role == RoleAssistant {
+			role = "model"
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: []geminiPart{{Text: m.Content}}})
+	}
+	contents = append(contents, geminiContent{Role: "user", Parts: []geminiPart{{Text: req.UserMessage}}})
+
+	names := toolCallNames(req.ToolSteps)
+	for _, m := range req.ToolSteps {
+		if m.Role == RoleTool {
+			part := geminiPart{FunctionResponse: &geminiFunctionResponse{
+				Name:     names[m.ToolCallID],
+				Response: geminiFunctionContent{Content: m.Content},
+			}}
+			if last := &contents[len(contents)-1]; last.Role == "user" && last.Parts[0].FunctionResponse != nil {
+				last.Parts = append(last.Parts, part)
+			} else {
+				contents = append(contents, geminiContent{Role: "user", Parts: []geminiPart{part}})
+			}
+			continue
+		}
+		var parts []geminiPart
+		if m.Content != "" {
+			parts = append(parts, geminiPart{Text: m.Content})
+		}
+		for _, c := range m.ToolCalls {
+			parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{Name: c.Name, Args: toolArguments(c.Arguments)}})
+		}
+		contents = append(contents, geminiContent{Role: "model", Parts: parts})
+	}
+	return contents
+}
+
+// geminiTools converts tools to Gemini function declarations.
+func geminiTools(tools []Tool) []geminiTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	decls := make([]geminiFunctionDeclaration, len(tools))
+	for i, t := range tools {
+		decls[i] = geminiFunctionDeclaration{Name: t.Name, Description: t.Description, Parameters: t.Parameters}
+	}
+	return []geminiTool{{FunctionDeclarations: decls}}
+}
+
+// geminiToolCalls returns the function calls among parts, with generated IDs.
+func geminiToolCalls(parts []geminiPart) []ToolCall {
+	var calls []ToolCall
+	for _, p := range parts {
+		if p.FunctionCall != nil {
+			calls = append(calls, ToolCall{ID: newToolCallID(), Name: p.FunctionCall.Name, Arguments: toolArguments(p.FunctionCall.Args)})
+		}
+	}
+	return calls
+}
+
+// doGenerate makes a non-streaming generateContent call and returns the text
+// and any function calls.
+func (g *geminiAdapter) doGenerate(ctx context.Context, url string, body []byte) (string, []ToolCall, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("gemini complete request: %w", err)
+		return "", nil, fmt.Errorf("gemini complete request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := g.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("gemini complete: %w", err)
+		return "", nil, fmt.Errorf("gemini complete: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("gemini complete: status %d: %s", resp.StatusCode, respBody)
+		return "", nil, fmt.Errorf("gemini complete: status %d: %s", resp.StatusCode, respBody)
 	}
 
 	var genResp geminiGenerateResponse
 	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
-		return "", fmt.Errorf("gemini complete decode: %w", err)
+		return "", nil, fmt.Errorf("gemini complete decode: %w", err)
 	}
 
 	if genResp.Error != nil {
-		return "", fmt.Errorf("gemini api error %d: %s", genResp.Error.Code, genResp.Error.Message)
+		return "", nil, fmt.Errorf("gemini api error %d: %s", genResp.Error.Code, genResp.Error.Message)
 	}
 
 	var parts []string
+	var calls []ToolCall
 	for _, cand := range genResp.Candidates {
 		for _, part := range cand.Content.Parts {
 			if part.Text != "" {
 				parts = append(parts, part.Text)
 			}
 		}
+		calls = append(calls, geminiToolCalls(cand.Content.Parts)...)
 	}
-	return strings.Join(parts, ""), nil
+	return strings.Join(parts, ""), calls, nil
 }
@@ -0,0 +1,150 @@
+package adapter
+
+import (
+	"context"
+	"strings"
+)
+
+// Models selects the models an adapter uses. Empty fields use the
+// provider's defaults.
+type Models struct {
+	Completion string
+	Embedding  string
+}
+
+// defaultModels are the models each built-in provider uses unless others
+// are configured.
+var defaultModels = map[string]Models{
+	ProviderClaude: {Completion: "claude-sonnet-4-6"},
+	ProviderOpenAI: {Completion: "gpt-4o", Embedding: "text-embedding-3-small"},
+	ProviderGemini: {Completion: "gemini-2.0-flash", Embedding: "text-embedding-004"},
+	ProviderOllama: {Completion: "llama3.2", Embedding: "nomic-embed-text"},
+}
+
+// withDefaults fills the empty fields of m with provider's default models.
+func (m Models) withDefaults(provider string) Models {
+	d := defaultModels[provider]
+	if m.Completion == "" {
+		m.Completion = d.Completion
+	}
+	if m.Embedding == "" {
+		m.Embedding = d.Embedding
+	}
+	return m
+}
+
+// providerContextWindows is the context size assumed for a provider's
+// completion models that aren't listed in contextWindows. Ollama runs
+// models with a much smaller context than they support unless told
+// otherwise, so its models are not listed.
+var providerContextWindows = map[string]int{
+	ProviderClaude:           200000,
+	ProviderOpenAI:           128000,
+	ProviderGemini:           1000000,
+	ProviderOllama:           32768,
+	ProviderOpenAICompatible: defaultCompatibleContextWindow,
+}
+
+// contextWindows lists the context sizes of known completion models by
+// name prefix. Longer prefixes come first so that they win.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4.1", 1047576},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo", 16385},
+	{"gpt-5", 400000},
+	{"o1", 200000},
+	{"o3", 200000},
+	{"o4-mini", 200000},
+	{"claude-", 200000},
+	{"gemini-1.5-pro", 2097152},
+	{"gemini-1.5-flash", 1048576},
+	{"gemini-2.0-flash", 1048576},
+	{"gemini-2.5", 1048576},
+}
+
+// embeddingDimensions lists the vector sizes of known embedding models.
+// Unknown models report 0 and are measured by EmbeddingInfo.
+var embeddingDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+	"text-embedding-004":     768,
+	"gemini-embedding-001":   3072,
+	"nomic-embed-text":       768,
+	"mxbai-embed-large":      1024,
+	"all-minilm":             384,
+	"snowflake-arctic-embed": 1024,
+	"bge-m3":                 1024,
+	"bge-large":              1024,
+}
+
+// LookupModelInfo describes provider running the given models, with empty
+// fields of models set to the provider's defaults. Context windows and
+// embedding dimensions come from tables of known models.
+func LookupModelInfo(provider string, models Models) ModelInfo {
+	models = models.withDefaults(provider)
+	return ModelInfo{
+		Name:               models.Completion,
+		Provider:           provider,
+		MaxContextWindow:   contextWindow(provider, models.Completion),
+		SupportsStreaming:  true,
+		EmbeddingDimension: embeddingDimensions[baseModelName(models.Embedding)],
+		EmbeddingModel:     models.Embedding,
+	}
+}
+
+// contextWindow returns the context size of provider's completion model.
+func contextWindow(provider, model string) int {
+	model = baseModelName(model)
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			return w.tokens
+		}
+	}
+	if n, ok := providerContextWindows[provider]; ok {
+		return n
+	}
+	return defaultCompatibleContextWindow
+}
+
+// baseModelName strips an Ollama tag such as ":latest" from a model name.
+func baseModelName(model string) string {
+	if i := strings.Index(model, ":"); i >= 0 {
+		return model[:i]
+	}
+	return model
+}
+
+// WithModel returns llm answering with model instead of its configured
+// completion model. An empty model returns llm unchanged.
+func WithModel(llm LLMAdapter, model string) LLMAdapter {
+	if model == "" || llm == nil {
+		return llm
+	}
+	return &modelOverride{LLMAdapter: llm, model: model}
+}
+
+// modelOverride is an adapter whose completion model has been replaced.
+type modelOverride struct {
+	LLMAdapter
+	model string
+}
+
+func (m *modelOverride) Complete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	if req.Model == "" {
+		req.Model = m.model
+	}
+	return m.LLMAdapter.Complete(ctx, req)
+}
+
+func (m *modelOverride) Info() ModelInfo {
+	info := m.LLMAdapter.Info()
+	info.Name = m.model
+	info.MaxContextWindow = contextWindow(info.Provider, m.model)
+	return info
+}
@@ -6,34 +6,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 )
 
 // ollamaAdapter implements LLMAdapter for a local Ollama instance.
 type ollamaAdapter struct {
-	host       string
-	embedModel string
-	client     *http.Client
+	host   string
+	client *http.Client
+	info   ModelInfo
 }
 
-// NewOllama creates an Ollama adapter.
-func NewOllama(host, embedModel string) LLMAdapter {
+// NewOllama creates an Ollama adapter for the server at host, running the
+// given models.
+func NewOllama(host string, models Models) LLMAdapter {
 	return &ollamaAdapter{
-		host:       strings.TrimRight(host, "/"),
-		embedModel: embedModel,
-		client:     &http.Client{},
+		host:   strings.TrimRight(host, "/"),
+		client: &http.Client{},
+		info:   LookupModelInfo(ProviderOllama, models),
 	}
 }
 
 func (o *ollamaAdapter) Info() ModelInfo {
-	return ModelInfo{
-		Name:               o.embedModel,
-		Provider:           ProviderOllama,
-		MaxContextWindow:   32768,
-		SupportsStreaming:  true,
-		EmbeddingDimension: 384,
-	}
+	return o.info
 }
 
 // ollamaEmbedRequest is the request body for the Ollama embed API.
@@ -53,7 +49,7 @@ func (o *ollamaAdapter) Embed(ctx context.Context, texts []string) ([][]float32,
 	}
 
 	body, err := json.Marshal(ollamaEmbedRequest{
-		Model: o.embedModel,
+		Model: o.info.EmbeddingModel,
 		Input: texts,
 	})
 	if err != nil {
@@ -91,11 +87,34 @@ type ollamaChatRequest struct {
 	Messages []ollamaChatMessage `json:"messages"`
 	Stream   bool                `json:"stream"`
 	Options  map[string]any      `json:"options,omitempty"`
+	Tools    []ollamaTool        `json:"tools,omitempty"`
 }
 
 type ollamaChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function ollamaFunctionCall `json:"function"`
+}
+
+type ollamaFunctionCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type ollamaTool struct {
+	Type     string             `json:"type"`
+	Function ollamaFunctionSpec `json:"function"`
+}
+
+type ollamaFunctionSpec struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
 }
 
 // ollamaChatChunk is a single streamed response chunk.
@@ -107,61 +126,39 @@ type ollamaChatChunk struct {
 func (o *ollamaAdapter) Complete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
 	model := req.Model
 	if model == "" {
-		model = o.embedModel
-	}
-	if model == "" {
-		model = "llama3.2"
+		model = o.info.Name
 	}
 
-	messages := []ollamaChatMessage{}
-	if req.SystemPrompt != "" {
-		messages = append(messages, ollamaChatMessage{Role: "system", Content: req.SystemPrompt})
-	}
-	if req.Context != "" {
-		messages = append(messages, ollamaChatMessage{
-			Role:    "system",
-			Content: fmt.Sprintf("<context>\n%s\n</context>", req.Context),
-		})
-	}
-	messages = append(messages, ollamaChatMessage{Role: "user", Content: req.UserMessage})
-
-	body, err := json.Marshal(ollamaChatRequest{
+	chatReq := ollamaChatRequest{
 		Model:    model,
-		Messages: messages,
+		Messages: ollamaMessages(req),
 		Stream:   req.Stream,
 		Options: map[string]any{
 			"temperature": req.Temperature,
 			"num_predict": req.MaxTokens,
 		},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("ollama complete marshal: %w", err)
+		Tools: ollamaTools(req.Tools),
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		o.host+"/api/chat", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("ollama complete request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
 	ch := make(chan StreamChunk, 64)
 
 	go func() {
 		defer close(ch)
 
-		resp, err := o.client.Do(httpReq)
+		resp, err := o.chat(ctx, chatReq)
+		if err != nil && len(chatReq.Tools) > 0 && strings.Contains(err.Error(), "does not support tools") {
+			// Not every model supports tools; those answer without them.
+			chatReq.Tools = nil
+			resp, err = o.chat(ctx, chatReq)
+		}
 		if err != nil {
-			ch <- StreamChunk{Error: fmt.Errorf("ollama complete: %w", err)}
+			ch <- StreamChunk{Error: err}
 			return
 		}
 		defer func() { _ = resp.Body.Close() }()
 
-		if resp.StatusCode != http.StatusOK {
-			ch <- StreamChunk{Error: fmt.Errorf("ollama complete: status %d", resp.StatusCode)}
-			return
-		}
-
+		// Tool calls arrive whole and are passed on when the reply is done.
+		var calls []ToolCall
 		scanner := bufio.NewScanner(resp.Body)
 		for scanner.Scan() {
 			line := scanner.Bytes()
@@ -176,7 +173,13 @@ func (o *ollamaAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 			if chunk.Message.Content != "" {
 				ch <- StreamChunk{Text: chunk.Message.Content}
 			}
+			for _, c := range chunk.Message.ToolCalls {
+				calls = append(calls, ToolCall{ID: newToolCallID(), Name: c.Function.Name, Arguments: toolArguments(c.Function.Arguments)})
+			}
 			if chunk.Done {
+				if len(calls) > 0 {
+					ch <- StreamChunk{ToolCalls: calls}
+				}
 				return
 			}
 		}
@@ -187,3 +190,82 @@ func (o *ollamaAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 
 	return ch, nil
 }
+
+// chat posts chatReq to the chat API. Unless the error is nil, the response
+// has status 200 and its body must be closed by the caller.
+func (o *ollamaAdapter) chat(ctx context.Context, chatReq ollamaChatRequest) (*http.Response, error) {
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama complete marshal: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		o.host+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("ollama complete request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama complete: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("ollama complete: status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return resp, nil
+}
+
+// ollamaMessages builds the conversation for req: the system prompt and
+// context as system messages, the history, the new user message, then the
+// tool steps.
+func ollamaMessages(req CompletionRequest) []ollamaChatMessage {
+	messages := []ollamaChatMessage{}
+	if req.SystemPrompt != "" {
+		messages = append(messages, ollamaChatMessage{Role: "system", Content: req.SystemPrompt})
+	}
+	if req.Context != "" {
+		messages = append(messages, ollamaChatMessage{
+			Role:    "system",
+			Content: fmt.Sprintf("<context>\n%s\n</context>", req.Context),
+		})
+	}
+	for _, m := range req.History {
+		role := "user"
+		if m.Role == RoleAssistant {
+			role = "assistant"
+		}
+		messages = append(messages, ollamaChatMessage{Role: role, Content: m.Content})
+	}
+	messages = append(messages, ollamaChatMessage{Role: "user", Content: req.UserMessage})
+
+	names := toolCallNames(req.ToolSteps)
+	for _, m := range req.ToolSteps {
+		if m.Role == RoleTool {
+			messages = append(messages, ollamaChatMessage{Role: "tool", Content: m.Content, ToolName: names[m.ToolCallID]})
+			continue
+		}
+		msg := ollamaChatMessage{Role: "assistant", Content: m.Content}
+		for _, c := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, ollamaToolCall{Function: ollamaFunctionCall{Name: c.Name, Arguments: toolArguments(c.Arguments)}})
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// ollamaTools converts tools to Ollama function tools.
+func ollamaTools(tools []Tool) []ollamaTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	defs := make([]ollamaTool, len(tools))
+	for i, t := range tools {
+		defs[i] = ollamaTool{
+			Type:     "function",
+			Function: ollamaFunctionSpec{Name: t.Name, Description: t.Description, Parameters: t.Parameters},
+		}
+	}
+	return defs
+}
@@ -2,50 +2,57 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
 )
 
-// openaiAdapter implements LLMAdapter for OpenAI.
+// openaiAdapter implements LLMAdapter for OpenAI and OpenAI-compatible
+// servers.
 type openaiAdapter struct {
 	client *openai.Client
+	info   ModelInfo
+	// noTools drops the tools of every request, for servers that can't
+	// handle them.
+	noTools bool
 }
 
-// NewOpenAI creates an OpenAI adapter. If apiKey is empty, OPENAI_API_KEY is used.
-func NewOpenAI(apiKey string) LLMAdapter {
+// NewOpenAI creates an OpenAI adapter running the given models. If apiKey is
+// empty, OPENAI_API_KEY is used.
+func NewOpenAI(apiKey string, models Models) LLMAdapter {
 	if apiKey == "" {
 		apiKey = os.Getenv("OPENAI_API_KEY")
 	}
 	return &openaiAdapter{
 		client: openai.NewClient(apiKey),
+		info:   LookupModelInfo(ProviderOpenAI, models),
 	}
 }
 
 func (o *openaiAdapter) Info() ModelInfo {
-	return ModelInfo{
-		Name:               "gpt-4o",
-		Provider:           ProviderOpenAI,
-		MaxContextWindow:   128000,
-		SupportsStreaming:  true,
-		EmbeddingDimension: 1536, // text-embedding-3-small
-	}
+	return o.info
 }
 
 func (o *openaiAdapter) Embed(ctx context.Context, texts []string) ([][]float32, error) {
 	if len(texts) == 0 {
 		return nil, nil
 	}
+	if o.info.EmbeddingModel == "" {
+		return nil, fmt.Errorf("%s embed: no embedding model configured", o.info.Provider)
+	}
 
 	resp, err := o.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
 		Input: texts,
-		Model: openai.SmallEmbedding3,
+		Model: openai.EmbeddingModel(o.info.EmbeddingModel),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("openai embed: %w", err)
+		return nil, fmt.Errorf("%s embed: %w", o.info.Provider, err)
 	}
 
 	result := make([][]float32, len(resp.Data))
@@ -58,7 +65,10 @@ func (o *openaiAdapter) Embed(ctx context.Context, texts []string) ([][]float32,
 func (o *openaiAdapter) Complete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
 	model := req.Model
 	if model == "" {
-		model = "gpt-4o"
+		model = o.info.Name
+	}
+	if model == "" {
+		return nil, fmt.Errorf("%s complete: no completion model configured", o.info.Provider)
 	}
 
 	maxTokens := req.MaxTokens
@@ -66,75 +76,201 @@ func (o *openaiAdapter) Complete(ctx context.Context, req CompletionRequest) (<-
 		maxTokens = 4096
 	}
 
-	messages := []openai.ChatCompletionMessage{}
-	if req.SystemPrompt != "" {
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    openai.ChatMessageRoleSystem,
-			Content: req.SystemPrompt,
-		})
+	chatReq := openai.ChatCompletionRequest{
+		Model:       model,
+		Messages:    openaiMessages(req),
+		MaxTokens:   maxTokens,
+		Temperature: float32(req.Temperature),
+		Stream:      req.Stream,
 	}
-	if req.Context != "" {
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    openai.ChatMessageRoleSystem,
-			Content: fmt.Sprintf("<context>\n%s\n</context>", req.Context),
-		})
+	if !o.noTools {
+		chatReq.Tools = openaiTools(req.Tools)
 	}
-	messages = append(messages, openai.ChatCompletionMessage{
-		Role:    openai.ChatMessageRoleUser,
-		Content: req.UserMessage,
-	})
 
 	ch := make(chan StreamChunk, 64)
 
 	if !req.Stream {
 		go func() {
 			defer close(ch)
-			resp, err := o.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-				Model:       model,
-				Messages:    messages,
-				MaxTokens:   maxTokens,
-				Temperature: float32(req.Temperature),
-			})
+			resp, err := o.client.CreateChatCompletion(ctx, chatReq)
+			if err != nil && chatReq.Tools != nil && rejectsTools(err) {
+				// The server can't call tools; answer without them.
+				chatReq.Tools = nil
+				resp, err = o.client.CreateChatCompletion(ctx, chatReq)
+			}
 			if err != nil {
-				ch <- StreamChunk{Error: fmt.Errorf("openai complete: %w", err)}
+				ch <- StreamChunk{Error: fmt.Errorf("%s complete: %w", o.info.Provider, err)}
 				return
 			}
 			if len(resp.Choices) > 0 {
-				ch <- StreamChunk{Text: resp.Choices[0].Message.Content}
+				msg := resp.Choices[0].Message
+				if msg.Content != "" {
+					ch <- StreamChunk{Text: msg.Content}
+				}
+				if calls := openaiToolCalls(msg.ToolCalls); len(calls) > 0 {
+					ch <- StreamChunk{ToolCalls: calls}
+				}
 			}
 		}()
 		return ch, nil
 	}
 
-	stream, err := o.client.CreateChatCompletionStream(ctx, openai.ChatCompletionRequest{
-		Model:       model,
-		Messages:    messages,
-		MaxTokens:   maxTokens,
-		Temperature: float32(req.Temperature),
-		Stream:      true,
-	})
+	stream, err := o.client.CreateChatCompletionStream(ctx, chatReq)
+	if err != nil && chatReq.Tools != nil && rejectsTools(err) {
+		chatReq.Tools = nil
+		stream, err = o.client.CreateChatCompletionStream(ctx, chatReq)
+	}
 	if err != nil {
 		close(ch)
-		return nil, fmt.Errorf("openai stream: %w", err)
+		return nil, fmt.Errorf("%s stream: %w", o.info.Provider, err)
 	}
 
 	go func() {
 		defer close(ch)
 		defer func() { _ = stream.Close() }()
+		var toolCalls []openai.ToolCall
 		for {
 			resp, err := stream.Recv()
 			if errors.Is(err, io.EOF) {
+				if calls := openaiToolCalls(toolCalls); len(calls) > 0 {
+					ch <- StreamChunk{ToolCalls: calls}
+				}
 				return
 			}
 			if err != nil {
-				ch <- StreamChunk{Error: fmt.Errorf("openai stream recv: %w", err)}
+				ch <- StreamChunk{Error: fmt.Errorf("%s stream recv: %w", o.info.Provider, err)}
 				return
 			}
 			if len(resp.Choices) > 0 {
-				ch <- StreamChunk{Text: resp.Choices[0].Delta.Content}
+				delta := resp.Choices[0].Delta
+				if delta.Content != "" {
+					ch <- StreamChunk{Text: delta.Content}
+				}
+				toolCalls = mergeOpenAIToolCallDeltas(toolCalls, delta.ToolCalls)
 			}
 		}
 	}()
 
 	return ch, nil
 }
+
+// rejectsTools reports whether err is a server refusing a request because
+// it offered tools, as vLLM does without --enable-auto-tool-choice and the
+// llama.cpp server without --jinja.
+func rejectsTools(err error) bool {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(apiErr.Message), "tool")
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(reqErr.Error()), "tool")
+	}
+	return false
+}
+
+// openaiMessages builds the conversation for req: the system prompt and
+// context as system messages, the history, the new user message, then the
+// tool steps.
+func openaiMessages(req CompletionRequest) []openai.ChatCompletionMessage {
+	messages := []openai.ChatCompletionMessage{}
+	if req.SystemPrompt != "" {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: req.SystemPrompt,
+		})
+	}
+	if req.Context != "" {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: fmt.Sprintf("<context>\n%s\n</context>", req.Context),
+		})
+	}
+	for _, m := range req.History {
+		role := openai.ChatMessageRoleUser
+		if m.Role == RoleAssistant {
+			role = openai.ChatMessageRoleAssistant
+		}
+		messages = append(messages, openai.ChatCompletionMessage{Role: role, Content: m.Content})
+	}
+	messages = append(messages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: req.UserMessage,
+	})
+
+	for _, m := range req.ToolSteps {
+		if m.Role == RoleTool {
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    m.Content,
+				ToolCallID: m.ToolCallID,
+			})
+			continue
+		}
+		msg := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: m.Content}
+		for _, c := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, openai.ToolCall{
+				ID:       c.ID,
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: c.Name, Arguments: string(toolArguments(c.Arguments))},
+			})
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// openaiTools converts tools to OpenAI function tools.
+func openaiTools(tools []Tool) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	defs := make([]openai.Tool, len(tools))
+	for i, t := range tools {
+		defs[i] = openai.Tool{
+			Type:     openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{Name: t.Name, Description: t.Description, Parameters: t.Parameters},
+		}
+	}
+	return defs
+}
+
+// mergeOpenAIToolCallDeltas adds the tool call fragments of one streamed
+// chunk to calls. A call's ID and name arrive in its first fragment and its
+// arguments are spread over the following ones, matched by index. Servers
+// that leave out the index continue the last call, unless the fragment
+// starts a new one with an ID of its own.
+func mergeOpenAIToolCallDeltas(calls, deltas []openai.ToolCall) []openai.ToolCall {
+	for _, d := range deltas {
+		i := len(calls) - 1
+		switch {
+		case d.Index != nil:
+			i = *d.Index
+		case i < 0 || (d.ID != "" && d.ID != calls[i].ID):
+			i = len(calls)
+		}
+		for len(calls) <= i {
+			calls = append(calls, openai.ToolCall{Type: openai.ToolTypeFunction})
+		}
+		if d.ID != "" {
+			calls[i].ID = d.ID
+		}
+		if d.Function.Name != "" {
+			calls[i].Function.Name = d.Function.Name
+		}
+		calls[i].Function.Arguments += d.Function.Arguments
+	}
+	return calls
+}
+
+// openaiToolCalls converts the tool calls of a response.
+func openaiToolCalls(calls []openai.ToolCall) []ToolCall {
+	var out []ToolCall
+	for _, c := range calls {
+		if c.Function.Name == "" {
+			continue
+		}
+		out = append(out, ToolCall{ID: c.ID, Name: c.Function.Name, Arguments: toolArguments(json.RawMessage(c.Function.Arguments))})
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+// Package agent runs completions in which the model may call read-only
+// Memvra tools, so it can look things up that the injected context missed.
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/memvra/memvra/internal/adapter"
+)
+
+// DefaultMaxIterations is the number of rounds of tool calls Run allows
+// when Options.MaxIterations is not set.
+const DefaultMaxIterations = 5
+
+// limitReached is sent as the result of tool calls made after the last
+// round allowed.
+const limitReached = "Tool call limit reached. Answer with the information you already have."
+
+// ErrIterationLimit is returned by Run when the model keeps calling tools
+// after being told the limit was reached.
+var ErrIterationLimit = errors.New("agent: model kept calling tools after the limit was reached")
+
+// Executor provides the tools a model may call.
+type Executor interface {
+	// Tools describes the available tools.
+	Tools() []adapter.Tool
+	// Call runs one tool call and returns its result for the model.
+	Call(ctx context.Context, call adapter.ToolCall) (string, error)
+}
+
+// Options controls Run.
+type Options struct {
+	// MaxIterations caps the rounds of tool calls. After the last one the
+	// model is told to answer with what it has.
+	MaxIterations int
+	// OnText receives the response text as it arrives.
+	OnText func(text string)
+	// OnToolCall is told about each tool call and its outcome, e.g. to
+	// show a transcript.
+	OnToolCall func(call adapter.ToolCall, result string, err error)
+}
+
+// Run completes req with llm, offering it the given tools (none if tools is
+// nil). Whenever the model calls tools they are run and the results sent
+// back, until the model answers without calling any. It returns the full response text, including
+// any text written between tool calls. On an error, including
+// ErrIterationLimit, it returns the text received so far.
+func Run(ctx context.Context, llm adapter.LLMAdapter, req adapter.CompletionRequest, tools Executor, opts Options) (string, error) {
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = DefaultMaxIterations
+	}
+	onText := opts.OnText
+	if onText == nil {
+		onText = func(string) {}
+	}
+	if tools != nil {
+		req.Tools = tools.Tools()
+	}
+
+	var response strings.Builder
+	for round := 0; ; round++ {
+		text, calls, err := complete(ctx, llm, req, onText)
+		response.WriteString(text)
+		if err != nil {
+			return response.String(), err
+		}
+		if len(calls) == 0 {
+			return response.String(), nil
+		}
+		if round > opts.MaxIterations {
+			return response.String(), ErrIterationLimit
+		}
+		if text != "" {
+			response.WriteString("\n\n")
+			onText("\n\n")
+		}
+
+		req.ToolSteps = append(req.ToolSteps, adapter.Message{Role: adapter.RoleAssistant, Content: text, ToolCalls: calls})
+		for _, call := range calls {
+			result := limitReached
+			if round < opts.MaxIterations {
+				var callErr error
+				result, callErr = tools.Call(ctx, call)
+				if callErr != nil {
+					result = "Error: " + callErr.Error()
+				}
+				if opts.OnToolCall != nil {
+					opts.OnToolCall(call, result, callErr)
+				}
+			}
+			req.ToolSteps = append(req.ToolSteps, adapter.Message{Role: adapter.RoleTool, ToolCallID: call.ID, Content: result})
+		}
+	}
+}
+
+// complete runs one completion, passing text to onText as it streams, and
+// returns the text and any tool calls.
+func complete(ctx context.Context, llm adapter.LLMAdapter, req adapter.CompletionRequest, onText func(string)) (string, []adapter.ToolCall, error) {
+	stream, err := llm.Complete(ctx, req)
+	if err != nil {
+		return "", nil, fmt.Errorf("LLM request: %w", err)
+	}
+	var text strings.Builder
+	var calls []adapter.ToolCall
+	var streamErr error
+	for chunk := range stream {
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+			continue
+		}
+		if chunk.Text != "" {
+			onText(chunk.Text)
+			text.WriteString(chunk.Text)
+		}
+		calls = append(calls, chunk.ToolCalls...)
+	}
+	if streamErr != nil {
+		return text.String(), nil, fmt.Errorf("stream error: %w", streamErr)
+	}
+	return text.String(), calls, nil
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/memvra/memvra/internal/adapter"
+)
+
+// scriptedLLM replies to each completion with the next of replies and
+// records the requests it received.
+type scriptedLLM struct {
+	replies  [][]adapter.StreamChunk
+	requests []adapter.CompletionRequest
+}
+
+func (s *scriptedLLM) Complete(_ context.Context, req adapter.CompletionRequest) (<-chan adapter.StreamChunk, error) {
+	s.requests = append(s.requests, req)
+	if len(s.replies) == 0 {
+		return nil, errors.New("no more replies")
+	}
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	ch := make(chan adapter.StreamChunk, len(reply))
+	for _, c := range reply {
+		ch <- c
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (s *scriptedLLM) Embed(context.Context, []string) ([][]float32, error) { return nil, nil }
+func (s *scriptedLLM) Info() adapter.ModelInfo                              { return adapter.ModelInfo{} }
+
+// echoTools answers every call with its name and arguments.
+type echoTools struct{ calls int }
+
+func (e *echoTools) Tools() []adapter.Tool {
+	return []adapter.Tool{{Name: "echo", Parameters: json.RawMessage(`{"type":"object"}`)}}
+}
+
+func (e *echoTools) Call(_ context.Context, call adapter.ToolCall) (string, error) {
+	e.calls++
+	if call.Name != "echo" {
+		return "", fmt.Errorf("unknown tool %q", call.Name)
+	}
+	return "echo " + string(call.Arguments), nil
+}
+
+func toolCall(id, name string) adapter.StreamChunk {
+	return adapter.StreamChunk{ToolCalls: []adapter.ToolCall{{ID: id, Name: name, Arguments: json.RawMessage(`{"x":1}`)}}}
+}
+
+func TestRun_ToolRoundTrip(t *testing.T) {
+	llm := &scriptedLLM{replies: [][]adapter.StreamChunk{
+		{{Text: "Let me check."}, toolCall("1", "echo")},
+		{toolCall("2", "missing")},
+		{{Text: "The answer."}},
+	}}
+	tools := &echoTools{}
+	var streamed strings.Builder
+	var transcript []string
+
+	resp, err := Run(context.Background(), llm, adapter.CompletionRequest{UserMessage: "q"}, tools, Options{
+		OnText: func(s string) { streamed.WriteString(s) },
+		OnToolCall: func(c adapter.ToolCall, result string, err error) {
+			transcript = append(transcript, fmt.Sprintf("%s=%s/%v", c.Name, result, err != nil))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != "Let me check.\n\nThe answer." || streamed.String() != resp {
+		t.Errorf("response %q, streamed %q", resp, streamed.String())
+	}
+	if len(transcript) != 2 || transcript[0] != `echo=echo {"x":1}/false` || !strings.HasSuffix(transcript[1], "/true") {
+		t.Errorf("transcript: %v", transcript)
+	}
+
+	if len(llm.requests[0].Tools) != 1 || len(llm.requests[0].ToolSteps) != 0 {
+		t.Errorf("first request: %+v", llm.requests[0])
+	}
+	steps := llm.requests[2].ToolSteps
+	if len(steps) != 4 || steps[0].Content != "Let me check." || steps[1].ToolCallID != "1" ||
+		steps[3].Role != adapter.RoleTool || !strings.HasPrefix(steps[3].Content, "Error: unknown tool") {
+		t.Errorf("tool steps sent back: %+v", steps)
+	}
+}
+
+func TestRun_IterationLimit(t *testing.T) {
+	llm := &scriptedLLM{replies: [][]adapter.StreamChunk{
+		{toolCall("1", "echo")},
+		{toolCall("2", "echo")},
+		{{Text: "Best guess."}},
+	}}
+	tools := &echoTools{}
+	resp, err := Run(context.Background(), llm, adapter.CompletionRequest{}, tools, Options{MaxIterations: 1})
+	if err != nil || resp != "Best guess." {
+		t.Fatalf("got %q, %v", resp, err)
+	}
+	if tools.calls != 1 {
+		t.Errorf("expected 1 tool call to run, got %d", tools.calls)
+	}
+	if last := llm.requests[2].ToolSteps[3]; last.Content != limitReached {
+		t.Errorf("expected the model to be told the limit was reached, got %q", last.Content)
+	}
+
+	llm = &scriptedLLM{replies: [][]adapter.StreamChunk{
+		{{Text: "Looking."}, toolCall("1", "echo")}, {toolCall("2", "echo")}, {toolCall("3", "echo")},
+	}}
+	resp, err = Run(context.Background(), llm, adapter.CompletionRequest{}, &echoTools{}, Options{MaxIterations: 1})
+	if !errors.Is(err, ErrIterationLimit) {
+		t.Errorf("expected ErrIterationLimit, got %v", err)
+	}
+	if resp != "Looking.\n\n" {
+		t.Errorf("expected the partial response with the error, got %q", resp)
+	}
+}
+
+func TestRun_StreamError(t *testing.T) {
+	llm := &scriptedLLM{replies: [][]adapter.StreamChunk{
+		{{Text: "partial"}, {Error: errors.New("boom")}},
+	}}
+	resp, err := Run(context.Background(), llm, adapter.CompletionRequest{}, &echoTools{}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "boom") || resp != "partial" {
+		t.Errorf("got %q, %v", resp, err)
+	}
+}
+
+func TestRun_NoTools(t *testing.T) {
+	llm := &scriptedLLM{replies: [][]adapter.StreamChunk{{{Text: "Plain answer."}}}}
+	resp, err := Run(context.Background(), llm, adapter.CompletionRequest{UserMessage: "q"}, nil, Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if resp != "Plain answer." {
+		t.Errorf("response = %q", resp)
+	}
+	if len(llm.requests[0].Tools) != 0 {
+		t.Errorf("tools offered = %v, want none", llm.requests[0].Tools)
+	}
+}
@@ -0,0 +1,377 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/git"
+	"github.com/memvra/memvra/internal/memory"
+	"github.com/memvra/memvra/internal/scanner"
+)
+
+// Tool names offered by Toolset.
+const (
+	ToolSearchMemories = "search_memories"
+	ToolReadFile       = "read_file"
+	ToolListSymbols    = "list_symbols"
+	ToolGitDiff        = "git_diff"
+)
+
+const (
+	// maxReadLines caps the lines read_file returns per call.
+	maxReadLines = 400
+	// maxResultBytes caps the size of any tool result.
+	maxResultBytes = 20000
+	// maxSymbols caps the symbols list_symbols returns.
+	maxSymbols = 50
+)
+
+// Toolset is the read-only Memvra tools offered to a model: searching
+// memories, reading lines of project files, listing symbols and showing the
+// git diff.
+type Toolset struct {
+	root         string
+	store        *memory.Store
+	orchestrator *memory.Orchestrator
+	threshold    float64
+	ignore       *scanner.IgnoreMatcher
+}
+
+// NewToolset creates the tools for the project at root. Memory searches use
+// orchestrator with the given similarity threshold.
+func NewToolset(root string, store *memory.Store, orchestrator *memory.Orchestrator, threshold float64) *Toolset {
+	return &Toolset{
+		root:         root,
+		store:        store,
+		orchestrator: orchestrator,
+		threshold:    threshold,
+		ignore:       scanner.NewIgnoreMatcher(root),
+	}
+}
+
+// Tools implements Executor.
+func (t *Toolset) Tools() []adapter.Tool {
+	return []adapter.Tool{
+		{
+			Name:        ToolSearchMemories,
+			Description: "Search the project's stored memories (decisions, conventions, constraints, notes, todos) by meaning and keywords.",
+			Parameters: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"query": {"type": "string", "description": "What to look for"},
+					"limit": {"type": "integer", "description": "Maximum results (default 10)"}
+				},
+				"required": ["query"]
+			}`),
+		},
+		{
+			Name:        ToolReadFile,
+			Description: fmt.Sprintf("Read lines of a project file, with line numbers. At most %d lines are returned per call.", maxReadLines),
+			Parameters: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"path": {"type": "string", "description": "File path relative to the project root"},
+					"start_line": {"type": "integer", "description": "First line to read, 1-based (default 1)"},
+					"end_line": {"type": "integer", "description": "Last line to read (default: as many as allowed)"}
+				},
+				"required": ["path"]
+			}`),
+		},
+		{
+			Name:        ToolListSymbols,
+			Description: "Find where functions, types, methods and other symbols are defined. Give a name to look it up across the project, or a file to list the symbols it defines.",
+			Parameters: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "description": "Symbol name; partial names are matched when there is no exact match"},
+					"file": {"type": "string", "description": "File path relative to the project root"},
+					"kind": {"type": "string", "description": "Only symbols of this kind, e.g. func, method, type, class"}
+				}
+			}`),
+		},
+		{
+			Name:        ToolGitDiff,
+			Description: "Show the uncommitted changes in the project's git working tree.",
+			Parameters: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"staged": {"type": "boolean", "description": "Show staged changes instead of unstaged ones"},
+					"path": {"type": "string", "description": "Limit the diff to this file or directory"}
+				}
+			}`),
+		},
+	}
+}
+
+// Call implements Executor.
+func (t *Toolset) Call(ctx context.Context, call adapter.ToolCall) (string, error) {
+	var (
+		result string
+		err    error
+	)
+	switch call.Name {
+	case ToolSearchMemories:
+		var args struct {
+			Query string `json:"query"`
+			Limit int    `json:"limit"`
+		}
+		if err := decodeArgs(call, &args); err != nil {
+			return "", err
+		}
+		result, err = t.searchMemories(ctx, args.Query, args.Limit)
+	case ToolReadFile:
+		var args struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+			EndLine   int    `json:"end_line"`
+		}
+		if err := decodeArgs(call, &args); err != nil {
+			return "", err
+		}
+		result, err = t.readFile(args.Path, args.StartLine, args.EndLine)
+	case ToolListSymbols:
+		var args struct {
+			Name string `json:"name"`
+			File string `json:"file"`
+			Kind string `json:"kind"`
+		}
+		if err := decodeArgs(call, &args); err != nil {
+			return "", err
+		}
+		result, err = t.listSymbols(args.Name, args.File, args.Kind)
+	case ToolGitDiff:
+		var args struct {
+			Staged bool   `json:"staged"`
+			Path   string `json:"path"`
+		}
+		if err := decodeArgs(call, &args); err != nil {
+			return "", err
+		}
+		result, err = t.gitDiff(args.Staged, args.Path)
+	default:
+		return "", fmt.Errorf("unknown tool %q", call.Name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return truncateResult(result), nil
+}
+
+func (t *Toolset) searchMemories(ctx context.Context, query string, limit int) (string, error) {
+	if strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	if limit <= 0 || limit > 25 {
+		limit = 10
+	}
+	res, err := t.orchestrator.Retrieve(ctx, query, memory.RetrieveOptions{
+		TopKMemories:        limit,
+		SimilarityThreshold: t.threshold,
+	})
+	if err != nil {
+		return "", fmt.Errorf("search failed: %w", err)
+	}
+	if len(res.Memories) == 0 {
+		return "No matching memories.", nil
+	}
+	var sb strings.Builder
+	for _, m := range res.Memories {
+		fmt.Fprintf(&sb, "- [%s] %s", m.MemoryType, m.Content)
+		if len(m.Tags) > 0 {
+			fmt.Fprintf(&sb, " (tags: %s)", strings.Join(m.Tags, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func (t *Toolset) readFile(path string, start, end int) (string, error) {
+	rel, abs, err := t.resolve(path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s does not exist", rel)
+		}
+		return "", fmt.Errorf("read %s: %w", rel, err)
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return "", fmt.Errorf("%s is a binary file", rel)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if start <= 0 {
+		start = 1
+	}
+	if start > len(lines) {
+		return "", fmt.Errorf("%s has only %d lines", rel, len(lines))
+	}
+	if end <= 0 || end > len(lines) {
+		end = len(lines)
+	}
+	if end < start {
+		return "", fmt.Errorf("end_line %d is before start_line %d", end, start)
+	}
+	if end-start+1 > maxReadLines {
+		end = start + maxReadLines - 1
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s (lines %d-%d of %d)\n", rel, start, end, len(lines))
+	for i := start; i <= end; i++ {
+		fmt.Fprintf(&sb, "%5d  %s\n", i, lines[i-1])
+	}
+	return sb.String(), nil
+}
+
+func (t *Toolset) listSymbols(name, file, kind string) (string, error) {
+	var (
+		syms []memory.Symbol
+		err  error
+	)
+	switch {
+	case file != "":
+		rel, _, rerr := t.resolve(file)
+		if rerr != nil {
+			return "", rerr
+		}
+		syms, err = t.store.ListFileSymbols(rel)
+		if err == nil && kind != "" {
+			syms = filterKind(syms, kind)
+		}
+		if err == nil && name != "" {
+			syms = filterName(syms, name)
+		}
+	case name != "":
+		syms, err = t.store.FindSymbols(name, kind, false, maxSymbols)
+		if err == nil && len(syms) == 0 {
+			syms, err = t.store.FindSymbols(name, kind, true, maxSymbols)
+		}
+	default:
+		return "", fmt.Errorf("give a name or a file")
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(syms) == 0 {
+		return "No matching symbols.", nil
+	}
+
+	var sb strings.Builder
+	for i, s := range syms {
+		if i == maxSymbols {
+			fmt.Fprintf(&sb, "... and %d more\n", len(syms)-maxSymbols)
+			break
+		}
+		fmt.Fprintf(&sb, "%s:%d-%d  %s %s", s.FilePath, s.StartLine, s.EndLine, s.Kind, s.Name)
+		if s.Signature != "" {
+			fmt.Fprintf(&sb, "  %s", s.Signature)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func (t *Toolset) gitDiff(staged bool, path string) (string, error) {
+	var paths []string
+	if path != "" {
+		rel, _, err := t.resolve(path)
+		if err != nil {
+			return "", err
+		}
+		paths = append(paths, rel)
+	}
+	diff, err := git.Diff(t.root, staged, paths...)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return "No changes.", nil
+	}
+	return diff, nil
+}
+
+// resolve checks that path names something inside the project that may be
+// shown to the model, and returns it relative to the root (slash-separated)
+// and as an absolute path. The .git and .memvra directories and files
+// matched by .gitignore are refused.
+func (t *Toolset) resolve(path string) (rel, abs string, err error) {
+	rel = filepath.Clean(filepath.FromSlash(strings.TrimPrefix(path, "./")))
+	if filepath.IsAbs(rel) {
+		if r, rerr := filepath.Rel(t.root, rel); rerr == nil {
+			rel = r
+		}
+	}
+	if !filepath.IsLocal(rel) {
+		return "", "", fmt.Errorf("%s is outside the project", path)
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if part == ".git" || part == ".memvra" {
+			return "", "", fmt.Errorf("%s is not available", path)
+		}
+	}
+	if rel != "." && t.ignore.Match(filepath.ToSlash(rel)) {
+		return "", "", fmt.Errorf("%s is ignored by .gitignore", path)
+	}
+
+	abs = filepath.Join(t.root, rel)
+	// Refuse symlinks that lead out of the project.
+	if real, rerr := filepath.EvalSymlinks(abs); rerr == nil {
+		root, _ := filepath.EvalSymlinks(t.root)
+		if r, rerr := filepath.Rel(root, real); rerr != nil || !filepath.IsLocal(r) {
+			return "", "", fmt.Errorf("%s is outside the project", path)
+		}
+	}
+	return filepath.ToSlash(rel), abs, nil
+}
+
+// decodeArgs unmarshals the arguments of call into v.
+func decodeArgs(call adapter.ToolCall, v any) error {
+	if len(call.Arguments) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(call.Arguments, v); err != nil {
+		return fmt.Errorf("invalid arguments for %s: %w", call.Name, err)
+	}
+	return nil
+}
+
+// truncateResult shortens s to maxResultBytes, cutting at a line break.
+func truncateResult(s string) string {
+	if len(s) <= maxResultBytes {
+		return s
+	}
+	cut := s[:maxResultBytes]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i+1]
+	}
+	return cut + "... (output truncated; ask for less, e.g. a smaller line range or a single path)\n"
+}
+
+func filterKind(syms []memory.Symbol, kind string) []memory.Symbol {
+	var out []memory.Symbol
+	for _, s := range syms {
+		if strings.EqualFold(s.Kind, kind) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func filterName(syms []memory.Symbol, name string) []memory.Symbol {
+	var out []memory.Symbol
+	for _, s := range syms {
+		if strings.Contains(strings.ToLower(s.Name), strings.ToLower(name)) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func setupToolset(t *testing.T) (*Toolset, string) {
+	t.Helper()
+	root := t.TempDir()
+	database, err := db.Open(filepath.Join(t.TempDir(), "memvra.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	store := memory.NewStore(database)
+	orch := memory.NewOrchestrator(store, memory.NewVectorStore(database), memory.NewRanker(), nil)
+	return NewToolset(root, store, orch, 0), root
+}
+
+func call(t *testing.T, ts *Toolset, name, args string) (string, error) {
+	t.Helper()
+	return ts.Call(context.Background(), adapter.ToolCall{ID: "1", Name: name, Arguments: json.RawMessage(args)})
+}
+
+func TestToolset_ReadFile(t *testing.T) {
+	ts, root := setupToolset(t)
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644)
+	os.WriteFile(filepath.Join(root, ".gitignore"), []byte(".env\n"), 0o644)
+	os.WriteFile(filepath.Join(root, ".env"), []byte("SECRET=1\n"), 0o644)
+	ts = NewToolset(root, ts.store, ts.orchestrator, 0)
+
+	out, err := call(t, ts, ToolReadFile, `{"path":"main.go","start_line":3}`)
+	if err != nil || !strings.HasPrefix(out, "main.go (lines 3-3 of 3)") || !strings.Contains(out, "    3  func main() {}") {
+		t.Errorf("read_file: %q %v", out, err)
+	}
+
+	for _, path := range []string{"../outside.go", "/etc/passwd", ".env", ".memvra/memvra.db", "missing.go"} {
+		if _, err := call(t, ts, ToolReadFile, `{"path":"`+path+`"}`); err == nil {
+			t.Errorf("expected %s to be refused", path)
+		}
+	}
+}
+
+func TestToolset_ListSymbols(t *testing.T) {
+	ts, _ := setupToolset(t)
+	fileID, _ := ts.store.UpsertFile(memory.File{Path: "server.go", Language: "go", LastModified: time.Now(), ContentHash: "h"})
+	ts.store.ReplaceFileSymbols(fileID, []memory.Symbol{
+		{Name: "Server", Kind: "type", StartLine: 1, EndLine: 3},
+		{Name: "Server.Start", Kind: "method", StartLine: 5, EndLine: 9, Signature: "func (s *Server) Start() error"},
+	})
+
+	out, err := call(t, ts, ToolListSymbols, `{"name":"Start"}`)
+	if err != nil || !strings.Contains(out, "server.go:5-9  method Server.Start  func (s *Server) Start() error") {
+		t.Errorf("by name: %q %v", out, err)
+	}
+	out, _ = call(t, ts, ToolListSymbols, `{"file":"server.go","kind":"type"}`)
+	if !strings.Contains(out, "type Server") || strings.Contains(out, "method") {
+		t.Errorf("by file: %q", out)
+	}
+	if _, err := call(t, ts, ToolListSymbols, `{}`); err == nil {
+		t.Error("expected an error without name or file")
+	}
+}
+
+func TestToolset_SearchMemories(t *testing.T) {
+	ts, _ := setupToolset(t)
+	ts.store.InsertMemory(memory.Memory{Content: "Use PostgreSQL for persistence", MemoryType: memory.TypeDecision, Importance: 0.8})
+
+	out, err := call(t, ts, ToolSearchMemories, `{"query":"PostgreSQL"}`)
+	if err != nil || !strings.Contains(out, "[decision] Use PostgreSQL for persistence") {
+		t.Errorf("search_memories: %q %v", out, err)
+	}
+	if _, err := call(t, ts, ToolSearchMemories, `{"query":""}`); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+}
+
+func TestToolset_GitDiff(t *testing.T) {
+	ts, root := setupToolset(t)
+	for _, args := range [][]string{
+		{"init"}, {"config", "user.email", "t@t"}, {"config", "user.name", "T"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git unavailable: %v %s", err, out)
+		}
+	}
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("one\n"), 0o644)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "init"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v %s", args, err, out)
+		}
+	}
+
+	if out, _ := call(t, ts, ToolGitDiff, `{}`); out != "No changes." {
+		t.Errorf("clean tree: %q", out)
+	}
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("two\n"), 0o644)
+	out, err := call(t, ts, ToolGitDiff, `{"path":"a.txt"}`)
+	if err != nil || !strings.Contains(out, "+two") {
+		t.Errorf("git_diff: %q %v", out, err)
+	}
+}
+
+func TestToolset_UnknownToolAndBadArgs(t *testing.T) {
+	ts, _ := setupToolset(t)
+	if _, err := call(t, ts, "write_file", `{}`); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+	if _, err := call(t, ts, ToolReadFile, `{"path": 1}`); err == nil {
+		t.Error("expected an error for malformed arguments")
+	}
+}
+
+func TestTruncateResult(t *testing.T) {
+	long := strings.Repeat("0123456789\n", maxResultBytes/5)
+	got := truncateResult(long)
+	if len(got) > maxResultBytes+200 || !strings.Contains(got, "output truncated") {
+		t.Errorf("not truncated: %d bytes", len(got))
+	}
+	if truncateResult("short") != "short" {
+		t.Error("short results should be unchanged")
+	}
+}
@@ -0,0 +1,349 @@
+// Package bundle reads and writes portable Memvra memory bundles: a
+// directory of JSON Lines files holding a project's memories, sessions and
+// profile, so that memory can be moved or shared between projects and
+// reviewed in version control.
+package bundle
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/memvra/memvra/internal/memory"
+)
+
+const (
+	// Format identifies a memvra bundle in its manifest.
+	Format = "memvra-pack"
+	// Version is the bundle layout written by this version of Memvra.
+	// Bundles with a newer version are rejected.
+	Version = 1
+	// DefaultDir is the directory `memvra pack` writes to by default.
+	DefaultDir = "memvra-pack"
+)
+
+// Files that make up a bundle.
+const (
+	manifestFile   = "manifest.json"
+	projectFile    = "project.jsonl"
+	memoriesFile   = "memories.jsonl"
+	sessionsFile   = "sessions.jsonl"
+	turnsFile      = "session_turns.jsonl"
+	embeddingsFile = "embeddings.jsonl"
+)
+
+// Manifest describes a bundle. It carries no timestamp so that packing an
+// unchanged project produces an identical bundle.
+type Manifest struct {
+	Format             string `json:"format"`
+	Version            int    `json:"version"`
+	Project            string `json:"project"`
+	EmbeddingModel     string `json:"embedding_model,omitempty"`
+	EmbeddingDimension int    `json:"embedding_dimension,omitempty"`
+	Memories           int    `json:"memories"`
+	Sessions           int    `json:"sessions"`
+	SessionTurns       int    `json:"session_turns,omitempty"`
+	Embeddings         int    `json:"embeddings"`
+}
+
+// Profile is the part of the project record that travels with a bundle.
+type Profile struct {
+	Name         string `json:"name"`
+	TechStack    string `json:"tech_stack,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	Conventions  string `json:"conventions,omitempty"`
+}
+
+// embeddingLine is one line of embeddings.jsonl: a memory's vector as
+// base64-encoded little-endian float32s.
+type embeddingLine struct {
+	ID     string `json:"id"`
+	Vector string `json:"vector"`
+}
+
+// Bundle is the content of a memory bundle.
+type Bundle struct {
+	Manifest Manifest
+	Profile  Profile
+	Memories []memory.Memory
+	Sessions []memory.Session
+	// SessionTurns holds the transcripts of Sessions, ordered by session
+	// then turn.
+	SessionTurns []memory.SessionTurn
+	Embeddings   map[string][]float32 // memory ID → vector; nil if not packed
+}
+
+// PackOptions controls what Pack includes.
+type PackOptions struct {
+	Embeddings bool // include memory vectors
+	Sessions   bool // include session history and transcripts
+}
+
+// Pack collects the memories, sessions and project profile of a database
+// into a bundle. Memories of every status are included, so that history
+// and superseded links survive the move.
+func Pack(store *memory.Store, vectors *memory.VectorStore, opts PackOptions) (*Bundle, error) {
+	proj, err := store.GetProject()
+	if err != nil {
+		return nil, err
+	}
+	memories, err := store.ListAllMemories()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		Profile: Profile{
+			Name:         proj.Name,
+			TechStack:    proj.TechStack,
+			Architecture: proj.Architecture,
+			Conventions:  proj.Conventions,
+		},
+		Memories: memories,
+	}
+
+	if opts.Sessions {
+		sessions, err := store.ListSessionsSince(time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(sessions, func(i, j int) bool {
+			if !sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+				return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+			}
+			return sessions[i].ID < sessions[j].ID
+		})
+		b.Sessions = sessions
+		for _, s := range sessions {
+			turns, err := store.ListSessionTurns(s.ID)
+			if err != nil {
+				return nil, err
+			}
+			b.SessionTurns = append(b.SessionTurns, turns...)
+		}
+	}
+
+	if opts.Embeddings {
+		b.Embeddings = make(map[string][]float32)
+		for _, m := range memories {
+			vec, err := vectors.GetMemoryEmbedding(m.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(vec) > 0 {
+				b.Embeddings[m.ID] = vec
+			}
+		}
+	}
+
+	b.Manifest = Manifest{
+		Format:       Format,
+		Version:      Version,
+		Project:      proj.Name,
+		Memories:     len(b.Memories),
+		Sessions:     len(b.Sessions),
+		SessionTurns: len(b.SessionTurns),
+		Embeddings:   len(b.Embeddings),
+	}
+	if len(b.Embeddings) > 0 {
+		b.Manifest.EmbeddingModel = proj.EmbeddingModel
+		b.Manifest.EmbeddingDimension = proj.EmbeddingDimension
+	}
+	return b, nil
+}
+
+// Write stores b in dir, creating it if needed. Files of a previous bundle
+// that b has no data for are removed.
+func Write(dir string, b *Bundle) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("bundle: create %s: %w", dir, err)
+	}
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), append(manifest, '\n'), 0o644); err != nil {
+		return fmt.Errorf("bundle: write manifest: %w", err)
+	}
+
+	if err := writeLines(filepath.Join(dir, projectFile), []Profile{b.Profile}); err != nil {
+		return err
+	}
+	if err := writeLines(filepath.Join(dir, memoriesFile), b.Memories); err != nil {
+		return err
+	}
+
+	sessionsPath := filepath.Join(dir, sessionsFile)
+	if len(b.Sessions) > 0 {
+		if err := writeLines(sessionsPath, b.Sessions); err != nil {
+			return err
+		}
+	} else if err := removeIfExists(sessionsPath); err != nil {
+		return err
+	}
+
+	turnsPath := filepath.Join(dir, turnsFile)
+	if len(b.SessionTurns) > 0 {
+		if err := writeLines(turnsPath, b.SessionTurns); err != nil {
+			return err
+		}
+	} else if err := removeIfExists(turnsPath); err != nil {
+		return err
+	}
+
+	embeddingsPath := filepath.Join(dir, embeddingsFile)
+	if len(b.Embeddings) == 0 {
+		return removeIfExists(embeddingsPath)
+	}
+	lines := make([]embeddingLine, 0, len(b.Embeddings))
+	for _, m := range b.Memories {
+		if vec, ok := b.Embeddings[m.ID]; ok {
+			lines = append(lines, embeddingLine{ID: m.ID, Vector: encodeVector(vec)})
+		}
+	}
+	return writeLines(embeddingsPath, lines)
+}
+
+// Read loads the bundle stored in dir.
+func Read(dir string) (*Bundle, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("bundle: read manifest: %w", err)
+	}
+	b := &Bundle{}
+	if err := json.Unmarshal(data, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("bundle: parse manifest: %w", err)
+	}
+	if b.Manifest.Format != Format {
+		return nil, fmt.Errorf("bundle: %s is not a memvra bundle", dir)
+	}
+	if b.Manifest.Version > Version {
+		return nil, fmt.Errorf("bundle: version %d is newer than this Memvra supports (%d) — upgrade Memvra",
+			b.Manifest.Version, Version)
+	}
+
+	profiles, err := readLines[Profile](filepath.Join(dir, projectFile))
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) > 0 {
+		b.Profile = profiles[0]
+	}
+	if b.Memories, err = readLines[memory.Memory](filepath.Join(dir, memoriesFile)); err != nil {
+		return nil, err
+	}
+	if b.Sessions, err = readLines[memory.Session](filepath.Join(dir, sessionsFile)); err != nil {
+		return nil, err
+	}
+	if b.SessionTurns, err = readLines[memory.SessionTurn](filepath.Join(dir, turnsFile)); err != nil {
+		return nil, err
+	}
+
+	embeddings, err := readLines[embeddingLine](filepath.Join(dir, embeddingsFile))
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) > 0 {
+		b.Embeddings = make(map[string][]float32, len(embeddings))
+		for _, e := range embeddings {
+			vec, err := decodeVector(e.Vector)
+			if err != nil {
+				return nil, fmt.Errorf("bundle: embedding of %s: %w", e.ID, err)
+			}
+			b.Embeddings[e.ID] = vec
+		}
+	}
+	return b, nil
+}
+
+// writeLines writes items to path, one JSON document per line.
+func writeLines[T any](path string, items []T) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("bundle: create %s: %w", filepath.Base(path), err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("bundle: write %s: %w", filepath.Base(path), err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("bundle: write %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// readLines reads the JSON Lines file at path. A missing file reads as
+// empty, since optional parts of a bundle may be left out.
+func readLines[T any](path string) ([]T, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var out []T
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			return nil, fmt.Errorf("bundle: %s line %d: %w", filepath.Base(path), n, err)
+		}
+		out = append(out, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("bundle: read %s: %w", filepath.Base(path), err)
+	}
+	return out, nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	return nil
+}
+
+func encodeVector(vec []float32) string {
+	buf := make([]byte, 4*len(vec))
+	for i, f := range vec {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func decodeVector(s string) ([]float32, error) {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf)%4 != 0 {
+		return nil, fmt.Errorf("vector length %d is not a multiple of 4", len(buf))
+	}
+	vec := make([]float32, len(buf)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return vec, nil
+}
@@ -0,0 +1,243 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func setupStore(t *testing.T) (*memory.Store, *memory.VectorStore) {
+	t.Helper()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	store := memory.NewStore(database)
+	if err := store.UpsertProject(memory.Project{Name: "proj", RootPath: "/tmp/proj", TechStack: "{}"}); err != nil {
+		t.Fatalf("UpsertProject: %v", err)
+	}
+	return store, memory.NewVectorStore(database)
+}
+
+func at(s string) time.Time {
+	t, _ := time.Parse("2006-01-02 15:04:05", s)
+	return t
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	store, vectors := setupStore(t)
+	if err := store.SetEmbeddingModel("test/embed", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := vectors.Reset(3); err != nil {
+		t.Fatal(err)
+	}
+
+	id, _ := store.InsertMemory(memory.Memory{
+		Content: "Use pgx for Postgres", MemoryType: memory.TypeDecision, Importance: 0.8,
+		Source: "user", Tags: []string{"db"},
+	})
+	if err := vectors.UpsertMemoryEmbedding(id, []float32{0.5, -1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	sid, _ := store.InsertSessionReturningID(memory.Session{Question: "how do we migrate?", ResponseSummary: "with goose"})
+	store.AddSessionTurn(memory.SessionTurn{SessionID: sid, Question: "how do we migrate?", Response: "with goose", Sources: []string{"db/migrate.go"}})
+
+	b, err := Pack(store, vectors, PackOptions{Embeddings: true, Sessions: true})
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), DefaultDir)
+	if err := Write(dir, b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, err := Read(dir)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got.Manifest.Memories != 1 || got.Manifest.Sessions != 1 || got.Manifest.EmbeddingModel != "test/embed" {
+		t.Errorf("manifest: %+v", got.Manifest)
+	}
+	if len(got.Memories) != 1 || got.Memories[0].ID != id || got.Memories[0].Tags[0] != "db" {
+		t.Errorf("memories: %+v", got.Memories)
+	}
+	if len(got.Sessions) != 1 || got.Sessions[0].Question != "how do we migrate?" {
+		t.Errorf("sessions: %+v", got.Sessions)
+	}
+	if len(got.SessionTurns) != 1 || got.SessionTurns[0].SessionID != sid || got.SessionTurns[0].Sources[0] != "db/migrate.go" {
+		t.Errorf("session turns: %+v", got.SessionTurns)
+	}
+	if vec := got.Embeddings[id]; len(vec) != 3 || vec[1] != -1 {
+		t.Errorf("embedding: %v", vec)
+	}
+
+	// Packing again without embeddings removes the stale file.
+	b, _ = Pack(store, vectors, PackOptions{})
+	if err := Write(dir, b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	for _, name := range []string{embeddingsFile, sessionsFile, turnsFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed", name)
+		}
+	}
+}
+
+func TestRead_RejectsNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, manifestFile), []byte(`{"format":"memvra-pack","version":99}`), 0o644)
+	if _, err := Read(dir); err == nil {
+		t.Fatal("expected an error for a newer bundle version")
+	}
+}
+
+func TestPlanMerge_Categories(t *testing.T) {
+	store, _ := setupStore(t)
+	base := memory.Memory{MemoryType: memory.TypeNote, Importance: 0.5, Source: "user",
+		CreatedAt: at("2026-01-01 10:00:00"), UpdatedAt: at("2026-01-01 10:00:00")}
+
+	put := func(id, content, updated string) memory.Memory {
+		m := base
+		m.ID, m.Content, m.UpdatedAt = id, content, at(updated)
+		if err := store.PutMemory(m); err != nil {
+			t.Fatalf("PutMemory: %v", err)
+		}
+		got, _ := store.GetMemoryByID(id)
+		return got
+	}
+	same := put("same", "unchanged memory", "2026-01-02 00:00:00")
+	older := put("older", "local text", "2026-01-02 00:00:00")
+	newer := put("newer", "local edit", "2026-01-05 00:00:00")
+	put("other", "Deploys go through CI", "2026-01-02 00:00:00")
+
+	older.Content, older.UpdatedAt = "bundle text", at("2026-01-03 00:00:00")
+	newer.Content, newer.UpdatedAt = "bundle edit", at("2026-01-04 00:00:00")
+	fresh := base
+	fresh.ID, fresh.Content = "fresh", "brand new memory"
+	dup := base
+	dup.ID, dup.Content = "dup", "deploys go through CI."
+
+	b := &Bundle{
+		Memories: []memory.Memory{same, older, newer, fresh, dup},
+		Sessions: []memory.Session{{ID: "s1", Question: "q", CreatedAt: at("2026-01-01 09:00:00")}},
+		Profile:  Profile{Name: "other", TechStack: `{"language":"Go"}`},
+	}
+	plan, err := PlanMerge(store, b, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+	if plan.Unchanged != 1 {
+		t.Errorf("unchanged: got %d, want 1", plan.Unchanged)
+	}
+	if len(plan.Updated) != 1 || plan.Updated[0].ID != "older" {
+		t.Errorf("updated: %+v", plan.Updated)
+	}
+	if len(plan.Conflicts) != 1 || plan.Conflicts[0].Local.Content != "local edit" {
+		t.Errorf("conflicts: %+v", plan.Conflicts)
+	}
+	if len(plan.New) != 1 || plan.New[0].ID != "fresh" {
+		t.Errorf("new: %+v", plan.New)
+	}
+	if len(plan.Duplicates) != 1 || plan.Duplicates[0].ID != "dup" {
+		t.Errorf("duplicates: %+v", plan.Duplicates)
+	}
+	if len(plan.Sessions) != 1 {
+		t.Errorf("sessions: %+v", plan.Sessions)
+	}
+	if !plan.ProfileFilled || plan.Profile.TechStack != `{"language":"Go"}` || plan.Profile.Name != "proj" {
+		t.Errorf("profile: %+v", plan.Profile)
+	}
+	if len(plan.Written()) != 2 {
+		t.Errorf("written: got %d, want 2", len(plan.Written()))
+	}
+	plan.Theirs = true
+	if len(plan.Written()) != 3 {
+		t.Errorf("written with theirs: got %d, want 3", len(plan.Written()))
+	}
+}
+
+func TestApply_ReusesCompatibleVectors(t *testing.T) {
+	src, srcVectors := setupStore(t)
+	src.SetEmbeddingModel("test/embed", 2)
+	srcVectors.Reset(2)
+	id, _ := src.InsertMemory(memory.Memory{Content: "Use pgx for Postgres", MemoryType: memory.TypeDecision, Source: "user"})
+	srcVectors.UpsertMemoryEmbedding(id, []float32{1, 2})
+	noVecID, _ := src.InsertMemory(memory.Memory{Content: "Prefer table tests", MemoryType: memory.TypeConvention, Source: "user"})
+	sid, _ := src.InsertSessionReturningID(memory.Session{Question: "why pgx?"})
+	src.AddSessionTurn(memory.SessionTurn{SessionID: sid, Question: "why pgx?", Response: "It supports COPY."})
+	src.AddSessionTurn(memory.SessionTurn{SessionID: sid, Question: "and sqlc?", Response: "It generates pgx code."})
+
+	b, err := Pack(src, srcVectors, PackOptions{Embeddings: true, Sessions: true})
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+
+	dst, dstVectors := setupStore(t)
+	plan, err := PlanMerge(dst, b, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+	unembedded, err := Apply(dst, dstVectors, b, plan)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(unembedded) != 1 || unembedded[0].ID != noVecID {
+		t.Errorf("unembedded: %+v", unembedded)
+	}
+	if vec, _ := dstVectors.GetMemoryEmbedding(id); len(vec) != 2 || vec[1] != 2 {
+		t.Errorf("embedding not copied: %v", vec)
+	}
+	got, err := dst.GetMemoryByID(id)
+	if err != nil || got.Content != "Use pgx for Postgres" {
+		t.Errorf("memory not stored under its ID: %+v, %v", got, err)
+	}
+	if n, _ := dst.CountSessions(); n != 1 {
+		t.Errorf("sessions: got %d, want 1", n)
+	}
+	turns, err := dst.ListSessionTurns(sid)
+	if err != nil || len(turns) != 2 || turns[1].Turn != 2 || turns[1].Question != "and sqlc?" {
+		t.Errorf("session turns: %+v, %v", turns, err)
+	}
+
+	// Unpacking again changes nothing.
+	plan, _ = PlanMerge(dst, b, false)
+	if plan.Unchanged != 2 || len(plan.Written()) != 0 || len(plan.Sessions) != 0 || len(plan.SessionTurns) != 0 {
+		t.Errorf("second merge: %+v", plan)
+	}
+
+	// A turn added to the session since is picked up on the next unpack.
+	src.AddSessionTurn(memory.SessionTurn{SessionID: sid, Question: "migrations?", Response: "goose"})
+	b, _ = Pack(src, srcVectors, PackOptions{Sessions: true})
+	plan, _ = PlanMerge(dst, b, false)
+	if len(plan.SessionTurns) != 1 || plan.SessionTurns[0].Turn != 3 {
+		t.Fatalf("new turn: %+v", plan.SessionTurns)
+	}
+	if _, err := Apply(dst, dstVectors, b, plan); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if turns, _ := dst.ListSessionTurns(sid); len(turns) != 3 {
+		t.Errorf("session turns after second unpack: got %d, want 3", len(turns))
+	}
+}
+
+func TestApply_RollsBackOnError(t *testing.T) {
+	dst, dstVectors := setupStore(t)
+	b := &Bundle{}
+	plan := &Plan{
+		New: []memory.Memory{{ID: "m1", Content: "Use pgx for Postgres", MemoryType: memory.TypeDecision, Source: "user"}},
+		// A turn of a session that exists nowhere fails the foreign key.
+		SessionTurns: []memory.SessionTurn{{SessionID: "missing", Turn: 1, Question: "q"}},
+	}
+	if _, err := Apply(dst, dstVectors, b, plan); err == nil {
+		t.Fatal("expected Apply to fail")
+	}
+	if _, err := dst.GetMemoryByID("m1"); err == nil {
+		t.Error("memory written before the failure was kept")
+	}
+}
@@ -0,0 +1,229 @@
+package bundle
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/memvra/memvra/internal/memory"
+)
+
+// Conflict is a memory changed both in the bundle and locally: the bundle's
+// version differs from the local one but is not newer.
+type Conflict struct {
+	Local    memory.Memory
+	Incoming memory.Memory
+}
+
+// Plan is what merging a bundle into a database would do.
+type Plan struct {
+	New           []memory.Memory      // IDs not in the database
+	Updated       []memory.Memory      // newer versions of local memories
+	Unchanged     int                  // memories identical to the local ones
+	Conflicts     []Conflict           // kept local unless Theirs is set
+	Duplicates    []memory.Memory      // new IDs repeating an active local memory; skipped
+	Sessions      []memory.Session     // sessions not in the database
+	SessionTurns  []memory.SessionTurn // transcript turns not in the database
+	Profile       memory.Project       // local project with empty fields filled from the bundle
+	ProfileFilled bool                 // Profile fills empty fields of the local project
+	Theirs        bool                 // conflicts take the bundle's version
+}
+
+// PlanMerge compares b with the database and returns what merging it would
+// do. Memories are matched by ID, so a bundle can be unpacked repeatedly.
+// When a memory differs, the version updated last wins; a local version
+// that is as new or newer is a conflict, resolved in favour of the bundle
+// only if theirs is set.
+func PlanMerge(store *memory.Store, b *Bundle, theirs bool) (*Plan, error) {
+	local, err := store.ListAllMemories()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]memory.Memory, len(local))
+	activeContent := make(map[string]bool)
+	for _, m := range local {
+		byID[m.ID] = m
+		if isActive(m) {
+			activeContent[memory.ContentKey(m.Content)] = true
+		}
+	}
+
+	plan := &Plan{Theirs: theirs}
+	for _, in := range b.Memories {
+		cur, ok := byID[in.ID]
+		switch {
+		case !ok && isActive(in) && activeContent[memory.ContentKey(in.Content)]:
+			plan.Duplicates = append(plan.Duplicates, in)
+		case !ok:
+			plan.New = append(plan.New, in)
+		case sameMemory(cur, in):
+			plan.Unchanged++
+		case in.UpdatedAt.After(cur.UpdatedAt):
+			plan.Updated = append(plan.Updated, in)
+		default:
+			plan.Conflicts = append(plan.Conflicts, Conflict{Local: cur, Incoming: in})
+		}
+	}
+
+	sessions, err := store.ListSessionsSince(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		known[s.ID] = true
+	}
+	for _, s := range b.Sessions {
+		if !known[s.ID] {
+			plan.Sessions = append(plan.Sessions, s)
+		}
+	}
+	// Turns are matched by session and turn number, so turns added to a
+	// session after an earlier unpack still arrive.
+	localTurns := make(map[string]map[int]bool)
+	for _, t := range b.SessionTurns {
+		if !known[t.SessionID] {
+			continue
+		}
+		if _, ok := localTurns[t.SessionID]; !ok {
+			turns, err := store.ListSessionTurns(t.SessionID)
+			if err != nil {
+				return nil, err
+			}
+			localTurns[t.SessionID] = make(map[int]bool, len(turns))
+			for _, lt := range turns {
+				localTurns[t.SessionID][lt.Turn] = true
+			}
+		}
+	}
+	incoming := make(map[string]bool, len(b.Sessions))
+	for _, s := range b.Sessions {
+		incoming[s.ID] = true
+	}
+	for _, t := range b.SessionTurns {
+		switch {
+		case known[t.SessionID]:
+			if !localTurns[t.SessionID][t.Turn] {
+				plan.SessionTurns = append(plan.SessionTurns, t)
+			}
+		case incoming[t.SessionID]:
+			plan.SessionTurns = append(plan.SessionTurns, t)
+		}
+	}
+
+	plan.Profile, err = store.GetProject()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&plan.Profile.TechStack, b.Profile.TechStack},
+		{&plan.Profile.Architecture, b.Profile.Architecture},
+		{&plan.Profile.Conventions, b.Profile.Conventions},
+	} {
+		if isEmptyProfileField(*f.dst) && !isEmptyProfileField(f.src) {
+			*f.dst = f.src
+			plan.ProfileFilled = true
+		}
+	}
+	return plan, nil
+}
+
+// Written returns the memories Apply writes: the new and updated ones, and
+// the conflicting ones when the bundle wins.
+func (p *Plan) Written() []memory.Memory {
+	out := append(slices.Clone(p.New), p.Updated...)
+	if p.Theirs {
+		for _, c := range p.Conflicts {
+			out = append(out, c.Incoming)
+		}
+	}
+	return out
+}
+
+// Apply carries out plan in a single transaction: if any write fails,
+// none is kept. The bundle's vectors are stored with the written memories
+// when they come from a model compatible with the database (see
+// memory.CheckEmbeddingModel); the memories returned need embedding by the
+// caller, which is all written memories otherwise. Callers that embed with
+// a different model than the bundle's clear b.Embeddings first.
+func Apply(store *memory.Store, vectors *memory.VectorStore, b *Bundle, plan *Plan) (unembedded []memory.Memory, err error) {
+	useVectors := len(b.Embeddings) > 0 && b.Manifest.EmbeddingModel != ""
+	if useVectors {
+		err := memory.CheckEmbeddingModel(store, vectors, b.Manifest.EmbeddingModel, b.Manifest.EmbeddingDimension)
+		var mismatch *memory.EmbeddingMismatchError
+		if errors.As(err, &mismatch) {
+			useVectors = false
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	merge := memory.Merge{
+		Memories:     plan.Written(),
+		Sessions:     plan.Sessions,
+		SessionTurns: plan.SessionTurns,
+		Embeddings:   make(map[string][]float32),
+	}
+	if plan.ProfileFilled {
+		merge.Project = &plan.Profile
+	}
+	for _, m := range merge.Memories {
+		vec := b.Embeddings[m.ID]
+		if !useVectors || len(vec) != b.Manifest.EmbeddingDimension {
+			unembedded = append(unembedded, m)
+			continue
+		}
+		merge.Embeddings[m.ID] = vec
+	}
+	if err := store.ApplyMerge(merge); err != nil {
+		return nil, err
+	}
+	return unembedded, nil
+}
+
+// sameMemory reports whether a and b hold the same memory, ignoring
+// timestamps.
+func sameMemory(a, b memory.Memory) bool {
+	return a.Content == b.Content &&
+		a.MemoryType == b.MemoryType &&
+		a.Importance == b.Importance &&
+		a.Source == b.Source &&
+		a.Scope == b.Scope &&
+		a.Supersedes == b.Supersedes &&
+		statusOf(a) == statusOf(b) &&
+		sameSet(a.Tags, b.Tags) &&
+		sameSet(a.RelatedFiles, b.RelatedFiles)
+}
+
+func isActive(m memory.Memory) bool { return statusOf(m) == memory.StatusActive }
+
+func statusOf(m memory.Memory) memory.MemoryStatus {
+	if m.Status == "" {
+		return memory.StatusActive
+	}
+	return m.Status
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	return slices.Equal(a, b)
+}
+
+// isEmptyProfileField reports whether a JSON profile field holds nothing.
+func isEmptyProfileField(s string) bool {
+	switch strings.TrimSpace(s) {
+	case "", "{}", "[]", "null":
+		return true
+	}
+	return false
+}
@@ -2,7 +2,7 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -10,23 +10,31 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/agent"
 	ctxpkg "github.com/memvra/memvra/internal/context"
 	"github.com/memvra/memvra/internal/config"
 	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/git"
 	"github.com/memvra/memvra/internal/memory"
 )
 
 func newAskCmd() *cobra.Command {
 	var (
-		model       string
-		files       []string
-		noMemory    bool
-		contextOnly bool
-		verbose     bool
-		extract     bool
-		summarize   bool
-		maxTokens   int
-		temperature float64
+		model         string
+		modelName     string
+		files         []string
+		tags          []string
+		onlyTags      bool
+		noMemory      bool
+		contextOnly   bool
+		noTools       bool
+		verbose       bool
+		extract       bool
+		summarize     bool
+		continueID    string
+		maxIterations int
+		maxTokens     int
+		temperature   float64
 	)
 
 	cmd := &cobra.Command{
@@ -37,12 +45,24 @@ func newAskCmd() *cobra.Command {
 Examples:
   memvra ask "How should I implement the document upload endpoint?"
   memvra ask "Explain the auth flow" --model openai
+  memvra ask "Explain the auth flow" --model openai --model-name gpt-4.1
   memvra ask "Refactor this" --files app/controllers/documents_controller.rb
-  memvra ask "Generate a migration" --context-only`,
+  memvra ask "Why is the cart slow?" --tag frontend --only-tags
+  memvra ask "Generate a migration" --context-only
+  memvra ask --continue "And how would I test that?"
+  memvra ask --continue 3f9c2a1b "What about the retry logic?"
+  memvra ask "Where is the session token validated?" --verbose
+
+--continue resumes the latest session, or the one whose ID (or unique ID
+prefix, as printed by memvra sessions list) is given, replaying its
+transcript to the model and adding the new question to it.
+
+While answering, the model may call read-only tools to search memories,
+read lines of project files, list symbols and show the git diff, for up to
+--max-iterations rounds. --verbose prints each tool call to stderr;
+--no-tools turns them off.`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			question := strings.Join(args, " ")
-
 			root, err := findRoot()
 			if err != nil {
 				return err
@@ -79,7 +99,24 @@ Examples:
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
+
+			// Resume an earlier session: its transcript is sent as history.
+			var (
+				sess  memory.Session
+				prior []memory.SessionTurn
+			)
+			if cmd.Flags().Changed("continue") {
+				sess, args, err = continueSession(store, continueID, args)
+				if err != nil {
+					return err
+				}
+				if prior, err = sessionTranscript(store, sess); err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Continuing session %s (%d earlier turn%s)\n", sess.ID[:min(8, len(sess.ID))], len(prior), pluralS(len(prior)))
+			}
+			question := strings.Join(args, " ")
 
 			// Build context.
 			tokenizer, err := ctxpkg.NewTokenizer()
@@ -92,12 +129,12 @@ Examples:
 			var embedder adapter.LLMAdapter
 			if !noMemory {
 				embedderName := gcfg.DefaultEmbedder
-				embedder, _ = adapter.New(embedderName, gcfg.Ollama.EmbedModel, apiKey(gcfg, embedderName), gcfg.Ollama.Host)
+				embedder, _ = adapter.FromConfig(gcfg, embedderName)
 			}
 
 			vectors := memory.NewVectorStore(database)
 			ranker := memory.NewRanker()
-			orchestrator := memory.NewOrchestrator(store, vectors, ranker, embedder)
+			orchestrator := memory.NewOrchestrator(store, vectors, ranker, compatibleEmbedder(store, vectors, embedder))
 			builder := ctxpkg.NewBuilder(store, orchestrator, formatter, tokenizer)
 
 			builtCtx, err := builder.Build(context.Background(), ctxpkg.BuildOptions{
@@ -110,6 +147,9 @@ Examples:
 				SessionTokenBudget:  gcfg.Context.SessionTokenBudget,
 				SimilarityThreshold: gcfg.Context.SimilarityThreshold,
 				ExtraFiles:          files,
+				ChangedFiles:        git.CaptureWorkingState(root).ChangedFiles(),
+				Tags:                tags,
+				RestrictTags:        onlyTags,
 			})
 			if err != nil {
 				return fmt.Errorf("build context: %w", err)
@@ -133,10 +173,12 @@ Examples:
 			}
 
 			// Call the LLM.
-			llm, err := adapter.New(providerName, gcfg.Ollama.CompletionModel, apiKey(gcfg, providerName), gcfg.Ollama.Host)
+			llm, err := adapter.FromConfig(gcfg, providerName)
 			if err != nil {
 				return fmt.Errorf("init LLM adapter: %w", err)
 			}
+			llm = adapter.WithModel(llm, modelName)
+			models := gcfg.ModelsFor(providerName)
 
 			mt := maxTokens
 			if mt == 0 {
@@ -147,46 +189,64 @@ Examples:
 				temp = 0.7
 			}
 
-			stream, err := llm.Complete(context.Background(), adapter.CompletionRequest{
+			// Unless --no-tools is given, the model may call read-only tools
+			// to look up what the context is missing.
+			var tools agent.Executor
+			if !noTools {
+				tools = agent.NewToolset(root, store, orchestrator, gcfg.Context.SimilarityThreshold)
+			}
+			response, err := agent.Run(context.Background(), llm, adapter.CompletionRequest{
 				SystemPrompt: builtCtx.SystemPrompt,
 				Context:      builtCtx.ContextText,
+				History:      transcriptHistory(prior, tokenizer.Count, llm.Info().MaxContextWindow/2),
 				UserMessage:  question,
+				Model:        llm.Info().Name,
 				MaxTokens:    mt,
 				Temperature:  temp,
 				Stream:       gcfg.Output.Stream,
+			}, tools, agent.Options{
+				MaxIterations: maxIterations,
+				OnText:        func(text string) { fmt.Print(text) },
+				OnToolCall: func(call adapter.ToolCall, result string, err error) {
+					if !verbose {
+						return
+					}
+					fmt.Fprintf(os.Stderr, "\n  tool: %s %s\n", call.Name, call.Arguments)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "    error: %v\n", err)
+					} else {
+						fmt.Fprintf(os.Stderr, "    → %s\n", toolResultSummary(result))
+					}
+				},
 			})
-			if err != nil {
-				return fmt.Errorf("LLM request: %w", err)
-			}
-
-			var responseBuf strings.Builder
-			for chunk := range stream {
-				if chunk.Error != nil {
-					return fmt.Errorf("stream error: %w", chunk.Error)
-				}
-				fmt.Print(chunk.Text)
-				responseBuf.WriteString(chunk.Text)
+			// A model stopped at the tool call limit still answered in
+			// part; that answer is recorded before the error is reported.
+			limited := errors.Is(err, agent.ErrIterationLimit)
+			if err != nil && !limited {
+				return err
 			}
 			fmt.Println()
 
 			// Record the session (best-effort — non-fatal on failure).
-			var sessID string
-			if sourcesJSON, err := json.Marshal(builtCtx.Sources); err == nil {
-				sessID, _ = store.InsertSessionReturningID(memory.Session{
-					Question:        question,
-					ContextUsed:     string(sourcesJSON),
-					ResponseSummary: truncateLabel(responseBuf.String(), 300),
-					ModelUsed:       providerName,
-					TokensUsed:      builtCtx.TokensUsed,
-				})
+			turns := append(prior, memory.SessionTurn{
+				Question: question,
+				Response: response,
+				Summary:  truncateLabel(response, 300),
+				Sources:  builtCtx.Sources,
+			})
+			sess.ModelUsed = providerName
+			sess.TokensUsed += builtCtx.TokensUsed
+			recorded := recordTurn(store, &sess, turns) == nil
+			if limited {
+				return err
 			}
 
 			// Auto-summarize session if enabled.
 			doSummarize := gcfg.Summarization.Enabled || summarize
-			if doSummarize && sessID != "" {
+			if doSummarize && recorded {
 				summary, err := memory.SummarizeSession(
-					context.Background(), llm,
-					question, responseBuf.String(),
+					context.Background(), adapter.WithModel(llm, models.Summarization),
+					question, response,
 					gcfg.Summarization.MaxTokens,
 				)
 				if err != nil {
@@ -194,7 +254,10 @@ Examples:
 						fmt.Fprintf(os.Stderr, "  warn: session summarization failed: %v\n", err)
 					}
 				} else if summary != "" {
-					_ = store.UpdateSessionSummary(sessID, summary)
+					last := &turns[len(turns)-1]
+					last.Summary = summary
+					_ = store.UpdateSessionTurnSummary(sess.ID, last.Turn, summary)
+					_ = store.UpdateSessionSummary(sess.ID, transcriptSummary(turns))
 					if verbose {
 						fmt.Fprintf(os.Stderr, "  session summary stored (%d chars)\n", len(summary))
 					}
@@ -204,7 +267,7 @@ Examples:
 			// Auto-extract memories from the response if enabled.
 			doExtract := gcfg.Extraction.Enabled || extract
 			if doExtract {
-				extracted, err := memory.ExtractMemories(context.Background(), llm, responseBuf.String(), gcfg.Extraction.MaxExtracts)
+				extracted, err := memory.ExtractMemories(context.Background(), adapter.WithModel(llm, models.Extraction), response, gcfg.Extraction.MaxExtracts)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "warn: memory extraction failed: %v\n", err)
 				} else if len(extracted) == 0 {
@@ -212,17 +275,27 @@ Examples:
 						fmt.Fprintf(os.Stderr, "  (no memories extracted from response)\n")
 					}
 				} else {
+					orchestrator.SetDedupe(dedupeOptions(gcfg, llm))
+					outcomes := make(map[memory.SaveOutcome]int)
 					for _, m := range extracted {
-						saved, saveErr := orchestrator.Remember(context.Background(), m.Content, m.MemoryType, "extracted")
+						res, saveErr := orchestrator.Save(context.Background(), m)
 						if saveErr != nil {
 							continue
 						}
+						outcomes[res.Outcome]++
 						if verbose {
-							fmt.Fprintf(os.Stderr, "  extracted (%s): %s\n", saved.MemoryType, truncateLabel(saved.Content, 60))
+							fmt.Fprintf(os.Stderr, "  extracted (%s, %s): %s\n", res.Memory.MemoryType, res.Outcome, truncateLabel(res.Memory.Content, 60))
+						}
+						if res.Outcome == memory.OutcomeConflict {
+							fmt.Fprintf(os.Stderr, "  conflict: %q may contradict %q (id: %s)\n",
+								truncateLabel(res.Memory.Content, 60), truncateLabel(res.Existing.Content, 60), res.Existing.ID)
 						}
 					}
 					if !verbose {
-						fmt.Fprintf(os.Stderr, "  %d memor%s extracted and stored.\n", len(extracted), pluralY(len(extracted)))
+						fmt.Fprintf(os.Stderr, "  %d memor%s extracted: %d stored, %d merged, %d conflict%s.\n",
+							len(extracted), pluralY(len(extracted)),
+							outcomes[memory.OutcomeStored]+outcomes[memory.OutcomeConflict],
+							outcomes[memory.OutcomeMerged], outcomes[memory.OutcomeConflict], pluralS(outcomes[memory.OutcomeConflict]))
 					}
 					AutoExport(root, store)
 				}
@@ -232,13 +305,20 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&model, "model", "m", "", "LLM provider override: claude, openai, gemini, ollama")
+	cmd.Flags().StringVarP(&model, "model", "m", "", "LLM provider override: claude, openai, gemini, ollama, or a provider profile name")
+	cmd.Flags().StringVar(&modelName, "model-name", "", "model to run with the provider, e.g. gpt-4.1 (default: [models.<provider>] completion)")
 	cmd.Flags().StringArrayVarP(&files, "files", "f", nil, "files to always include in context (comma-separated paths)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "prioritise memories with these tags (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&onlyTags, "only-tags", false, "with --tag, leave out memories tagged only with other tags")
 	cmd.Flags().BoolVar(&noMemory, "no-memory", false, "skip memory retrieval, use raw question only")
 	cmd.Flags().BoolVar(&contextOnly, "context-only", false, "print injected context without calling LLM")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "show which memories and chunks were included in context")
+	cmd.Flags().BoolVar(&noTools, "no-tools", false, "don't let the model call tools to read files, symbols, memories or the git diff")
+	cmd.Flags().IntVar(&maxIterations, "max-iterations", agent.DefaultMaxIterations, "maximum rounds of tool calls before the model must answer")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "show which memories and chunks were included in context, and the tool calls made")
 	cmd.Flags().BoolVarP(&extract, "extract", "e", false, "auto-extract decisions and constraints from the response")
 	cmd.Flags().BoolVarP(&summarize, "summarize", "s", false, "auto-summarize this session with an LLM call")
+	cmd.Flags().StringVar(&continueID, "continue", "", "continue the latest session, or the session with this ID")
+	cmd.Flags().Lookup("continue").NoOptDefVal = continueLast
 	cmd.Flags().IntVar(&maxTokens, "max-tokens", 4096, "maximum response tokens")
 	cmd.Flags().Float64Var(&temperature, "temperature", 0.7, "sampling temperature")
 
@@ -254,6 +334,16 @@ func truncateLabel(s string, max int) string {
 	return string(runes[:max]) + "..."
 }
 
+// toolResultSummary shortens a tool result to its first line and size for
+// the --verbose transcript.
+func toolResultSummary(result string) string {
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	if len(lines) == 1 {
+		return truncateLabel(lines[0], 100)
+	}
+	return fmt.Sprintf("%s (%d lines)", truncateLabel(lines[0], 100), len(lines))
+}
+
 // pluralY returns "y" for n==1, "ies" otherwise (for "memory"/"memories").
 func pluralY(n int) string {
 	if n == 1 {
@@ -261,17 +351,3 @@ func pluralY(n int) string {
 	}
 	return "ies"
 }
-
-// apiKey returns the correct API key from the global config for the given provider.
-func apiKey(cfg config.GlobalConfig, provider string) string {
-	switch provider {
-	case adapter.ProviderClaude:
-		return cfg.Keys.Anthropic
-	case adapter.ProviderOpenAI:
-		return cfg.Keys.OpenAI
-	case adapter.ProviderGemini:
-		return cfg.Keys.Gemini
-	default:
-		return ""
-	}
-}
@@ -7,11 +7,11 @@ import (
 )
 
 // autoExportFilenames returns the filenames that auto-export would generate
-// for the given config.
-func autoExportFilenames(cfg config.AutoExportConfig) []string {
+// for the given config, including those of the project's template formats.
+func autoExportFilenames(cfg config.AutoExportConfig, templates export.Templates) []string {
 	var names []string
 	for _, f := range cfg.Formats {
-		if name := export.FormatToFilename(f); name != "" {
+		if name := templates.Filename(f); name != "" {
 			names = append(names, name)
 		}
 	}
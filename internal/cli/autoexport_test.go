@@ -21,6 +21,11 @@ func TestFormatToFilename(t *testing.T) {
 		{"cursor", ".cursorrules"},
 		{"markdown", "PROJECT_CONTEXT.md"},
 		{"json", "memvra-context.json"},
+		{"agents", "AGENTS.md"},
+		{"gemini", "GEMINI.md"},
+		{"copilot", ".github/copilot-instructions.md"},
+		{"windsurf", ".windsurfrules"},
+		{"cursor-mdc", ".cursor/rules/memvra.mdc"},
 		{"unknown", ""},
 	}
 	for _, tt := range tests {
@@ -38,7 +43,7 @@ func TestAutoExportFilenames(t *testing.T) {
 		Enabled: true,
 		Formats: []string{"claude", "cursor", "markdown", "json"},
 	}
-	names := autoExportFilenames(cfg)
+	names := autoExportFilenames(cfg, nil)
 	if len(names) != 4 {
 		t.Fatalf("expected 4 filenames, got %d", len(names))
 	}
@@ -229,3 +234,106 @@ func TestAutoExport_UpdatesOnRerun(t *testing.T) {
 		t.Error("should contain newly added memory")
 	}
 }
+
+func TestAutoExport_NestedScopedMemories(t *testing.T) {
+	root, store := setupAutoExportTestDB(t)
+	os.MkdirAll(filepath.Join(root, "services", "billing"), 0o755)
+
+	store.InsertMemory(memory.Memory{Content: "use PostgreSQL", MemoryType: memory.TypeDecision})
+	id, _ := store.InsertMemory(memory.Memory{Content: "amounts are in cents", MemoryType: memory.TypeConvention, Scope: "services/billing"})
+
+	AutoExport(root, store)
+
+	rootFile, _ := os.ReadFile(filepath.Join(root, "CLAUDE.md"))
+	if strings.Contains(string(rootFile), "amounts are in cents") {
+		t.Error("root CLAUDE.md should not contain scoped memories")
+	}
+	if !strings.Contains(string(rootFile), "services/billing/CLAUDE.md") {
+		t.Error("root CLAUDE.md should point to the nested file")
+	}
+
+	nestedPath := filepath.Join(root, "services", "billing", "CLAUDE.md")
+	nested, err := os.ReadFile(nestedPath)
+	if err != nil {
+		t.Fatalf("expected nested CLAUDE.md: %v", err)
+	}
+	if !strings.Contains(string(nested), "amounts are in cents") || strings.Contains(string(nested), "use PostgreSQL") {
+		t.Errorf("nested CLAUDE.md should hold only scoped memories:\n%s", nested)
+	}
+	if !strings.Contains(string(nested), "../../CLAUDE.md") {
+		t.Errorf("nested CLAUDE.md should link to the root file:\n%s", nested)
+	}
+
+	// Formats without nesting keep scoped memories, marked with their scope.
+	cursor, _ := os.ReadFile(filepath.Join(root, ".cursorrules"))
+	if !strings.Contains(string(cursor), "amounts are in cents (applies to services/billing/)") {
+		t.Errorf(".cursorrules should include the scoped memory:\n%s", cursor)
+	}
+
+	gitignore, _ := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if !strings.Contains(string(gitignore), "services/billing/CLAUDE.md") {
+		t.Errorf(".gitignore should cover the nested file:\n%s", gitignore)
+	}
+
+	// Once the scope has no memories left, its nested file is removed.
+	store.DeleteMemory(id)
+	AutoExport(root, store)
+	if _, err := os.Stat(nestedPath); !os.IsNotExist(err) {
+		t.Error("stale nested CLAUDE.md should be removed")
+	}
+}
+
+func TestAutoExport_NestedKeepsHandWrittenFile(t *testing.T) {
+	root, store := setupAutoExportTestDB(t)
+	os.MkdirAll(filepath.Join(root, "web"), 0o755)
+	handWritten := "# Web rules\n\nWritten by hand.\n"
+	os.WriteFile(filepath.Join(root, "web", "CLAUDE.md"), []byte(handWritten), 0o644)
+
+	store.InsertMemory(memory.Memory{Content: "use CSS modules", MemoryType: memory.TypeConvention, Scope: "web"})
+	AutoExport(root, store)
+
+	content, _ := os.ReadFile(filepath.Join(root, "web", "CLAUDE.md"))
+	if !strings.HasPrefix(string(content), handWritten) || !strings.Contains(string(content), "use CSS modules") {
+		t.Errorf("nested file should keep the hand-written rules and add the managed region:\n%s", content)
+	}
+
+	// Once the scope is empty, only the managed region is removed.
+	memories, _ := store.ListMemories("")
+	store.DeleteMemory(memories[0].ID)
+	AutoExport(root, store)
+	content, _ = os.ReadFile(filepath.Join(root, "web", "CLAUDE.md"))
+	if strings.TrimSpace(string(content)) != strings.TrimSpace(handWritten) {
+		t.Errorf("hand-written nested file should survive without the region:\n%s", content)
+	}
+}
+
+func TestAutoExport_PreservesHandWrittenContent(t *testing.T) {
+	root, store := setupAutoExportTestDB(t)
+	before := "# House rules\n\nAlways run make lint.\n"
+	after := "\n## Links\n\nSee the wiki.\n"
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte(before), 0o644)
+
+	store.InsertMemory(memory.Memory{Content: "use PostgreSQL", MemoryType: memory.TypeDecision})
+	AutoExport(root, store)
+
+	content, _ := os.ReadFile(filepath.Join(root, "CLAUDE.md"))
+	text := string(content)
+	if !strings.HasPrefix(text, before) || !strings.Contains(text, "<!-- memvra:begin") ||
+		!strings.Contains(text, "use PostgreSQL") || !strings.HasSuffix(text, "<!-- memvra:end -->\n") {
+		t.Fatalf("first export should append a managed region:\n%s", text)
+	}
+
+	// Content added after the region survives the next export.
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte(text+after), 0o644)
+	store.InsertMemory(memory.Memory{Content: "switched to Redis", MemoryType: memory.TypeDecision})
+	AutoExport(root, store)
+
+	content, _ = os.ReadFile(filepath.Join(root, "CLAUDE.md"))
+	text = string(content)
+	if !strings.HasPrefix(text, before) || !strings.HasSuffix(text, after) || !strings.Contains(text, "switched to Redis") {
+		t.Errorf("re-export should only replace the managed region:\n%s", text)
+	}
+	if strings.Count(text, "<!-- memvra:begin") != 1 {
+		t.Errorf("expected exactly one managed region:\n%s", text)
+	}
+}
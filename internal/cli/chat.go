@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/config"
+	ctxpkg "github.com/memvra/memvra/internal/context"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/git"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newChatCmd() *cobra.Command {
+	var (
+		model       string
+		modelName   string
+		files       []string
+		tags        []string
+		onlyTags    bool
+		verbose     bool
+		summarize   bool
+		maxTokens   int
+		temperature float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "chat",
+		Short: "Have a conversation with your LLM, with project context injected",
+		Long: `Start an interactive conversation. Every message is sent with the
+earlier turns, so follow-up questions work, and the project context is
+rebuilt for each message so it stays relevant as the topic moves.
+
+The whole conversation is recorded as one session, with its transcript
+and a summary line per turn (see memvra sessions). Type /clear to start
+a new conversation and /exit (or Ctrl-D) to quit.
+
+Examples:
+  memvra chat
+  memvra chat --model openai --tag billing`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+			dbPath, err := ensureInitialized(root)
+			if err != nil {
+				return err
+			}
+
+			gcfg, err := config.LoadGlobal()
+			if err != nil {
+				gcfg = config.DefaultGlobal()
+			}
+			pcfg, _ := config.LoadProject(root)
+			if len(pcfg.AlwaysInclude) > 0 {
+				files = append(pcfg.AlwaysInclude, files...)
+			}
+
+			providerName := gcfg.DefaultModel
+			if pcfg.DefaultModel != "" {
+				providerName = pcfg.DefaultModel
+			}
+			if model != "" {
+				providerName = model
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+			store := openStore(database)
+
+			tokenizer, err := ctxpkg.NewTokenizer()
+			if err != nil {
+				return fmt.Errorf("init tokenizer: %w", err)
+			}
+			embedder, _ := adapter.FromConfig(gcfg, gcfg.DefaultEmbedder)
+			vectors := memory.NewVectorStore(database)
+			orchestrator := memory.NewOrchestrator(store, vectors, memory.NewRanker(), compatibleEmbedder(store, vectors, embedder))
+			builder := ctxpkg.NewBuilder(store, orchestrator, ctxpkg.NewFormatter(), tokenizer)
+
+			llm, err := adapter.FromConfig(gcfg, providerName)
+			if err != nil {
+				return fmt.Errorf("init LLM adapter: %w", err)
+			}
+			llm = adapter.WithModel(llm, modelName)
+			summarizer := adapter.WithModel(llm, gcfg.ModelsFor(providerName).Summarization)
+			doSummarize := gcfg.Summarization.Enabled || summarize
+			// Earlier turns may use up to half the model's window; the
+			// oldest are dropped beyond that.
+			historyBudget := llm.Info().MaxContextWindow / 2
+
+			fmt.Printf("Chatting with %s about %s. /clear starts over, /exit quits.\n", providerName, projectName(store))
+
+			// Files changed in the working tree steer which memories are
+			// used; they are looked up once rather than on every turn.
+			changedFiles := git.CaptureWorkingState(root).ChangedFiles()
+
+			var (
+				turns    []memory.SessionTurn
+				sess     memory.Session
+				recorded bool
+			)
+			// Put the conversation into the exported context files on exit.
+			defer func() {
+				if recorded {
+					AutoExport(root, store)
+				}
+			}()
+			in := bufio.NewReader(os.Stdin)
+			for {
+				fmt.Print("\n> ")
+				line, readErr := in.ReadString('\n')
+				question := strings.TrimSpace(line)
+				if readErr != nil && question == "" {
+					if errors.Is(readErr, io.EOF) {
+						fmt.Println()
+						return nil
+					}
+					return readErr
+				}
+
+				switch question {
+				case "":
+					continue
+				case "/exit", "/quit":
+					return nil
+				case "/clear":
+					turns, sess = nil, memory.Session{}
+					fmt.Println("Started a new conversation.")
+					continue
+				}
+
+				builtCtx, err := builder.Build(context.Background(), ctxpkg.BuildOptions{
+					Question:            question,
+					ProjectRoot:         root,
+					MaxTokens:           gcfg.Context.MaxTokens,
+					TopKChunks:          gcfg.Context.TopKChunks,
+					TopKMemories:        gcfg.Context.TopKMemories,
+					TopKSessions:        gcfg.Context.TopKSessions,
+					SessionTokenBudget:  gcfg.Context.SessionTokenBudget,
+					SimilarityThreshold: gcfg.Context.SimilarityThreshold,
+					ExtraFiles:          files,
+					ChangedFiles:        changedFiles,
+					Tags:                tags,
+					RestrictTags:        onlyTags,
+				})
+				if err != nil {
+					return fmt.Errorf("build context: %w", err)
+				}
+				if verbose && len(builtCtx.Sources) > 0 {
+					fmt.Fprintln(os.Stderr, "=== Sources included ===")
+					for _, s := range builtCtx.Sources {
+						fmt.Fprintf(os.Stderr, "  • %s\n", s)
+					}
+					fmt.Fprintln(os.Stderr)
+				}
+
+				stream, err := llm.Complete(context.Background(), adapter.CompletionRequest{
+					SystemPrompt: builtCtx.SystemPrompt,
+					Context:      builtCtx.ContextText,
+					History:      transcriptHistory(turns, tokenizer.Count, historyBudget),
+					UserMessage:  question,
+					Model:        llm.Info().Name,
+					MaxTokens:    maxTokens,
+					Temperature:  temperature,
+					Stream:       gcfg.Output.Stream,
+				})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "  Error: LLM request: %v\n", err)
+					continue
+				}
+				var responseBuf strings.Builder
+				var streamErr error
+				for chunk := range stream {
+					if chunk.Error != nil {
+						streamErr = chunk.Error
+						continue
+					}
+					fmt.Print(chunk.Text)
+					responseBuf.WriteString(chunk.Text)
+				}
+				fmt.Println()
+				if streamErr != nil {
+					// The turn is not kept, so the question can be asked again.
+					fmt.Fprintf(os.Stderr, "  Error: %v\n", streamErr)
+					continue
+				}
+
+				turn := memory.SessionTurn{Question: question, Response: responseBuf.String(), Sources: builtCtx.Sources}
+				turn.Summary = truncateLabel(strings.Join(strings.Fields(turn.Response), " "), 200)
+				if doSummarize {
+					summary, err := memory.SummarizeSession(context.Background(), summarizer, question, turn.Response, gcfg.Summarization.MaxTokens)
+					if err != nil {
+						if verbose {
+							fmt.Fprintf(os.Stderr, "  warn: turn summarization failed: %v\n", err)
+						}
+					} else if summary != "" {
+						turn.Summary = summary
+					}
+				}
+				turns = append(turns, turn)
+
+				// Record the conversation after every turn, so an interrupted
+				// chat still leaves its session (best-effort).
+				sess.ModelUsed = providerName
+				sess.TokensUsed += builtCtx.TokensUsed
+				if err := recordTurn(store, &sess, turns); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: record session: %v\n", err)
+				} else {
+					recorded = true
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&model, "model", "m", "", "LLM provider override: claude, openai, gemini, ollama, or a provider profile name")
+	cmd.Flags().StringVar(&modelName, "model-name", "", "model to run with the provider (default: [models.<provider>] completion)")
+	cmd.Flags().StringArrayVarP(&files, "files", "f", nil, "files to always include in context (comma-separated paths)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "prioritise memories with these tags (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&onlyTags, "only-tags", false, "with --tag, leave out memories tagged only with other tags")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "show which memories and chunks were included in context")
+	cmd.Flags().BoolVarP(&summarize, "summarize", "s", false, "summarize each turn with an LLM call")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 4096, "maximum tokens per response")
+	cmd.Flags().Float64Var(&temperature, "temperature", 0.7, "sampling temperature")
+
+	return cmd
+}
+
+// projectName returns the indexed project's name for display.
+func projectName(store *memory.Store) string {
+	if proj, err := store.GetProject(); err == nil && proj.Name != "" {
+		return proj.Name
+	}
+	return "this project"
+}
@@ -19,6 +19,7 @@ func newContextCmd() *cobra.Command {
 	var section string
 	var export bool
 	var edit bool
+	var tags []string
 
 	cmd := &cobra.Command{
 		Use:   "context",
@@ -30,6 +31,7 @@ Sections: profile, decisions, conventions, constraints, notes, todos
 Examples:
   memvra context
   memvra context --section decisions
+  memvra context --tag frontend
   memvra context --export
   memvra context --edit`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -49,7 +51,7 @@ Examples:
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 
 			proj, err := store.GetProject()
 			if err != nil {
@@ -107,7 +109,7 @@ Examples:
 					continue // a specific memory section was requested — skip non-matches
 				}
 
-				memories, err := store.ListMemories(ts.mt)
+				memories, err := store.ListMemories(ts.mt, tags...)
 				if err != nil {
 					continue
 				}
@@ -150,6 +152,7 @@ Examples:
 	cmd.Flags().StringVarP(&section, "section", "s", "", "Show only a specific section: profile, decisions, conventions, constraints, notes, todos")
 	cmd.Flags().BoolVar(&export, "export", false, "Also write context to .memvra/context.md")
 	cmd.Flags().BoolVar(&edit, "edit", false, "Open .memvra/context.md in $EDITOR")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Show only memories with one of these tags (repeatable or comma-separated)")
 
 	return cmd
 }
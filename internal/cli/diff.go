@@ -71,7 +71,7 @@ Examples:
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 			gcfg, _ := config.LoadGlobal()
 
 			if !gcfg.Output.Color || os.Getenv("NO_COLOR") != "" {
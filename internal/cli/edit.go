@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newEditCmd() *cobra.Command {
+	var content string
+	var memType string
+	var importance float64
+	var files []string
+	var clearFiles bool
+	var scope string
+
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Change a stored memory in place",
+		Long: `Edit an existing memory without losing its ID, creation time or history.
+
+Without flags the memory's text is opened in $EDITOR. With flags only the
+given fields change. Changed text is re-embedded.
+
+Examples:
+  memvra edit 3f9c2a...
+  memvra edit 3f9c2a... --type constraint --importance 0.9
+  memvra edit 3f9c2a... --file internal/auth/jwt.go --file internal/auth/middleware.go
+  memvra edit 3f9c2a... --scope services/billing
+  memvra edit 3f9c2a... --scope .
+  memvra edit 3f9c2a... --content "Use RS256 for all JWTs"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+
+			dbPath := config.ProjectDBPath(root)
+			if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+				return fmt.Errorf("memvra not initialized. Run `memvra init` first")
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+
+			m, err := store.GetMemoryByID(args[0])
+			if err != nil {
+				return err
+			}
+			updated := m
+
+			flags := cmd.Flags()
+			if flags.Changed("content") {
+				updated.Content = strings.TrimSpace(content)
+			}
+			if flags.Changed("type") {
+				updated.MemoryType = memory.MemoryType(strings.ToLower(memType))
+				if !memory.ValidMemoryType(updated.MemoryType) {
+					return fmt.Errorf("unknown memory type %q (valid: decision, convention, constraint, note, todo)", memType)
+				}
+			}
+			if flags.Changed("importance") {
+				if importance < 0 || importance > 1 {
+					return fmt.Errorf("importance must be between 0 and 1")
+				}
+				updated.Importance = importance
+			}
+			if clearFiles {
+				updated.RelatedFiles = nil
+			}
+			if flags.Changed("file") {
+				updated.RelatedFiles = nil
+				for _, f := range files {
+					if strings.TrimSpace(f) == "" {
+						continue
+					}
+					updated.RelatedFiles = append(updated.RelatedFiles, memory.NormalizeFilePath(root, f))
+				}
+			}
+
+			if flags.Changed("scope") {
+				if updated.Scope, err = memory.NormalizeScope(root, scope); err != nil {
+					return err
+				}
+				if err := memory.CheckScope(root, updated.Scope); err != nil {
+					return err
+				}
+			}
+
+			// No edit flags: edit the text interactively.
+			if !slices.ContainsFunc(editFlags, flags.Changed) {
+				text, err := editText(m.Content)
+				if err != nil {
+					return err
+				}
+				updated.Content = text
+			}
+
+			if updated.Content == "" {
+				return fmt.Errorf("memory content can't be empty; use `memvra forget --id %s` to remove it", m.ID)
+			}
+			if updated.Content == m.Content && updated.MemoryType == m.MemoryType &&
+				updated.Importance == m.Importance && slices.Equal(updated.RelatedFiles, m.RelatedFiles) &&
+				updated.Scope == m.Scope {
+				fmt.Println("No changes.")
+				return nil
+			}
+
+			gcfg, _ := config.LoadGlobal()
+			vectors := memory.NewVectorStore(database)
+			orchestrator := memory.NewOrchestrator(store, vectors, memory.NewRanker(),
+				compatibleEmbedder(store, vectors, buildEmbedder(gcfg)))
+
+			saved, err := orchestrator.UpdateMemory(context.Background(), updated)
+			if err != nil {
+				return fmt.Errorf("update memory: %w", err)
+			}
+
+			fmt.Printf("Updated %s\n", saved.MemoryType)
+			fmt.Printf("  %q\n", saved.Content)
+			fmt.Printf("  id: %s\n", saved.ID)
+			fmt.Printf("  importance: %.2f\n", saved.Importance)
+			if len(saved.RelatedFiles) > 0 {
+				fmt.Printf("  files: %s\n", strings.Join(saved.RelatedFiles, ", "))
+			}
+			if saved.Scope != "" {
+				fmt.Printf("  scope: %s/\n", saved.Scope)
+			}
+
+			saveShared(store)
+			AutoExport(root, store)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&content, "content", "", "Replace the memory text without opening an editor")
+	cmd.Flags().StringVarP(&memType, "type", "t", "", "Change the memory type: decision, convention, constraint, note, todo")
+	cmd.Flags().Float64Var(&importance, "importance", 0, "Change the importance (0.0 to 1.0)")
+	cmd.Flags().StringArrayVar(&files, "file", nil, "Set the related files (repeatable; replaces the current list)")
+	cmd.Flags().BoolVar(&clearFiles, "clear-files", false, "Remove all related files")
+	cmd.Flags().StringVar(&scope, "scope", "", "Set the directory the memory applies to (\".\" for the whole project)")
+
+	return cmd
+}
+
+// editFlags are the flags of `memvra edit` that change a memory.
+var editFlags = []string{"content", "type", "importance", "file", "clear-files", "scope"}
+
+// editText opens text in $EDITOR via a temporary file and returns the
+// edited, trimmed result.
+func editText(text string) (string, error) {
+	f, err := os.CreateTemp("", "memvra-memory-*.md")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	if _, err := f.WriteString(text + "\n"); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := openInEditor(path); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read edited memory: %w", err)
+	}
+	return strings.TrimSpace(string(edited)), nil
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func TestEdit_EmptyFileClearsWithoutEditor(t *testing.T) {
+	root, store := setupAutoExportTestDB(t)
+	t.Chdir(root)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("EDITOR", "")
+	t.Setenv("VISUAL", "")
+
+	id, _ := store.InsertMemory(memory.Memory{Content: "use JWT", MemoryType: memory.TypeDecision, RelatedFiles: []string{"auth.go"}})
+
+	cmd := newEditCmd()
+	cmd.SetArgs([]string{id, "--file", ""})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("edit --file \"\": %v", err)
+	}
+	m, _ := store.GetMemoryByID(id)
+	if len(m.RelatedFiles) != 0 {
+		t.Errorf("an empty --file should leave no related files, got %q", m.RelatedFiles)
+	}
+}
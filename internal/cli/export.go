@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/memvra/memvra/internal/config"
+	ctxpkg "github.com/memvra/memvra/internal/context"
 	"github.com/memvra/memvra/internal/db"
 	"github.com/memvra/memvra/internal/export"
 	gitpkg "github.com/memvra/memvra/internal/git"
@@ -17,21 +18,29 @@ import (
 
 func newExportCmd() *cobra.Command {
 	var (
-		format  string
-		section string
+		format    string
+		section   string
+		tags      []string
+		maxTokens int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "export",
-		Short: "Export context to CLAUDE.md, .cursorrules, or markdown",
+		Short: "Export context to CLAUDE.md, AGENTS.md, .cursorrules, and other formats",
 		Long: `Render project memory in a format compatible with other AI tools.
 Output is written to stdout — pipe it to a file.
 
 Examples:
   memvra export --format claude > CLAUDE.md
   memvra export --format cursor > .cursorrules
+  memvra export --format agents > AGENTS.md
   memvra export --format markdown > PROJECT_CONTEXT.md
-  memvra export --format markdown --section decisions`,
+  memvra export --format markdown --section decisions
+  memvra export --format claude --tag frontend > web/CLAUDE.md
+  memvra export --format claude --max-tokens 4000
+
+Custom formats are defined by text/template files in .memvra/templates:
+.memvra/templates/onboarding.tmpl adds the format "onboarding".`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, err := findRoot()
 			if err != nil {
@@ -49,7 +58,7 @@ Examples:
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 
 			proj, err := store.GetProject()
 			if err != nil {
@@ -67,27 +76,40 @@ Examples:
 				}
 			}
 
-			memories, err := store.ListMemories(filterType)
+			memories, err := store.ListMemories(filterType, tags...)
 			if err != nil {
 				return fmt.Errorf("list memories: %w", err)
 			}
 
-			exporter, ok := export.Get(strings.ToLower(format))
+			templates, err := export.LoadTemplates(root)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			exporter, ok := templates.Get(strings.ToLower(format))
 			if !ok {
 				return fmt.Errorf("unknown format %q; valid formats: %s",
-					format, strings.Join(export.ValidFormats(), ", "))
+					format, strings.Join(templates.Formats(), ", "))
 			}
 
 			sessions, _ := store.GetLastNSessions(5)
 			gitState := gitpkg.CaptureWorkingState(root)
 
-			output, err := exporter.Export(export.ExportData{
+			count := func(string) int { return 0 }
+			if maxTokens > 0 {
+				tokenizer, err := ctxpkg.NewTokenizer()
+				if err != nil {
+					return fmt.Errorf("init tokenizer: %w", err)
+				}
+				count = tokenizer.Count
+			}
+
+			output, err := export.FitBudget(exporter, export.ExportData{
 				Project:  proj,
 				Stack:    ts,
 				Memories: memories,
 				Sessions: sessions,
 				GitState: gitState,
-			})
+			}, maxTokens, count)
 			if err != nil {
 				return fmt.Errorf("export: %w", err)
 			}
@@ -98,9 +120,13 @@ Examples:
 	}
 
 	cmd.Flags().StringVar(&format, "format", "markdown",
-		"output format: claude, cursor, markdown")
+		"output format: "+strings.Join(export.ValidFormats(), ", "))
 	cmd.Flags().StringVarP(&section, "section", "s", "",
 		"export only memories of this type: decision, convention, constraint, note, todo")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil,
+		"export only memories with one of these tags (repeatable or comma-separated)")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0,
+		"drop the least important and oldest memories and sessions to fit this many tokens")
 
 	return cmd
 }
@@ -16,7 +16,9 @@ import (
 func newForgetCmd() *cobra.Command {
 	var memID string
 	var memType string
+	var tag string
 	var all bool
+	var archive bool
 
 	cmd := &cobra.Command{
 		Use:   "forget",
@@ -25,7 +27,9 @@ func newForgetCmd() *cobra.Command {
 
 Examples:
   memvra forget --id mem_abc123
+  memvra forget --id mem_abc123 --archive
   memvra forget --type todo
+  memvra forget --tag legacy-api
   memvra forget --all`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, err := findRoot()
@@ -44,7 +48,7 @@ Examples:
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 
 			switch {
 			case all:
@@ -69,6 +73,19 @@ Examples:
 				}
 				fmt.Printf("Deleted %d %s memories.\n", n, mt)
 
+			case tag != "":
+				n, err := store.DeleteMemoriesByTag(tag)
+				if err != nil {
+					return fmt.Errorf("delete memories: %w", err)
+				}
+				fmt.Printf("Deleted %d memories tagged %q.\n", n, tag)
+
+			case memID != "" && archive:
+				if err := store.SetMemoryStatus(memID, memory.StatusArchived); err != nil {
+					return err
+				}
+				fmt.Printf("Archived memory %s.\n", memID)
+
 			case memID != "":
 				if err := store.DeleteMemory(memID); err != nil {
 					return err
@@ -77,16 +94,22 @@ Examples:
 
 			default:
 				// Interactive mode: list memories and let user choose.
-				return forgetInteractive(store)
+				if err := forgetInteractive(store); err != nil {
+					return err
+				}
 			}
 
+			saveShared(store)
+			AutoExport(root, store)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&memID, "id", "", "Delete a specific memory by ID")
 	cmd.Flags().StringVarP(&memType, "type", "t", "", "Delete all memories of this type")
+	cmd.Flags().StringVar(&tag, "tag", "", "Delete all memories with this tag")
 	cmd.Flags().BoolVar(&all, "all", false, "Delete all memories (requires confirmation)")
+	cmd.Flags().BoolVar(&archive, "archive", false, "With --id, archive the memory instead of deleting it (kept in history)")
 
 	return cmd
 }
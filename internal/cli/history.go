@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history <id>",
+		Short: "Show how a memory has been superseded over time",
+		Long: `Show the supersession chain a memory belongs to, oldest first: the
+memories it replaced, the memory itself, and anything that replaced it.
+
+Superseded and archived memories are left out of context and exports but
+remain viewable here.
+
+Examples:
+  memvra history 3f9c2a...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+
+			dbPath := config.ProjectDBPath(root)
+			if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+				return fmt.Errorf("memvra not initialized. Run `memvra init` first")
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+			if gcfg, _ := config.LoadGlobal(); !gcfg.Output.Color || os.Getenv("NO_COLOR") != "" {
+				disableColors()
+			}
+
+			history, err := store.MemoryHistory(args[0])
+			if err != nil {
+				return err
+			}
+
+			for i, m := range history {
+				marker := " "
+				if m.ID == args[0] {
+					marker = "*"
+				}
+				color := cGreen
+				if m.Status != memory.StatusActive {
+					color = cDim
+				}
+				fmt.Printf("%s %s%-10s%s %s  [%s]\n", marker, color, m.Status, cReset,
+					m.CreatedAt.Local().Format("2006-01-02 15:04"), m.MemoryType)
+				fmt.Printf("    %s\n", m.Content)
+				fmt.Printf("    %sid: %s%s\n", cDim, m.ID, cReset)
+				if i < len(history)-1 {
+					fmt.Println("    ↓ superseded by")
+				}
+			}
+			return nil
+		},
+	}
+}
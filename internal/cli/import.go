@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/export"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+// importCandidates are the hand-written context files `memvra import --auto`
+// looks for, relative to the project root. Globs are allowed.
+var importCandidates = []string{
+	"CLAUDE.md",
+	"AGENTS.md",
+	"GEMINI.md",
+	".cursorrules",
+	".windsurfrules",
+	".github/copilot-instructions.md",
+	".cursor/rules/*.mdc",
+	"docs/adr/*.md",
+	"doc/adr/*.md",
+	"docs/decisions/*.md",
+}
+
+// adrDirs are directory names holding Architecture Decision Records. Files
+// in them that are not ADRs, such as an index README, are skipped.
+var adrDirs = map[string]bool{"adr": true, "adrs": true, "decisions": true}
+
+func newImportCmd() *cobra.Command {
+	var auto bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import [path...]",
+		Short: "Import memories from existing CLAUDE.md, .cursorrules, ADRs and docs",
+		Long: `Turn hand-written context files into memories.
+
+Markdown bullets become memories, typed by the heading they are under
+("## Conventions", "## Constraints", ...) or by their wording. In
+.cursorrules and other plain-text rule files every line is an item.
+Architecture Decision Records (files with a "## Decision" section) become
+one decision each, made of the title and the decision; rejected,
+superseded and deprecated records are skipped.
+
+Sections Memvra generated itself are ignored, and items that are already
+stored are skipped, so importing again is safe.
+
+Examples:
+  memvra import --auto
+  memvra import CLAUDE.md .cursorrules
+  memvra import docs/adr
+  memvra import --auto --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !auto && len(args) == 0 {
+				return fmt.Errorf("give files or directories to import, or use --auto")
+			}
+
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+
+			dbPath := config.ProjectDBPath(root)
+			if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+				return fmt.Errorf("memvra not initialized. Run `memvra init` first")
+			}
+
+			var files []string
+			if auto {
+				files = append(files, autoImportFiles(root)...)
+			}
+			for _, arg := range args {
+				found, err := importFiles(root, arg)
+				if err != nil {
+					return err
+				}
+				files = append(files, found...)
+			}
+			if len(files) == 0 {
+				fmt.Println("No context files found to import.")
+				return nil
+			}
+
+			var candidates []memory.Memory
+			for _, rel := range files {
+				parsed, err := parseImportFile(root, rel)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("  %s: %d item(s)\n", rel, len(parsed))
+				candidates = append(candidates, parsed...)
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+
+			existing, err := store.ListMemories("")
+			if err != nil {
+				return fmt.Errorf("list memories: %w", err)
+			}
+			fresh, known := memory.DropKnown(candidates, existing)
+
+			if dryRun {
+				for _, m := range fresh {
+					fmt.Printf("  [%s] %s\n", m.MemoryType, m.Content)
+				}
+				fmt.Printf("Would import %d memories (%d already stored).\n", len(fresh), known)
+				return nil
+			}
+
+			gcfg, _ := config.LoadGlobal()
+			vectors := memory.NewVectorStore(database)
+			orchestrator := memory.NewOrchestrator(store, vectors, memory.NewRanker(),
+				compatibleEmbedder(store, vectors, buildEmbedder(gcfg)))
+			orchestrator.SetDedupe(dedupeOptions(gcfg, nil))
+
+			var stored, merged int
+			for _, m := range fresh {
+				res, err := orchestrator.Save(context.Background(), m)
+				if err != nil {
+					return fmt.Errorf("store memory: %w", err)
+				}
+				if res.Outcome == memory.OutcomeMerged {
+					merged++
+				} else {
+					stored++
+				}
+			}
+
+			fmt.Printf("Imported %d memories from %d file(s)", stored, len(files))
+			if known+merged > 0 {
+				fmt.Printf(" (%d already stored, %d merged into similar ones)", known, merged)
+			}
+			fmt.Println(".")
+
+			if stored+merged > 0 {
+				AutoExport(root, store)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&auto, "auto", false,
+		"Import the usual context files: CLAUDE.md, AGENTS.md, .cursorrules, Cursor rules, docs/adr and more")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be imported without storing anything")
+
+	return cmd
+}
+
+// autoImportFiles returns the importCandidates present in root.
+func autoImportFiles(root string) []string {
+	var files []string
+	for _, pattern := range importCandidates {
+		matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		for _, m := range matches {
+			rel := memory.NormalizeFilePath(root, m)
+			// Skip the rules Memvra exports to Cursor itself.
+			if strings.HasPrefix(path.Base(rel), "memvra") && path.Ext(rel) == ".mdc" {
+				continue
+			}
+			files = append(files, rel)
+		}
+	}
+	return files
+}
+
+// handWrittenContextFiles returns the importCandidates in root that have
+// content Memvra did not generate.
+func handWrittenContextFiles(root string) []string {
+	var files []string
+	for _, rel := range autoImportFiles(root) {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err == nil && strings.TrimSpace(export.HandWritten(string(data))) != "" {
+			files = append(files, rel)
+		}
+	}
+	return files
+}
+
+// importFiles resolves a path argument to the files to import: the file
+// itself, or the markdown files under a directory.
+func importFiles(root, arg string) ([]string, error) {
+	abs := arg
+	if !filepath.IsAbs(abs) {
+		cwd, _ := os.Getwd()
+		abs = filepath.Join(cwd, arg)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("import %s: %w", arg, err)
+	}
+	if filepath.IsAbs(memory.NormalizeFilePath(root, abs)) {
+		return nil, fmt.Errorf("import %s: not inside the project", arg)
+	}
+	if !info.IsDir() {
+		return []string{memory.NormalizeFilePath(root, abs)}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(abs, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != abs && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if ext := strings.ToLower(filepath.Ext(p)); !d.IsDir() && (ext == ".md" || ext == ".mdc") {
+			files = append(files, memory.NormalizeFilePath(root, p))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("import %s: %w", arg, err)
+	}
+	return files, nil
+}
+
+// parseImportFile reads the file rel and parses its hand-written parts
+// into memories.
+func parseImportFile(root, rel string) ([]memory.Memory, error) {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, fmt.Errorf("import %s: %w", rel, err)
+	}
+	content := export.HandWritten(string(data))
+
+	if memory.IsADR(content) {
+		if m, ok := memory.ParseADR(content, rel); ok {
+			return []memory.Memory{m}, nil
+		}
+		return nil, nil
+	}
+	if adrDirs[path.Base(path.Dir(rel))] {
+		return nil, nil
+	}
+	return memory.ParseMarkdownMemories(content, rel), nil
+}
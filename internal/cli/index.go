@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/memvra/memvra/internal/adapter"
 	"github.com/memvra/memvra/internal/config"
 	"github.com/memvra/memvra/internal/memory"
 	"github.com/memvra/memvra/internal/scanner"
@@ -20,7 +21,7 @@ const (
 )
 
 // upsertScannedFile indexes a single scanned file: upserts the file record,
-// replaces its chunks, and returns the new file ID. force causes re-indexing
+// replaces its chunks and symbols, and returns the new file ID. force causes re-indexing
 // even if the content hash matches.
 func upsertScannedFile(store *memory.Store, sf scanner.ScannedFile, force bool) (fileID string, status fileStatus, err error) {
 	existing, lookupErr := store.GetFileByPath(sf.File.Path)
@@ -37,6 +38,7 @@ func upsertScannedFile(store *memory.Store, sf scanner.ScannedFile, force bool)
 			chunk.FileID = fileID
 			_ = store.InsertChunk(chunk)
 		}
+		_ = store.ReplaceFileSymbols(fileID, sf.Symbols)
 		if isNew {
 			return fileID, fileAdded, nil
 		}
@@ -58,6 +60,7 @@ func upsertScannedFile(store *memory.Store, sf scanner.ScannedFile, force bool)
 		chunk.FileID = fileID
 		_ = store.InsertChunk(chunk)
 	}
+	_ = store.ReplaceFileSymbols(fileID, sf.Symbols)
 	return fileID, fileModified, nil
 }
 
@@ -70,11 +73,23 @@ func pruneDeletedFile(store *memory.Store, vectors *memory.VectorStore, fileID s
 	_ = store.DeleteFile(fileID)
 }
 
+// compatibleEmbedder returns embedder unless the vectors it produces no
+// longer match the stored ones, in which case it prints a warning and
+// returns nil (see memory.CompatibleEmbedder).
+func compatibleEmbedder(store *memory.Store, vectors *memory.VectorStore, embedder adapter.Embedder) adapter.Embedder {
+	emb, err := memory.CompatibleEmbedder(context.Background(), store, vectors, embedder)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: %v\n", err)
+	}
+	return emb
+}
+
 // embedFileChunks generates embeddings for all chunks of the given file IDs.
-// Returns the count of chunks successfully embedded.
+// Returns the count of chunks successfully embedded and the first error
+// encountered storing an embedding.
 func embedFileChunks(ctx context.Context, store *memory.Store, vectors *memory.VectorStore, embedder interface {
 	Embed(ctx context.Context, texts []string) ([][]float32, error)
-}, fileIDs []string) int {
+}, fileIDs []string) (int, error) {
 	embeddedCount := 0
 	const batchSize = 32
 
@@ -104,13 +119,14 @@ func embedFileChunks(ctx context.Context, store *memory.Store, vectors *memory.V
 				if j >= len(batch) {
 					break
 				}
-				if err := vectors.UpsertChunkEmbedding(batch[j].ID, vec); err == nil {
-					embeddedCount++
+				if err := vectors.UpsertChunkEmbedding(batch[j].ID, vec); err != nil {
+					return embeddedCount, err
 				}
+				embeddedCount++
 			}
 		}
 	}
-	return embeddedCount
+	return embeddedCount, nil
 }
 
 // refreshProjectCounts updates the file and chunk counts on the project record.
@@ -3,6 +3,7 @@ package cli
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"github.com/memvra/memvra/internal/adapter"
 	"github.com/memvra/memvra/internal/config"
 	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/export"
 	"github.com/memvra/memvra/internal/memory"
 	"github.com/memvra/memvra/internal/scanner"
 )
@@ -81,9 +83,9 @@ and set up the .memvra/ directory with a SQLite database and config.`,
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 
-			// Persist all files and chunks.
+			// Persist all files, chunks and symbols.
 			for _, sf := range result.Files {
 				fileID, err := store.UpsertFile(sf.File)
 				if err != nil {
@@ -98,6 +100,9 @@ and set up the .memvra/ directory with a SQLite database and config.`,
 						fmt.Fprintf(os.Stderr, "  Warning: chunk error for %s: %v\n", sf.File.Path, err)
 					}
 				}
+				if err := store.ReplaceFileSymbols(fileID, sf.Symbols); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: symbol error for %s: %v\n", sf.File.Path, err)
+				}
 			}
 
 			fileCount, _ := store.CountFiles()
@@ -119,8 +124,8 @@ and set up the .memvra/ directory with a SQLite database and config.`,
 
 			// --- Embedding phase ---
 			// Build embedder from config; skip silently if unavailable or unconfigured.
-			embedder := buildEmbedder(gcfg)
 			vectors := memory.NewVectorStore(database)
+			embedder := compatibleEmbedder(store, vectors, buildEmbedder(gcfg))
 			if embedder != nil {
 				embBar := progressbar.NewOptions(-1,
 					progressbar.OptionSetDescription("  Generating embeddings"),
@@ -128,9 +133,12 @@ and set up the .memvra/ directory with a SQLite database and config.`,
 					progressbar.OptionSetWriter(os.Stderr),
 					progressbar.OptionClearOnFinish(),
 				)
-				embeddedCount, embErr := embedAllChunks(context.Background(), store, vectors, embedder)
+				embeddedCount, embErr := embedAllChunks(context.Background(), store, vectors, embedder, embBar)
 				_ = embBar.Finish()
-				if embErr != nil {
+				var storeErr *embeddingStoreError
+				if errors.As(embErr, &storeErr) {
+					fmt.Fprintf(os.Stderr, "  Warning: could not store embeddings: %v\n", storeErr.err)
+				} else if embErr != nil {
 					// Connection-refused means the embedder (e.g. Ollama) isn't running.
 					fmt.Fprintf(os.Stderr, "  Embedder not available — skipping semantic indexing.\n")
 					fmt.Fprintf(os.Stderr, "  To enable: start Ollama or run `memvra setup` to configure OpenAI.\n")
@@ -168,7 +176,9 @@ and set up the .memvra/ directory with a SQLite database and config.`,
 						// Embed the memory too (best-effort).
 						if embedder != nil {
 							if vecs, err := embedder.Embed(context.Background(), []string{line}); err == nil && len(vecs) > 0 {
-								_ = vectors.UpsertMemoryEmbedding(id, vecs[0])
+								if err := vectors.UpsertMemoryEmbedding(id, vecs[0]); err != nil {
+									fmt.Fprintf(os.Stderr, "  Warning: could not store embedding: %v\n", err)
+								}
 							}
 						}
 					}
@@ -187,10 +197,15 @@ and set up the .memvra/ directory with a SQLite database and config.`,
 			ensureGitignore(root)
 
 			// Auto-export context files for all AI tools.
+			handWritten := handWrittenContextFiles(root)
 			AutoExport(root, store)
 
 			fmt.Println()
 			fmt.Println("Memvra initialized. Project context saved to .memvra/")
+			if len(handWritten) > 0 {
+				fmt.Printf("Found existing context in %s. Run `memvra import --auto` to turn it into memories.\n",
+					strings.Join(handWritten, ", "))
+			}
 			fmt.Println(`Tip: Run "memvra status" to see your project profile.`)
 			return nil
 		},
@@ -219,20 +234,25 @@ func buildEmbedder(gcfg config.GlobalConfig) adapter.Embedder {
 	if name == "" {
 		name = "ollama"
 	}
-	var apiKey string
-	if name == adapter.ProviderOpenAI {
-		apiKey = gcfg.Keys.OpenAI
-	}
-	emb, err := adapter.New(name, gcfg.Ollama.EmbedModel, apiKey, gcfg.Ollama.Host)
+	emb, err := adapter.FromConfig(gcfg, name)
 	if err != nil {
 		return nil
 	}
 	return emb
 }
 
+// embeddingStoreError wraps a failure to write a vector (as opposed to a
+// failure to generate one), so callers can tell a broken vector table from
+// an unreachable embedder.
+type embeddingStoreError struct{ err error }
+
+func (e *embeddingStoreError) Error() string { return "store embedding: " + e.err.Error() }
+func (e *embeddingStoreError) Unwrap() error { return e.err }
+
 // embedAllChunks fetches every chunk from the store and batch-embeds them,
 // writing the resulting vectors into vec_chunks. Returns the number embedded.
-func embedAllChunks(ctx context.Context, store *memory.Store, vectors *memory.VectorStore, embedder adapter.Embedder) (int, error) {
+// bar, if non-nil, is advanced by one per chunk processed.
+func embedAllChunks(ctx context.Context, store *memory.Store, vectors *memory.VectorStore, embedder adapter.Embedder, bar *progressbar.ProgressBar) (int, error) {
 	chunks, err := store.ListAllChunks()
 	if err != nil {
 		return 0, fmt.Errorf("list chunks: %w", err)
@@ -265,53 +285,29 @@ func embedAllChunks(ctx context.Context, store *memory.Store, vectors *memory.Ve
 				break
 			}
 			if err := vectors.UpsertChunkEmbedding(batch[j].ID, vec); err != nil {
-				// Non-fatal: log and continue.
-				continue
+				return embedded, &embeddingStoreError{err: err}
 			}
 			embedded++
 		}
+		if bar != nil {
+			_ = bar.Add(len(batch))
+		}
 	}
 
 	return embedded, nil
 }
 
-// ensureGitignore appends .memvra/ and auto-export filenames to .gitignore
-// if not already present.
+// ensureGitignore appends .memvra/ and auto-export filenames, including
+// nested per-directory exports, to .gitignore if not already present.
 func ensureGitignore(root string) {
-	path := filepath.Join(root, ".gitignore")
-	content, _ := os.ReadFile(path)
-	existing := string(content)
-
-	// Collect lines that need to be added.
 	entries := []string{".memvra/"}
 
 	gcfg, _ := config.Load(root)
 	if gcfg.AutoExport.Enabled {
-		entries = append(entries, autoExportFilenames(gcfg.AutoExport)...)
+		templates, _ := export.LoadTemplates(root)
+		entries = append(entries, autoExportFilenames(gcfg.AutoExport, templates)...)
+		entries = append(entries, export.NestedExports(root)...)
 	}
 
-	var toAdd []string
-	for _, entry := range entries {
-		if !strings.Contains(existing, entry) {
-			toAdd = append(toAdd, entry)
-		}
-	}
-	if len(toAdd) == 0 {
-		return
-	}
-
-	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
-		return
-	}
-	defer func() { _ = f.Close() }()
-
-	if len(content) > 0 && !strings.HasSuffix(existing, "\n") {
-		_, _ = f.WriteString("\n")
-	}
-
-	_, _ = f.WriteString("\n# Memvra auto-generated context files\n")
-	for _, entry := range toAdd {
-		_, _ = f.WriteString(entry + "\n")
-	}
+	export.EnsureGitignore(root, entries)
 }
@@ -1,14 +1,31 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/memvra/memvra/internal/config"
 	mcppkg "github.com/memvra/memvra/internal/mcp"
 )
 
 func newMCPCmd() *cobra.Command {
+	var (
+		httpAddr   string
+		token      string
+		allowHosts []string
+	)
+
 	cmd := &cobra.Command{
 		Use:   "mcp",
 		Short: "Start the MCP (Model Context Protocol) server",
@@ -18,10 +35,34 @@ AI coding assistants like Claude Code, Cursor, and Windsurf can connect
 to this server to automatically save progress, store decisions, and
 retrieve project context — without you running any commands.
 
+With --http the server listens on streamable HTTP (with SSE for
+notifications) instead, so several agents, including remote or
+containerized ones, can share one server. Each client picks its project
+with the root parameter; run from inside a project, that project is the
+default:
+  http://localhost:8765/mcp?root=/home/me/api
+
+Without a host, --http listens on 127.0.0.1 only. Listening on any other
+address requires a bearer token, set with --token or MEMVRA_MCP_TOKEN.
+Requests must name localhost, the listen address or a host given with
+--allow-host in their Host and Origin headers, so that web pages can't
+reach the server through DNS rebinding.
+
 To register Memvra with your AI tools, run:
-  memvra mcp install`,
+  memvra mcp install
+
+Examples:
+  memvra mcp --http 127.0.0.1:8765
+  MEMVRA_MCP_TOKEN=s3cret memvra mcp --http 0.0.0.0:8765 --allow-host devbox.lan`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if httpAddr != "" {
+				if token == "" {
+					token = os.Getenv("MEMVRA_MCP_TOKEN")
+				}
+				return serveMCPHTTP(httpAddr, token, allowHosts)
+			}
+
 			root, err := findRoot()
 			if err != nil {
 				return fmt.Errorf("no Memvra project found: %w", err)
@@ -37,37 +78,90 @@ To register Memvra with your AI tools, run:
 		},
 	}
 
+	cmd.Flags().StringVar(&httpAddr, "http", "", "serve over streamable HTTP on this address (e.g. :8765, which means 127.0.0.1:8765) instead of stdio")
+	cmd.Flags().StringVar(&token, "token", "", "bearer token clients must send with --http (default $MEMVRA_MCP_TOKEN)")
+	cmd.Flags().StringSliceVar(&allowHosts, "allow-host", nil, "host name clients may use to reach the --http server besides localhost (repeatable)")
+
 	cmd.AddCommand(newMCPInstallCmd())
+	cmd.AddCommand(newMCPUninstallCmd())
+	cmd.AddCommand(newMCPDoctorCmd())
 	return cmd
 }
 
+// serveMCPHTTP runs the HTTP transport until interrupted. The current
+// project, if any, is served to clients that don't name one.
+func serveMCPHTTP(addr, token string, allowHosts []string) error {
+	opts := mcppkg.HTTPOptions{Addr: addr, Token: token, AllowedHosts: allowHosts}
+	if root, err := findRoot(); err == nil {
+		if _, err := ensureInitialized(root); err == nil {
+			opts.Root = root
+		}
+	}
+	opts.OnListen = func(bound net.Addr) {
+		fmt.Fprintf(os.Stderr, "Memvra MCP server listening on http://%s%s\n", bound, mcppkg.HTTPEndpoint)
+		if opts.Root != "" {
+			fmt.Fprintf(os.Stderr, "  default project: %s\n", opts.Root)
+		} else {
+			fmt.Fprintf(os.Stderr, "  no default project: clients must add ?%s=<project path>\n", mcppkg.RootParam)
+		}
+		if token == "" {
+			fmt.Fprintln(os.Stderr, "  no --token set; only local clients can connect")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := mcppkg.ServeHTTP(ctx, opts); err != nil {
+		return fmt.Errorf("MCP HTTP server: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, "Memvra MCP server stopped.")
+	return nil
+}
+
 func newMCPInstallCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "install",
-		Short: "Register Memvra MCP server with Claude Code and Cursor",
+		Use:   "install [client...]",
+		Short: "Register Memvra MCP server with AI tools",
 		Long: `Registers Memvra as an MCP server so AI tools can discover and use it.
 
 This writes configuration to:
   Claude Code: ~/.claude/mcp.json
   Cursor:      .cursor/mcp.json (project-level)
+  VS Code:     .vscode/mcp.json (project-level)
+  Windsurf:    ~/.codeium/windsurf/mcp_config.json
+  Zed:         ~/.config/zed/settings.json
+  Gemini CLI:  ~/.gemini/settings.json
+  Codex:       ~/.codex/config.toml ($CODEX_HOME)
 
-After installing, restart your AI tool to pick up the new server.`,
+Without arguments Memvra registers with Claude Code and Cursor, plus every
+other tool whose config directory exists. Name clients to pick them:
+claude, cursor, vscode, windsurf, zed, gemini, codex. Other settings in
+the files are kept.
+
+After installing, restart your AI tool to pick up the new server.
+
+Examples:
+  memvra mcp install
+  memvra mcp install vscode zed`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Install for Claude Code (global).
-			if err := mcppkg.InstallClaudeCode(); err != nil {
-				fmt.Printf("  Claude Code: failed (%v)\n", err)
-			} else {
-				fmt.Println("  Claude Code: installed (~/.claude/mcp.json)")
+			root, _ := findRoot()
+			clients, err := selectMCPClients(root, args)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				clients = slices.DeleteFunc(clients, func(c mcppkg.Client) bool {
+					return c.ID != "claude" && c.ID != "cursor" && !c.Detected()
+				})
 			}
 
-			// Install for Cursor (project-level).
-			root, err := findRoot()
-			if err == nil {
-				if err := mcppkg.InstallCursor(root); err != nil {
-					fmt.Printf("  Cursor: failed (%v)\n", err)
+			for _, c := range clients {
+				if err := c.Install(); err != nil {
+					fmt.Printf("  %s: failed (%v)\n", c.Name, err)
 				} else {
-					fmt.Println("  Cursor: installed (.cursor/mcp.json)")
+					fmt.Printf("  %s: installed (%s)\n", c.Name, clientConfigPath(root, c))
 				}
 			}
 
@@ -76,3 +170,178 @@ After installing, restart your AI tool to pick up the new server.`,
 		},
 	}
 }
+
+func newMCPUninstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall [client...]",
+		Short: "Remove the Memvra MCP server from AI tool configs",
+		Long: `Removes the memvra entry from the MCP configs written by
+` + "`memvra mcp install`" + `, leaving other servers and settings alone. A config
+file left with nothing in it is deleted.
+
+Without arguments every known config is cleaned; name clients (claude,
+cursor, vscode, windsurf, zed, gemini, codex) to pick them.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := findRoot()
+			clients, err := selectMCPClients(root, args)
+			if err != nil {
+				return err
+			}
+
+			removed := 0
+			for _, c := range clients {
+				ok, err := c.Uninstall()
+				switch {
+				case err != nil:
+					fmt.Printf("  %s: failed (%v)\n", c.Name, err)
+				case ok:
+					removed++
+					fmt.Printf("  %s: removed (%s)\n", c.Name, clientConfigPath(root, c))
+				}
+			}
+			if removed == 0 {
+				fmt.Println("No Memvra MCP entries found.")
+			}
+			return nil
+		},
+	}
+}
+
+func newMCPDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the MCP configs that register Memvra",
+		Long: `Checks every AI tool config that exists: that it parses, that it has
+a memvra entry, that the entry's command is on PATH, that the project has
+a Memvra database, and that the server starts and lists its tools when
+launched the way the tool would launch it.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := findRoot()
+			clients, err := selectMCPClients(root, nil)
+			if err != nil {
+				return err
+			}
+
+			// Handshakes are cached: most clients launch the same command.
+			handshakes := make(map[string]string)
+			checked, problems := 0, 0
+			for _, c := range clients {
+				if _, err := os.Stat(c.Path); err != nil {
+					continue
+				}
+				checked++
+				fmt.Printf("%s (%s)\n", c.Name, clientConfigPath(root, c))
+				problems += doctorClient(c, root, handshakes)
+			}
+
+			switch {
+			case checked == 0:
+				fmt.Println("No MCP configs found — run `memvra mcp install`.")
+			case problems == 0:
+				fmt.Println("\nAll MCP configs look good.")
+			default:
+				return fmt.Errorf("%d problem(s) found", problems)
+			}
+			return nil
+		},
+	}
+}
+
+// doctorClient prints the checks of one client's config and returns the
+// number that failed.
+func doctorClient(c mcppkg.Client, root string, handshakes map[string]string) int {
+	entry, found, err := c.Entry()
+	if err != nil {
+		fmt.Printf("  FAIL  %v\n", err)
+		return 1
+	}
+	fmt.Println("  ok    config parses")
+	if !found {
+		fmt.Printf("  FAIL  no %s entry — run `memvra mcp install %s`\n", mcppkg.ServerName, c.ID)
+		return 1
+	}
+	launch := strings.Join(append([]string{entry.Command}, entry.Args...), " ")
+	fmt.Printf("  ok    %s entry: %s\n", mcppkg.ServerName, launch)
+
+	problems := 0
+	if path, err := exec.LookPath(entry.Command); err != nil {
+		fmt.Printf("  FAIL  %s is not on PATH\n", entry.Command)
+		problems++
+	} else {
+		fmt.Printf("  ok    command found: %s\n", path)
+	}
+
+	if root == "" {
+		fmt.Println("  FAIL  not inside a project, so there is no database to serve")
+		return problems + 1
+	}
+	if _, err := ensureInitialized(root); err != nil {
+		fmt.Printf("  FAIL  no project database in %s — run `memvra init`\n", root)
+		return problems + 1
+	}
+	fmt.Printf("  ok    project database: %s\n", config.ProjectDBPath(root))
+	if problems > 0 {
+		return problems
+	}
+
+	key := launch + "\x00" + root
+	result, ok := handshakes[key]
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		tools, err := mcppkg.Handshake(ctx, entry, root)
+		cancel()
+		if err != nil {
+			result = "FAIL  handshake: " + err.Error()
+		} else {
+			result = fmt.Sprintf("ok    handshake: %d tools (%s)", len(tools), strings.Join(tools, ", "))
+		}
+		handshakes[key] = result
+	}
+	fmt.Printf("  %s\n", result)
+	if strings.HasPrefix(result, "FAIL") {
+		return 1
+	}
+	return 0
+}
+
+// selectMCPClients returns the known MCP clients, or those named in ids.
+func selectMCPClients(root string, ids []string) ([]mcppkg.Client, error) {
+	clients, err := mcppkg.Clients(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return clients, nil
+	}
+
+	var selected []mcppkg.Client
+	for _, id := range ids {
+		i := slices.IndexFunc(clients, func(c mcppkg.Client) bool { return c.ID == strings.ToLower(id) })
+		if i < 0 {
+			if slices.Contains([]string{"cursor", "vscode"}, strings.ToLower(id)) {
+				return nil, fmt.Errorf("%s is configured per project — run this inside a project", id)
+			}
+			return nil, fmt.Errorf("unknown client %q (valid: claude, cursor, vscode, windsurf, zed, gemini, codex)", id)
+		}
+		selected = append(selected, clients[i])
+	}
+	return selected, nil
+}
+
+// clientConfigPath shortens a client's config path for display: relative
+// to the project for project-level configs, ~-prefixed otherwise.
+func clientConfigPath(root string, c mcppkg.Client) string {
+	if c.Project {
+		if rel, err := filepath.Rel(root, c.Path); err == nil {
+			return rel
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, c.Path); err == nil && filepath.IsLocal(rel) {
+			return filepath.Join("~", rel)
+		}
+	}
+	return c.Path
+}
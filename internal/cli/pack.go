@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/bundle"
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newPackCmd() *cobra.Command {
+	var withEmbeddings bool
+	var noSessions bool
+
+	cmd := &cobra.Command{
+		Use:   "pack [dir]",
+		Short: "Write the project's memories, sessions and profile to a portable bundle",
+		Long: `Write the project's memory to a bundle directory (default: memvra-pack/)
+that can be committed, shared, or unpacked into another project with
+` + "`memvra unpack`" + `.
+
+A bundle is one JSON Lines file per table — memories.jsonl, sessions.jsonl,
+session_turns.jsonl, project.jsonl — plus a manifest.json, written in a stable order so that
+bundles diff cleanly. Vectors are left out unless --embeddings is given;
+the receiving project re-embeds otherwise.
+
+Examples:
+  memvra pack
+  memvra pack ../shared/memvra-pack --embeddings
+  memvra pack --no-sessions`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+			dbPath, err := ensureInitialized(root)
+			if err != nil {
+				return err
+			}
+
+			dir := bundle.DefaultDir
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			b, err := bundle.Pack(openStore(database), memory.NewVectorStore(database), bundle.PackOptions{
+				Embeddings: withEmbeddings,
+				Sessions:   !noSessions,
+			})
+			if err != nil {
+				return fmt.Errorf("pack: %w", err)
+			}
+			if err := bundle.Write(dir, b); err != nil {
+				return err
+			}
+
+			fmt.Printf("Packed %d memories and %d sessions into %s", b.Manifest.Memories, b.Manifest.Sessions, dir)
+			if b.Manifest.Embeddings > 0 {
+				fmt.Printf(" (%d embeddings from %s)", b.Manifest.Embeddings, b.Manifest.EmbeddingModel)
+			}
+			fmt.Println(".")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&withEmbeddings, "embeddings", false, "Include memory embeddings, so the receiver can skip re-embedding")
+	cmd.Flags().BoolVar(&noSessions, "no-sessions", false, "Leave session history and transcripts out of the bundle")
+
+	return cmd
+}
+
+func newUnpackCmd() *cobra.Command {
+	var dryRun bool
+	var theirs bool
+
+	cmd := &cobra.Command{
+		Use:   "unpack [dir]",
+		Short: "Merge a bundle written by `memvra pack` into this project",
+		Long: `Merge the memories, sessions and project profile of a bundle (default:
+memvra-pack/) into this project.
+
+Memories are matched by ID, so unpacking the same bundle twice changes
+nothing. A memory that differs is updated when the bundle's version is
+newer; when the local version is as new or newer it is reported as a
+conflict and kept, unless --theirs is given. New memories that repeat an
+existing one are skipped. Sessions and transcript turns are added if
+missing, and the profile only fills fields this project doesn't have yet.
+
+The bundle's embeddings are used when they come from the configured
+embedder; otherwise the merged memories are re-embedded.
+
+Examples:
+  memvra unpack --dry-run
+  memvra unpack ../other-project/memvra-pack
+  memvra unpack --theirs`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+			dbPath, err := ensureInitialized(root)
+			if err != nil {
+				return err
+			}
+
+			dir := bundle.DefaultDir
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			b, err := bundle.Read(dir)
+			if err != nil {
+				return err
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+			vectors := memory.NewVectorStore(database)
+
+			plan, err := bundle.PlanMerge(store, b, theirs)
+			if err != nil {
+				return fmt.Errorf("unpack: %w", err)
+			}
+			printMergePlan(plan, dryRun)
+			if dryRun {
+				return nil
+			}
+
+			ctx := context.Background()
+			gcfg, _ := config.Load(root)
+			embedder := buildEmbedder(gcfg)
+			if embedder != nil && len(b.Embeddings) > 0 {
+				if model, _, err := adapter.EmbeddingInfo(ctx, embedder); err == nil && model != b.Manifest.EmbeddingModel {
+					b.Embeddings = nil // embedded by another model; re-embed below
+				}
+			}
+
+			unembedded, err := bundle.Apply(store, vectors, b, plan)
+			if err != nil {
+				return fmt.Errorf("unpack: %w", err)
+			}
+			if len(unembedded) > 0 {
+				emb := compatibleEmbedder(store, vectors, embedder)
+				if emb == nil {
+					fmt.Fprintf(os.Stderr, "  Warning: %d memories have no embedding — run `memvra reembed` once an embedder is available\n", len(unembedded))
+				} else if _, err := embedMemories(ctx, vectors, emb, unembedded, nil); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: embed memories: %v — run `memvra reembed` to retry\n", err)
+				}
+			}
+
+			fmt.Printf("Unpacked %s.\n", dir)
+			if len(plan.Written()) > 0 {
+				AutoExport(root, store)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without writing anything")
+	cmd.Flags().BoolVar(&theirs, "theirs", false, "Resolve conflicts in favour of the bundle")
+
+	return cmd
+}
+
+// printMergePlan reports what unpacking a bundle does, or would do.
+func printMergePlan(plan *bundle.Plan, dryRun bool) {
+	verb := ""
+	if dryRun {
+		verb = "would be "
+	}
+
+	for _, m := range plan.New {
+		fmt.Printf("  + [%s] %s\n", m.MemoryType, truncateLabel(m.Content, 80))
+	}
+	for _, m := range plan.Updated {
+		fmt.Printf("  ~ [%s] %s\n", m.MemoryType, truncateLabel(m.Content, 80))
+	}
+	for _, c := range plan.Conflicts {
+		fmt.Printf("  ! conflict on %s:\n", c.Local.ID)
+		fmt.Printf("      local  (%s): %s\n", c.Local.UpdatedAt.Format("2006-01-02 15:04"), truncateLabel(c.Local.Content, 70))
+		fmt.Printf("      bundle (%s): %s\n", c.Incoming.UpdatedAt.Format("2006-01-02 15:04"), truncateLabel(c.Incoming.Content, 70))
+	}
+
+	fmt.Printf("Memories: %d new, %d updated, %d unchanged, %d duplicates skipped", len(plan.New), len(plan.Updated), plan.Unchanged, len(plan.Duplicates))
+	if n := len(plan.Conflicts); n > 0 {
+		if plan.Theirs {
+			fmt.Printf(", %d conflicts %sresolved with the bundle's version", n, verb)
+		} else {
+			fmt.Printf(", %d conflicts kept local (use --theirs to take the bundle's)", n)
+		}
+	}
+	fmt.Println(".")
+	fmt.Printf("Sessions: %d %sadded, with %d transcript turns.\n", len(plan.Sessions), verb, len(plan.SessionTurns))
+	if plan.ProfileFilled {
+		fmt.Printf("Project profile: empty fields %sfilled from the bundle.\n", verb)
+	}
+}
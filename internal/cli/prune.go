@@ -7,7 +7,6 @@ import (
 
 	"github.com/memvra/memvra/internal/config"
 	"github.com/memvra/memvra/internal/db"
-	"github.com/memvra/memvra/internal/memory"
 	"github.com/memvra/memvra/internal/scanner"
 )
 
@@ -42,7 +41,7 @@ By default, keeps the latest 100 sessions. Use flags to customise:
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 
 			before, _ := store.CountSessions()
 
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newReembedCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reembed",
+		Short: "Rebuild all embeddings with the configured embedder",
+		Long: `Regenerate the embeddings of all chunks and memories using the
+currently configured embedder. The stored embeddings are replaced only once
+every new one has been generated, so an interrupted run changes nothing.
+
+Run this after switching embedders (for example from Ollama to OpenAI):
+vectors from different models can't be compared, and models with a
+different dimension can't share the vector tables at all.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+
+			dbPath, err := ensureInitialized(root)
+			if err != nil {
+				return err
+			}
+
+			gcfg, err := config.Load(root)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			embedder := buildEmbedder(gcfg)
+			if embedder == nil {
+				return fmt.Errorf("no embedder configured — run `memvra setup` first")
+			}
+
+			ctx := context.Background()
+			model, dimension, err := adapter.EmbeddingInfo(ctx, embedder)
+			if err != nil {
+				return fmt.Errorf("embedder not available: %w", err)
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+
+			chunks, err := store.ListAllChunks()
+			if err != nil {
+				return fmt.Errorf("list chunks: %w", err)
+			}
+			memories, err := store.ListMemories("")
+			if err != nil {
+				return fmt.Errorf("list memories: %w", err)
+			}
+
+			fmt.Printf("Re-embedding with %s (%d dims)\n", model, dimension)
+
+			bar := progressbar.NewOptions(len(chunks)+len(memories),
+				progressbar.OptionSetDescription("  Generating embeddings"),
+				progressbar.OptionSetWriter(os.Stderr),
+				progressbar.OptionShowCount(),
+				progressbar.OptionClearOnFinish(),
+			)
+
+			// The new vectors are held in memory and swapped in at the end,
+			// so a failed run leaves the old embeddings and model in place.
+			ids, texts := make([]string, len(chunks)), make([]string, len(chunks))
+			for i, c := range chunks {
+				ids[i], texts[i] = c.ID, c.Content
+			}
+			chunkVecs, err := embedTexts(ctx, embedder, ids, texts, bar)
+			if err != nil {
+				_ = bar.Finish()
+				return fmt.Errorf("embed chunks (%d done): %w", len(chunkVecs), err)
+			}
+			ids, texts = make([]string, len(memories)), make([]string, len(memories))
+			for i, m := range memories {
+				ids[i], texts[i] = m.ID, m.Content
+			}
+			memoryVecs, err := embedTexts(ctx, embedder, ids, texts, bar)
+			_ = bar.Finish()
+			if err != nil {
+				return fmt.Errorf("embed memories (%d done): %w", len(memoryVecs), err)
+			}
+
+			if err := store.ReplaceEmbeddings(model, dimension, chunkVecs, memoryVecs); err != nil {
+				return fmt.Errorf("store embeddings: %w", err)
+			}
+
+			fmt.Printf("%d chunks and %d memories embedded\n", len(chunkVecs), len(memoryVecs))
+			return nil
+		},
+	}
+}
+
+// embedTexts batch-embeds texts and returns the vectors keyed by the ID at
+// the same index. bar, if non-nil, is advanced by one per text.
+func embedTexts(ctx context.Context, embedder adapter.Embedder, ids, texts []string, bar *progressbar.ProgressBar) (map[string][]float32, error) {
+	const batchSize = 32
+	vecsByID := make(map[string][]float32, len(ids))
+	for i := 0; i < len(texts); i += batchSize {
+		end := min(i+batchSize, len(texts))
+		vecs, err := embedder.Embed(ctx, texts[i:end])
+		if err != nil {
+			return vecsByID, fmt.Errorf("embed batch at offset %d: %w", i, err)
+		}
+		for j, vec := range vecs {
+			if i+j >= end {
+				break
+			}
+			vecsByID[ids[i+j]] = vec
+		}
+		if bar != nil {
+			_ = bar.Add(end - i)
+		}
+	}
+	return vecsByID, nil
+}
+
+// embedMemories batch-embeds the given memories into vec_memories. Returns
+// the number embedded. bar, if non-nil, is advanced by one per memory.
+func embedMemories(ctx context.Context, vectors *memory.VectorStore, embedder adapter.Embedder, memories []memory.Memory, bar *progressbar.ProgressBar) (int, error) {
+	const batchSize = 32
+	embedded := 0
+	for i := 0; i < len(memories); i += batchSize {
+		end := i + batchSize
+		if end > len(memories) {
+			end = len(memories)
+		}
+		batch := memories[i:end]
+
+		texts := make([]string, len(batch))
+		for j, m := range batch {
+			texts[j] = m.Content
+		}
+
+		vecs, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			return embedded, fmt.Errorf("embed batch at offset %d: %w", i, err)
+		}
+		for j, vec := range vecs {
+			if j >= len(batch) {
+				break
+			}
+			if err := vectors.UpsertMemoryEmbedding(batch[j].ID, vec); err != nil {
+				return embedded, &embeddingStoreError{err: err}
+			}
+			embedded++
+		}
+		if bar != nil {
+			_ = bar.Add(len(batch))
+		}
+	}
+	return embedded, nil
+}
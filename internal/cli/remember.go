@@ -4,17 +4,25 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/memvra/memvra/internal/adapter"
 	"github.com/memvra/memvra/internal/config"
 	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/export"
 	"github.com/memvra/memvra/internal/memory"
 )
 
 func newRememberCmd() *cobra.Command {
 	var memType string
+	var supersedes string
+	var files []string
+	var tags []string
+	var scope string
+	var shared bool
 
 	cmd := &cobra.Command{
 		Use:   "remember <statement>",
@@ -24,7 +32,23 @@ func newRememberCmd() *cobra.Command {
 Examples:
   memvra remember "We switched from Devise to custom JWT auth"
   memvra remember "All background jobs must be idempotent" --type constraint
-  memvra remember "TODO: Add rate limiting to document upload endpoint"`,
+  memvra remember "TODO: Add rate limiting to document upload endpoint"
+  memvra remember "We moved from JWT back to sessions" --supersedes <id>
+  memvra remember "Handlers must not touch the DB directly" --file internal/api/
+  memvra remember "Prices are stored in cents" --tag billing
+  memvra remember "Use the Stripe SDK, never raw HTTP" --scope services/billing
+  memvra remember "All money amounts are integers in cents" --shared
+
+With --supersedes the old memory is marked superseded: it no longer appears
+in context or exports, but stays visible through ` + "`memvra history <id>`" + `.
+
+With --scope the memory applies to one directory. It is exported to a nested
+context file in that directory (e.g. services/billing/CLAUDE.md) instead of
+the root one.
+
+With --shared the memory is also written to .memvra/shared.toml. Commit that
+file and your team gets the memory on their next Memvra command (run
+` + "`memvra sync`" + ` after pulling to refresh their exports).`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			statement := strings.Join(args, " ")
@@ -45,16 +69,31 @@ Examples:
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
+			if _, err := store.LastSharedSync(); err != nil && shared {
+				return err
+			}
+
+			var previous memory.Memory
+			if supersedes != "" {
+				previous, err = store.GetMemoryByID(supersedes)
+				if err != nil {
+					return err
+				}
+			}
 
-			// Determine memory type.
+			// Determine memory type. A replacement keeps the type of the
+			// memory it supersedes unless one is given.
 			var mt memory.MemoryType
-			if memType != "" {
+			switch {
+			case memType != "":
 				mt = memory.MemoryType(strings.ToLower(memType))
 				if !memory.ValidMemoryType(mt) {
 					return fmt.Errorf("unknown memory type %q (valid: decision, convention, constraint, note, todo)", memType)
 				}
-			} else {
+			case supersedes != "":
+				mt = previous.MemoryType
+			default:
 				mt = memory.ClassifyMemoryType(statement)
 			}
 
@@ -63,6 +102,30 @@ Examples:
 				MemoryType: mt,
 				Source:     "user",
 				Importance: 0.6,
+				Supersedes: supersedes,
+				Tags:       tags,
+			}
+			if m.Scope, err = memory.NormalizeScope(root, scope); err != nil {
+				return err
+			}
+			if shared {
+				m.Source = memory.SourceShared
+			}
+			if len(tags) == 0 && supersedes != "" {
+				m.Tags = previous.Tags
+			}
+			if !cmd.Flags().Changed("scope") && supersedes != "" {
+				m.Scope = previous.Scope
+			}
+			if err := memory.CheckScope(root, m.Scope); err != nil {
+				return err
+			}
+			for _, f := range files {
+				rel := memory.NormalizeFilePath(root, f)
+				if _, err := os.Stat(filepath.Join(root, rel)); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: %s does not exist in the project\n", rel)
+				}
+				m.RelatedFiles = append(m.RelatedFiles, rel)
 			}
 
 			// Decisions and constraints are slightly more important.
@@ -70,23 +133,35 @@ Examples:
 				m.Importance = 0.8
 			}
 
-			id, err := store.InsertMemory(m)
+			// Store with embedding, merging near-duplicates (best-effort
+			// embedding — non-fatal on failure).
+			gcfg, _ := config.LoadGlobal()
+			if !gcfg.Output.Color || os.Getenv("NO_COLOR") != "" {
+				disableColors()
+			}
+			vectors := memory.NewVectorStore(database)
+			orchestrator := memory.NewOrchestrator(store, vectors, memory.NewRanker(),
+				compatibleEmbedder(store, vectors, buildEmbedder(gcfg)))
+			orchestrator.SetDedupe(dedupeOptions(gcfg, adapter.ConflictChecker(gcfg)))
+
+			res, err := orchestrator.Save(context.Background(), m)
 			if err != nil {
 				return fmt.Errorf("store memory: %w", err)
 			}
 
-			// Embed the memory (best-effort — non-fatal on failure).
-			gcfg, _ := config.LoadGlobal()
-			if embedder := buildEmbedder(gcfg); embedder != nil {
-				vectors := memory.NewVectorStore(database)
-				if vecs, embErr := embedder.Embed(context.Background(), []string{statement}); embErr == nil && len(vecs) > 0 {
-					_ = vectors.UpsertMemoryEmbedding(id, vecs[0])
-				}
+			printSaveResult(res)
+			if supersedes != "" {
+				fmt.Printf("  supersedes: %s %q\n", supersedes, previous.Content)
 			}
 
-			fmt.Printf("Stored as: %s\n", mt)
-			fmt.Printf("  %q\n", statement)
-			fmt.Printf("  id: %s\n", id)
+			if shared {
+				if err := shareMemory(root, store, res.Memory.ID); err != nil {
+					return err
+				}
+				fmt.Printf("  shared: %s (commit it to share with your team)\n", sharedRel())
+			} else {
+				saveShared(store)
+			}
 
 			AutoExport(root, store)
 			return nil
@@ -95,6 +170,83 @@ Examples:
 
 	cmd.Flags().StringVarP(&memType, "type", "t", "",
 		"Memory type: decision, convention, constraint, note, todo (auto-detected if not set)")
+	cmd.Flags().StringVar(&supersedes, "supersedes", "",
+		"ID of an existing memory this one replaces")
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil,
+		"File or directory this memory is about (repeatable); boosts it when working on those files")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil,
+		"Tag the memory with an area such as billing or frontend (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&scope, "scope", "",
+		"Directory this memory applies to; exported to a nested context file there")
+	cmd.Flags().BoolVar(&shared, "shared", false,
+		"Also write the memory to .memvra/shared.toml, to be committed and shared with the team")
 
 	return cmd
 }
+
+// shareMemory marks the memory id as shared and writes the shared memories
+// to .memvra/shared.toml. A memory merged into an existing one makes that
+// one shared.
+func shareMemory(root string, store *memory.Store, id string) error {
+	m, err := store.GetMemoryByID(id)
+	if err != nil {
+		return err
+	}
+	if m.Source != memory.SourceShared {
+		m.Source = memory.SourceShared
+		if err := store.PutMemory(m); err != nil {
+			return err
+		}
+	}
+	if err := store.SaveShared(); err != nil {
+		return fmt.Errorf("write %s: %w", sharedRel(), err)
+	}
+	export.TrackMemvraFile(root, memory.SharedFileName)
+	return nil
+}
+
+// dedupeOptions returns the duplicate and contradiction detection settings
+// from gcfg. llm confirms likely contradictions when llm_check is enabled.
+func dedupeOptions(gcfg config.GlobalConfig, llm adapter.LLMAdapter) memory.DedupeOptions {
+	opts := memory.DedupeOptions{
+		DuplicateThreshold: gcfg.Dedupe.DuplicateThreshold,
+		ConflictThreshold:  gcfg.Dedupe.ConflictThreshold,
+	}
+	if gcfg.Dedupe.LLMCheck {
+		opts.Checker = llm
+	}
+	return opts
+}
+
+// printSaveResult reports whether a memory was stored, merged into an
+// existing one, or stored with a possible conflict.
+func printSaveResult(res memory.SaveResult) {
+	m := res.Memory
+	switch res.Outcome {
+	case memory.OutcomeMerged:
+		fmt.Printf("Merged into existing %s (%.0f%% similar)\n", m.MemoryType, res.Similarity*100)
+		fmt.Printf("  %q\n", m.Content)
+		fmt.Printf("  id: %s\n", m.ID)
+		fmt.Printf("  importance: %.2f\n", m.Importance)
+	default:
+		fmt.Printf("Stored as: %s\n", m.MemoryType)
+		fmt.Printf("  %q\n", m.Content)
+		fmt.Printf("  id: %s\n", m.ID)
+	}
+	if len(m.RelatedFiles) > 0 {
+		fmt.Printf("  files: %s\n", strings.Join(m.RelatedFiles, ", "))
+	}
+	if len(m.Tags) > 0 {
+		fmt.Printf("  tags: %s\n", strings.Join(m.Tags, ", "))
+	}
+	if m.Scope != "" {
+		fmt.Printf("  scope: %s/\n", m.Scope)
+	}
+	if res.Outcome == memory.OutcomeConflict && res.Existing != nil {
+		fmt.Printf("%sConflict:%s may contradict an existing %s (%.0f%% similar)\n",
+			cYellow, cReset, res.Existing.MemoryType, res.Similarity*100)
+		fmt.Printf("  %q\n", res.Existing.Content)
+		fmt.Printf("  id: %s\n", res.Existing.ID)
+		fmt.Printf("  If the new memory replaces it: memvra forget --id %s --archive\n", res.Existing.ID)
+	}
+}
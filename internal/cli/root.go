@@ -43,15 +43,25 @@ func init() {
 	rootCmd.AddCommand(
 		newInitCmd(),
 		newAskCmd(),
+		newChatCmd(),
+		newSessionsCmd(),
 		newRememberCmd(),
+		newEditCmd(),
 		newForgetCmd(),
+		newHistoryCmd(),
 		newContextCmd(),
 		newDiffCmd(),
 		newStatusCmd(),
+		newSymbolsCmd(),
 		newUpdateCmd(),
+		newReembedCmd(),
 		newWatchCmd(),
 		newWrapCmd(),
 		newExportCmd(),
+		newImportCmd(),
+		newPackCmd(),
+		newUnpackCmd(),
+		newSyncCmd(),
 		newHookCmd(),
 		newSetupCmd(),
 		newPruneCmd(),
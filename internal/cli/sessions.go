@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List past sessions and show their transcripts",
+		Long: `Browse the sessions recorded by memvra ask and memvra chat.
+
+A session can be picked up again with memvra ask --continue.`,
+	}
+
+	cmd.AddCommand(
+		newSessionsListCmd(),
+		newSessionsShowCmd(),
+	)
+
+	return cmd
+}
+
+func newSessionsListCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recent sessions, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+			dbPath, err := ensureInitialized(root)
+			if err != nil {
+				return err
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+			if gcfg, _ := config.LoadGlobal(); !gcfg.Output.Color || os.Getenv("NO_COLOR") != "" {
+				disableColors()
+			}
+
+			sessions, err := store.GetLastNSessions(limit)
+			if err != nil {
+				return err
+			}
+			if len(sessions) == 0 {
+				fmt.Println("No sessions recorded yet.")
+				return nil
+			}
+			for _, sess := range sessions {
+				turns, _ := store.ListSessionTurns(sess.ID)
+				fmt.Printf("%s%s%s  %s  %-7s %s\n", cCyan, sess.ID[:min(8, len(sess.ID))], cReset,
+					sess.CreatedAt.Local().Format("2006-01-02 15:04"), sess.ModelUsed,
+					truncateLabel(sess.Question, 70))
+				if len(turns) > 1 {
+					fmt.Printf("          %s%d turns%s\n", cDim, len(turns), cReset)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "number of sessions to list")
+
+	return cmd
+}
+
+func newSessionsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a session's full transcript",
+		Long: `Show every question and response of a session, with the context
+sources injected for each. The ID may be shortened to any unique prefix,
+as printed by memvra sessions list.
+
+Sessions recorded before transcripts were kept only show their summary.
+
+Examples:
+  memvra sessions show 3f9c2a1b`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+			dbPath, err := ensureInitialized(root)
+			if err != nil {
+				return err
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+			if gcfg, _ := config.LoadGlobal(); !gcfg.Output.Color || os.Getenv("NO_COLOR") != "" {
+				disableColors()
+			}
+
+			sess, err := store.GetSession(args[0])
+			if err != nil {
+				return err
+			}
+			turns, err := store.ListSessionTurns(sess.ID)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%sSession %s%s\n", cBold, sess.ID, cReset)
+			fmt.Printf("%s%s · %s · %d context tokens%s\n", cDim,
+				sess.CreatedAt.Local().Format("2006-01-02 15:04"), sess.ModelUsed, sess.TokensUsed, cReset)
+
+			if len(turns) == 0 {
+				fmt.Printf("\n%s> %s%s\n\n", cBold, sess.Question, cReset)
+				fmt.Println(sess.ResponseSummary)
+				fmt.Printf("\n%s(no transcript was recorded for this session; showing its summary)%s\n", cDim, cReset)
+				return nil
+			}
+			for _, t := range turns {
+				fmt.Printf("\n%s> %s%s\n\n", cBold, t.Question, cReset)
+				fmt.Println(strings.TrimRight(t.Response, "\n"))
+				if len(t.Sources) > 0 {
+					fmt.Printf("\n%sSources:%s\n", cDim, cReset)
+					for _, s := range t.Sources {
+						fmt.Printf("  %s• %s%s\n", cDim, s, cReset)
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// continueLast is the --continue value that resumes the latest session.
+const continueLast = "last"
+
+// continueSession resolves the session memvra ask --continue resumes. With
+// no ID given, a first argument that names a session is taken as its ID, so
+// "--continue <id> <question>" works; otherwise the latest session is used.
+// It returns the remaining arguments, which make up the question.
+func continueSession(store *memory.Store, id string, args []string) (memory.Session, []string, error) {
+	if id != continueLast {
+		sess, err := store.GetSession(id)
+		return sess, args, err
+	}
+	if len(args) > 1 && isSessionIDPrefix(args[0]) {
+		if sess, err := store.GetSession(args[0]); err == nil {
+			return sess, args[1:], nil
+		}
+	}
+	sessions, err := store.GetLastNSessions(1)
+	if err != nil {
+		return memory.Session{}, nil, err
+	}
+	if len(sessions) == 0 {
+		return memory.Session{}, nil, fmt.Errorf("no session to continue")
+	}
+	return sessions[0], args, nil
+}
+
+// isSessionIDPrefix reports whether s could be a (shortened) session ID.
+func isSessionIDPrefix(s string) bool {
+	if len(s) < 4 || len(s) > 32 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// sessionTranscript returns the turns of sess. A session recorded before
+// transcripts were kept is returned as a single turn made of its question
+// and summary.
+func sessionTranscript(store *memory.Store, sess memory.Session) ([]memory.SessionTurn, error) {
+	turns, err := store.ListSessionTurns(sess.ID)
+	if err != nil || len(turns) > 0 {
+		return turns, err
+	}
+	var sources []string
+	_ = json.Unmarshal([]byte(sess.ContextUsed), &sources)
+	return []memory.SessionTurn{{
+		SessionID: sess.ID,
+		Question:  sess.Question,
+		Response:  sess.ResponseSummary,
+		Summary:   sess.ResponseSummary,
+		Sources:   sources,
+		CreatedAt: sess.CreatedAt,
+	}}, nil
+}
+
+// recordTurn stores the last of turns in the transcript of sess, creating
+// the session on its first turn, and refreshes the session's sources and
+// summary to cover all of turns. sess.TokensUsed should already include the
+// new turn.
+func recordTurn(store *memory.Store, sess *memory.Session, turns []memory.SessionTurn) error {
+	var sources []string
+	for _, t := range turns {
+		for _, s := range t.Sources {
+			if !slices.Contains(sources, s) {
+				sources = append(sources, s)
+			}
+		}
+	}
+	sourcesJSON, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+	sess.Question = turns[0].Question
+	sess.ContextUsed = string(sourcesJSON)
+	sess.ResponseSummary = transcriptSummary(turns)
+	if sess.ID == "" {
+		sess.ID, err = store.InsertSessionReturningID(*sess)
+	} else {
+		err = store.UpdateSession(*sess)
+	}
+	if err != nil {
+		return err
+	}
+
+	last := &turns[len(turns)-1]
+	last.SessionID = sess.ID
+	last.Turn, err = store.AddSessionTurn(*last)
+	return err
+}
+
+// transcriptHistory returns turns as adapter messages, dropping the oldest
+// turns once they exceed budget tokens as measured by count.
+func transcriptHistory(turns []memory.SessionTurn, count func(string) int, budget int) []adapter.Message {
+	start, used := len(turns), 0
+	for start > 0 {
+		t := turns[start-1]
+		n := count(t.Question) + count(t.Response)
+		if budget > 0 && used+n > budget {
+			break
+		}
+		used += n
+		start--
+	}
+
+	history := make([]adapter.Message, 0, 2*(len(turns)-start))
+	for _, t := range turns[start:] {
+		history = append(history,
+			adapter.Message{Role: adapter.RoleUser, Content: t.Question},
+			adapter.Message{Role: adapter.RoleAssistant, Content: t.Response},
+		)
+	}
+	return history
+}
+
+// transcriptSummary renders the per-turn summaries recorded as a session's
+// response summary.
+func transcriptSummary(turns []memory.SessionTurn) string {
+	if len(turns) == 1 {
+		return turns[0].Summary
+	}
+	var sb strings.Builder
+	for i, t := range turns {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%d. %s — %s", i+1, truncateLabel(t.Question, 100), t.Summary)
+	}
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/memvra/memvra/internal/adapter"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func TestTranscriptHistory_DropsOldestTurnsOverBudget(t *testing.T) {
+	turns := []memory.SessionTurn{
+		{Question: "aaaa", Response: "bbbb"},
+		{Question: "cc", Response: "dd"},
+		{Question: "e", Response: "f"},
+	}
+	count := func(s string) int { return len(s) }
+
+	all := transcriptHistory(turns, count, 0)
+	if len(all) != 6 || all[0].Role != adapter.RoleUser || all[1].Role != adapter.RoleAssistant || all[5].Content != "f" {
+		t.Errorf("unlimited history: %+v", all)
+	}
+
+	trimmed := transcriptHistory(turns, count, 6)
+	if len(trimmed) != 4 || trimmed[0].Content != "cc" {
+		t.Errorf("expected the oldest turn to be dropped, got %+v", trimmed)
+	}
+}
+
+func TestTranscriptSummary(t *testing.T) {
+	one := []memory.SessionTurn{{Question: "How does auth work?", Summary: "JWT with RS256."}}
+	if got := transcriptSummary(one); got != "JWT with RS256." {
+		t.Errorf("single turn: %q", got)
+	}
+
+	two := append(one, memory.SessionTurn{Question: "Where are keys stored?", Summary: "In Vault."})
+	got := transcriptSummary(two)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 || lines[0] != "1. How does auth work? — JWT with RS256." || lines[1] != "2. Where are keys stored? — In Vault." {
+		t.Errorf("per-turn summary:\n%s", got)
+	}
+}
+
+func TestContinueSession(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "memvra.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = database.Close() }()
+	store := memory.NewStore(database)
+
+	if _, _, err := continueSession(store, continueLast, []string{"why?"}); err == nil {
+		t.Error("expected an error with no sessions")
+	}
+
+	sess := memory.Session{ModelUsed: "claude"}
+	first := []memory.SessionTurn{{Question: "first", Response: "one", Summary: "one"}}
+	if err := recordTurn(store, &sess, first); err != nil {
+		t.Fatal(err)
+	}
+
+	got, rest, err := continueSession(store, continueLast, []string{sess.ID[:6], "and", "then?"})
+	if err != nil || got.ID != sess.ID || strings.Join(rest, " ") != "and then?" {
+		t.Errorf("ID as first argument: %+v %v %v", got, rest, err)
+	}
+	other := "0000"
+	if sess.ID[0] == '0' {
+		other = "1111"
+	}
+	got, rest, err = continueSession(store, continueLast, []string{other, "latte"})
+	if err != nil || got.ID != sess.ID || len(rest) != 2 {
+		t.Errorf("non-matching first argument should stay in the question: %v %v", rest, err)
+	}
+	if _, _, err := continueSession(store, "ffffffff", []string{"q"}); err == nil {
+		t.Error("expected an error for an unknown ID")
+	}
+}
+
+func TestRecordTurn_AppendsTranscript(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "memvra.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = database.Close() }()
+	store := memory.NewStore(database)
+
+	var sess memory.Session
+	turns := []memory.SessionTurn{{Question: "How does auth work?", Response: "JWT.", Summary: "JWT.", Sources: []string{"a.go"}}}
+	if err := recordTurn(store, &sess, turns); err != nil {
+		t.Fatal(err)
+	}
+	turns = append(turns, memory.SessionTurn{Question: "Where are keys?", Response: "Vault.", Summary: "Vault.", Sources: []string{"a.go", "b.go"}})
+	if err := recordTurn(store, &sess, turns); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := sessionTranscript(store, sess)
+	if err != nil || len(stored) != 2 || stored[1].Turn != 2 || stored[1].Response != "Vault." {
+		t.Fatalf("transcript: %+v %v", stored, err)
+	}
+	got, _ := store.GetSession(sess.ID)
+	if got.Question != "How does auth work?" || got.ContextUsed != `["a.go","b.go"]` || !strings.HasPrefix(got.ResponseSummary, "1. ") {
+		t.Errorf("session not refreshed: %+v", got)
+	}
+}
+
+func TestSessionTranscript_FallsBackToSummary(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "memvra.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = database.Close() }()
+	store := memory.NewStore(database)
+
+	id, _ := store.InsertSessionReturningID(memory.Session{Question: "q", ContextUsed: `["a.go"]`, ResponseSummary: "s"})
+	sess, _ := store.GetSession(id)
+	turns, err := sessionTranscript(store, sess)
+	if err != nil || len(turns) != 1 || turns[0].Response != "s" || len(turns[0].Sources) != 1 {
+		t.Errorf("fallback transcript: %+v %v", turns, err)
+	}
+}
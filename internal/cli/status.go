@@ -34,7 +34,7 @@ func newStatusCmd() *cobra.Command {
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 
 			proj, err := store.GetProject()
 			if err != nil {
@@ -95,10 +95,20 @@ func newStatusCmd() *cobra.Command {
 				fmt.Printf(")")
 			}
 			fmt.Println()
+			if res, err := store.LastSharedSync(); err != nil {
+				fmt.Printf("Shared:   %s could not be loaded: %v\n", sharedRel(), err)
+			} else if res.Loaded {
+				if shared, err := store.ListSharedMemories(); err == nil {
+					fmt.Printf("Shared:   %d from %s\n", len(shared), sharedRel())
+				}
+			}
 
 			fmt.Printf("Sessions: %d\n", sessions)
 			fmt.Printf("Updated:  %s\n", lastUpdated)
 			fmt.Printf("Model:    %s (default)\n", modelName)
+			if proj.EmbeddingModel != "" {
+				fmt.Printf("Embedder: %s (%d dims)\n", proj.EmbeddingModel, proj.EmbeddingDimension)
+			}
 			fmt.Printf("DB size:  %s\n", formatBytes(dbSize))
 			fmt.Println()
 
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newSymbolsCmd() *cobra.Command {
+	var fuzzy bool
+	var kind string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "symbols <query>",
+		Short: "Find where functions, types and classes are defined",
+		Long: `Look up top-level declarations recorded in the symbol index.
+
+By default the query must match a symbol name exactly (a method also matches
+by its bare name, so "Start" finds "Server.Start"). If nothing matches
+exactly, a case-insensitive substring search is tried instead.
+
+Examples:
+  memvra symbols NewStore
+  memvra symbols --fuzzy handler
+  memvra symbols --kind class User`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+
+			dbPath := config.ProjectDBPath(root)
+			if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+				return fmt.Errorf("memvra not initialized. Run `memvra init` first")
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+
+			syms, err := findSymbols(store, args[0], kind, fuzzy, limit)
+			if err != nil {
+				return err
+			}
+
+			if len(syms) == 0 {
+				if n, _ := store.CountSymbols(); n == 0 {
+					fmt.Println("No symbols indexed yet. Run `memvra update --force` to build the index.")
+					return nil
+				}
+				fmt.Printf("No symbols matching %q.\n", args[0])
+				return nil
+			}
+
+			for _, sym := range syms {
+				fmt.Printf("%s:%d-%d  %s %s\n", sym.FilePath, sym.StartLine, sym.EndLine, sym.Kind, sym.Name)
+				if sym.Signature != "" {
+					fmt.Printf("    %s\n", sym.Signature)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fuzzy, "fuzzy", false, "Match names by case-insensitive substring")
+	cmd.Flags().StringVar(&kind, "kind", "", "Only show symbols of this kind (func, method, type, class, ...)")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of results")
+
+	return cmd
+}
+
+// findSymbols runs an exact lookup, falling back to a fuzzy one when nothing
+// matches exactly.
+func findSymbols(store *memory.Store, name, kind string, fuzzy bool, limit int) ([]memory.Symbol, error) {
+	syms, err := store.FindSymbols(name, kind, fuzzy, limit)
+	if err != nil || len(syms) > 0 || fuzzy {
+		return syms, err
+	}
+	return store.FindSymbols(name, kind, true, limit)
+}
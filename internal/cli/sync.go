@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/export"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func newSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Reconcile the database with .memvra/shared.toml after a git pull",
+		Long: `Bring the local database in line with the team memories in
+.memvra/shared.toml: add new entries, apply edits, remove deleted ones,
+embed what changed, and regenerate the exported context files.
+
+Every Memvra command already loads shared.toml, so this is mainly for
+refreshing CLAUDE.md and the other exports after a ` + "`git pull`" + `.
+
+Examples:
+  git pull && memvra sync`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := findRoot()
+			if err != nil {
+				return err
+			}
+			dbPath, err := ensureInitialized(root)
+			if err != nil {
+				return err
+			}
+
+			database, err := db.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer func() { _ = database.Close() }()
+
+			store := openStore(database)
+			res, err := store.LastSharedSync()
+			if err != nil {
+				return fmt.Errorf("sync: %w", err)
+			}
+			if !res.Loaded {
+				fmt.Printf("No %s yet — share a memory with `memvra remember --shared`.\n", sharedRel())
+				return nil
+			}
+			export.TrackMemvraFile(root, memory.SharedFileName)
+
+			fmt.Printf("Synced %s: %d added, %d updated, %d removed.\n", sharedRel(), res.Added, res.Updated, res.Removed)
+
+			if n := embedSharedMemories(root, store, database); n > 0 {
+				fmt.Printf("  embedded %d shared memories\n", n)
+			}
+
+			AutoExport(root, store)
+			return nil
+		},
+	}
+}
+
+// openStore returns the store for database after reconciling it with
+// .memvra/shared.toml, so that a `git pull` takes effect on the next
+// command. A file that can't be loaded is reported by LastSharedSync.
+func openStore(database *db.DB) *memory.Store {
+	store := memory.NewStore(database)
+	_, _ = store.LoadShared()
+	return store
+}
+
+// sharedRel is the shared memory file's path relative to the project root,
+// for messages.
+func sharedRel() string {
+	return filepath.ToSlash(filepath.Join(".memvra", memory.SharedFileName))
+}
+
+// saveShared writes changes to shared memories back to .memvra/shared.toml
+// after a command modified memories. Projects without the file are left
+// alone.
+func saveShared(store *memory.Store) {
+	if err := store.SaveSharedIfPresent(); err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: update %s: %v\n", sharedRel(), err)
+	}
+}
+
+// embedSharedMemories embeds the active shared memories that have no
+// embedding yet, such as those added by a teammate. Returns the number
+// embedded; failures only warn, since lexical search still finds them.
+func embedSharedMemories(root string, store *memory.Store, database *db.DB) int {
+	shared, err := store.ListSharedMemories()
+	if err != nil {
+		return 0
+	}
+	vectors := memory.NewVectorStore(database)
+	var missing []memory.Memory
+	for _, m := range shared {
+		if m.Status != memory.StatusActive {
+			continue
+		}
+		if vec, err := vectors.GetMemoryEmbedding(m.ID); err == nil && len(vec) == 0 {
+			missing = append(missing, m)
+		}
+	}
+	if len(missing) == 0 {
+		return 0
+	}
+
+	gcfg, _ := config.Load(root)
+	embedder := compatibleEmbedder(store, vectors, buildEmbedder(gcfg))
+	if embedder == nil {
+		return 0
+	}
+	n, err := embedMemories(context.Background(), vectors, embedder, missing, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: embed shared memories: %v\n", err)
+	}
+	return n
+}
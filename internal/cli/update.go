@@ -42,7 +42,7 @@ Use --quiet to suppress output (useful for git hooks).`,
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 			vectors := memory.NewVectorStore(database)
 			gcfg, _ := config.LoadGlobal()
 
@@ -115,8 +115,9 @@ Use --quiet to suppress output (useful for git hooks).`,
 				AutoExport(root, store)
 				return nil
 			}
-			embedder := buildEmbedder(gcfg)
+			embedder := compatibleEmbedder(store, vectors, buildEmbedder(gcfg))
 			if embedder == nil {
+				AutoExport(root, store)
 				return nil
 			}
 
@@ -130,7 +131,10 @@ Use --quiet to suppress output (useful for git hooks).`,
 				defer func() { _ = embBar.Finish() }()
 			}
 
-			embeddedCount := embedFileChunks(context.Background(), store, vectors, embedder, changedFileIDs)
+			embeddedCount, embErr := embedFileChunks(context.Background(), store, vectors, embedder, changedFileIDs)
+			if embErr != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: could not store embeddings: %v\n", embErr)
+			}
 
 			if !quiet && embeddedCount > 0 {
 				fmt.Printf("%d chunks re-embedded\n", embeddedCount)
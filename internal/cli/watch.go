@@ -50,7 +50,7 @@ Press Ctrl-C to stop.`,
 			}
 			defer func() { _ = database.Close() }()
 
-			store := memory.NewStore(database)
+			store := openStore(database)
 			vectors := memory.NewVectorStore(database)
 			gcfg, _ := config.LoadGlobal()
 
@@ -244,11 +244,14 @@ func processChanges(
 
 	// Re-embed if we have an embedder.
 	if len(changedFileIDs) > 0 {
-		if embedder := buildEmbedder(gcfg); embedder != nil {
-			n := embedFileChunks(ctx, store, vectors, embedder, changedFileIDs)
+		if embedder := compatibleEmbedder(store, vectors, buildEmbedder(gcfg)); embedder != nil {
+			n, err := embedFileChunks(ctx, store, vectors, embedder, changedFileIDs)
 			if n > 0 {
 				fmt.Printf(" (%d chunks embedded)", n)
 			}
+			if err != nil {
+				fmt.Printf(" (embedding failed: %v)", err)
+			}
 		}
 	}
 
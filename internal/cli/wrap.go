@@ -27,7 +27,8 @@ var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b[^\[a-zA-Z]|\r`)
 
 func newWrapCmd() *cobra.Command {
 	var (
-		model    string
+		model     string
+		modelName string
 		summarize bool
 		extract   bool
 		noInject  bool
@@ -68,7 +69,7 @@ Examples:
 					if dbErr == nil {
 						database = d
 						defer func() { _ = database.Close() }()
-						store = memory.NewStore(database)
+						store = openStore(database)
 					}
 				}
 			} else {
@@ -123,18 +124,17 @@ Examples:
 			if model != "" {
 				providerName = model
 			}
-			llm, llmErr := adapter.New(
-				providerName,
-				gcfg.Ollama.CompletionModel,
-				apiKey(gcfg, providerName),
-				gcfg.Ollama.Host,
-			)
+			llm, llmErr := adapter.FromConfig(gcfg, providerName)
+			models := gcfg.ModelsFor(providerName)
+			if modelName != "" {
+				models.Summarization, models.Extraction = modelName, modelName
+			}
 
 			// 8. Summarize.
 			doSummarize := gcfg.Summarization.Enabled || summarize
 			if doSummarize && sessID != "" && llmErr == nil {
 				summary, err := memory.SummarizeSession(
-					context.Background(), llm,
+					context.Background(), adapter.WithModel(llm, models.Summarization),
 					"Session with "+toolName,
 					capturedClean,
 					gcfg.Summarization.MaxTokens,
@@ -149,23 +149,32 @@ Examples:
 			doExtract := gcfg.Extraction.Enabled || extract
 			if doExtract && llmErr == nil && database != nil {
 				extracted, err := memory.ExtractMemories(
-					context.Background(), llm,
+					context.Background(), adapter.WithModel(llm, models.Extraction),
 					capturedClean,
 					gcfg.Extraction.MaxExtracts,
 				)
 				if err == nil && len(extracted) > 0 {
 					vectors := memory.NewVectorStore(database)
 					ranker := memory.NewRanker()
-					var embedder adapter.Embedder
-					if emb := buildEmbedder(gcfg); emb != nil {
-						embedder = emb
-					}
+					embedder := compatibleEmbedder(store, vectors, buildEmbedder(gcfg))
 					orchestrator := memory.NewOrchestrator(store, vectors, ranker, embedder)
+					orchestrator.SetDedupe(dedupeOptions(gcfg, llm))
+					outcomes := make(map[memory.SaveOutcome]int)
 					for _, m := range extracted {
-						_, _ = orchestrator.Remember(context.Background(), m.Content, m.MemoryType, "extracted")
+						res, saveErr := orchestrator.Save(context.Background(), m)
+						if saveErr != nil {
+							continue
+						}
+						outcomes[res.Outcome]++
+						if res.Outcome == memory.OutcomeConflict {
+							fmt.Fprintf(os.Stderr, "[memvra wrap] conflict: %q may contradict %q (id: %s)\n",
+								truncateLabel(res.Memory.Content, 60), truncateLabel(res.Existing.Content, 60), res.Existing.ID)
+						}
 					}
-					fmt.Fprintf(os.Stderr, "[memvra wrap] %d memor%s extracted\n",
-						len(extracted), pluralY(len(extracted)))
+					fmt.Fprintf(os.Stderr, "[memvra wrap] %d memor%s extracted: %d stored, %d merged, %d conflict%s\n",
+						len(extracted), pluralY(len(extracted)),
+						outcomes[memory.OutcomeStored]+outcomes[memory.OutcomeConflict],
+						outcomes[memory.OutcomeMerged], outcomes[memory.OutcomeConflict], pluralS(outcomes[memory.OutcomeConflict]))
 				}
 			}
 
@@ -174,7 +183,8 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&model, "model", "m", "", "LLM provider for summarization (claude, openai, gemini, ollama)")
+	cmd.Flags().StringVarP(&model, "model", "m", "", "LLM provider for summarization (claude, openai, gemini, ollama, or a provider profile name)")
+	cmd.Flags().StringVar(&modelName, "model-name", "", "Model for summarization and extraction (default: [models.<provider>] summarization and extraction)")
 	cmd.Flags().BoolVarP(&summarize, "summarize", "s", false, "Force session summarization")
 	cmd.Flags().BoolVarP(&extract, "extract", "e", false, "Force memory extraction from session")
 	cmd.Flags().BoolVar(&noInject, "no-inject", false, "Skip injecting project context into the wrapped tool")
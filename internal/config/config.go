@@ -21,6 +21,43 @@ type GlobalConfig struct {
 	Extraction      ExtractionConfig    `toml:"extraction"`
 	Summarization   SummarizationConfig `toml:"summarization"`
 	AutoExport      AutoExportConfig    `toml:"auto_export"`
+	Dedupe          DedupeConfig        `toml:"dedupe"`
+	// Models selects the models used with each provider, keyed by
+	// provider or profile name ([models.<provider>]).
+	Models map[string]ModelsConfig `toml:"models"`
+	// Providers holds named provider profiles ([providers.<name>]). A
+	// profile's name can be used wherever a provider is chosen: as
+	// default_model, default_embedder or ask --model.
+	Providers map[string]ProviderConfig `toml:"providers"`
+}
+
+// ModelsConfig selects the models used with a provider. Empty fields use
+// the provider's defaults; summarization and extraction default to the
+// completion model.
+type ModelsConfig struct {
+	Completion    string `toml:"completion"`
+	Embedding     string `toml:"embedding"`
+	Summarization string `toml:"summarization"`
+	Extraction    string `toml:"extraction"`
+}
+
+// ProviderConfig is a named provider profile. The only type supported is
+// "openai-compatible", for any server that speaks the OpenAI API.
+type ProviderConfig struct {
+	Type    string `toml:"type"`
+	BaseURL string `toml:"base_url"`
+	APIKey  string `toml:"api_key"`
+	// APIKeyEnv names an environment variable holding the API key, which
+	// takes precedence over APIKey.
+	APIKeyEnv          string            `toml:"api_key_env"`
+	Headers            map[string]string `toml:"headers"`
+	CompletionModel    string            `toml:"completion_model"`
+	EmbeddingModel     string            `toml:"embedding_model"`
+	EmbeddingDimension int               `toml:"embedding_dimension"` // 0 = measure on first use
+	ContextWindow      int               `toml:"context_window"`      // 0 = 32768
+	// Tools set to false stops tools being offered to the profile's
+	// models, for servers that can't call them.
+	Tools *bool `toml:"tools"`
 }
 
 // AutoExportConfig controls automatic regeneration of export files
@@ -28,6 +65,9 @@ type GlobalConfig struct {
 type AutoExportConfig struct {
 	Enabled bool     `toml:"enabled"`
 	Formats []string `toml:"formats"`
+	// Budgets caps the size of each format's file in tokens, keyed by
+	// format name. Formats without a budget are written in full.
+	Budgets map[string]int `toml:"budgets"`
 }
 
 // ExtractionConfig controls auto-extraction of memories from LLM responses.
@@ -36,6 +76,14 @@ type ExtractionConfig struct {
 	MaxExtracts int  `toml:"max_extracts"`
 }
 
+// DedupeConfig controls duplicate and contradiction detection when
+// memories are stored. Thresholds are cosine similarities of embeddings.
+type DedupeConfig struct {
+	DuplicateThreshold float64 `toml:"duplicate_threshold"`
+	ConflictThreshold  float64 `toml:"conflict_threshold"`
+	LLMCheck           bool    `toml:"llm_check"` // confirm likely contradictions with the default model
+}
+
 // SummarizationConfig controls auto-summarization of session responses.
 type SummarizationConfig struct {
 	Enabled   bool `toml:"enabled"`
@@ -83,6 +131,22 @@ type ProjectMeta struct {
 	Name string `toml:"name"`
 }
 
+// ModelsFor returns the models configured for provider. For Ollama, the
+// older [ollama] completion_model and embed_model settings apply when
+// [models.ollama] doesn't set them.
+func (c GlobalConfig) ModelsFor(provider string) ModelsConfig {
+	m := c.Models[provider]
+	if provider == "ollama" {
+		if m.Completion == "" {
+			m.Completion = c.Ollama.CompletionModel
+		}
+		if m.Embedding == "" {
+			m.Embedding = c.Ollama.EmbedModel
+		}
+	}
+	return m
+}
+
 // DefaultGlobal returns sensible defaults.
 func DefaultGlobal() GlobalConfig {
 	return GlobalConfig{
@@ -118,6 +182,10 @@ func DefaultGlobal() GlobalConfig {
 			Enabled: true,
 			Formats: []string{"claude", "cursor", "markdown", "json"},
 		},
+		Dedupe: DedupeConfig{
+			DuplicateThreshold: 0.92,
+			ConflictThreshold:  0.75,
+		},
 	}
 }
 
@@ -153,6 +221,15 @@ func LoadGlobal() (GlobalConfig, error) {
 	if v := os.Getenv("GEMINI_API_KEY"); v != "" {
 		cfg.Keys.Gemini = v
 	}
+	for name, p := range cfg.Providers {
+		if p.APIKeyEnv == "" {
+			continue
+		}
+		if v := os.Getenv(p.APIKeyEnv); v != "" {
+			p.APIKey = v
+			cfg.Providers[name] = p
+		}
+	}
 
 	return cfg, nil
 }
@@ -154,3 +154,73 @@ func TestGlobalConfigPath(t *testing.T) {
 		t.Errorf("expected config.toml, got %q", filepath.Base(path))
 	}
 }
+
+func TestLoadGlobal_ProviderProfiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("LITELLM_KEY", "from-env")
+
+	path, err := GlobalConfigPath()
+	if err != nil {
+		t.Fatalf("GlobalConfigPath: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := `
+default_model = "gateway"
+
+[providers.gateway]
+type = "openai-compatible"
+base_url = "https://llm.internal/v1"
+api_key_env = "LITELLM_KEY"
+completion_model = "qwen2.5-coder"
+embedding_model = "bge-m3"
+
+[providers.gateway.headers]
+X-Team = "platform"
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadGlobal()
+	if err != nil {
+		t.Fatalf("LoadGlobal: %v", err)
+	}
+	p, ok := cfg.Providers["gateway"]
+	if !ok {
+		t.Fatalf("profile not loaded: %+v", cfg.Providers)
+	}
+	if p.Type != "openai-compatible" || p.BaseURL != "https://llm.internal/v1" {
+		t.Errorf("profile = %+v", p)
+	}
+	if p.APIKey != "from-env" {
+		t.Errorf("APIKey = %q, want the api_key_env value", p.APIKey)
+	}
+	if p.Headers["X-Team"] != "platform" {
+		t.Errorf("Headers = %v", p.Headers)
+	}
+	if p.CompletionModel != "qwen2.5-coder" || p.EmbeddingModel != "bge-m3" {
+		t.Errorf("models = %q, %q", p.CompletionModel, p.EmbeddingModel)
+	}
+}
+
+func TestModelsFor(t *testing.T) {
+	cfg := DefaultGlobal()
+	cfg.Models = map[string]ModelsConfig{
+		"openai": {Completion: "gpt-4.1", Summarization: "gpt-4.1-mini"},
+		"ollama": {Completion: "qwen2.5-coder"},
+	}
+
+	if m := cfg.ModelsFor("openai"); m.Completion != "gpt-4.1" || m.Summarization != "gpt-4.1-mini" || m.Embedding != "" {
+		t.Errorf("ModelsFor(openai) = %+v", m)
+	}
+	// [ollama] fills in what [models.ollama] leaves out.
+	if m := cfg.ModelsFor("ollama"); m.Completion != "qwen2.5-coder" || m.Embedding != "nomic-embed-text" {
+		t.Errorf("ModelsFor(ollama) = %+v", m)
+	}
+	if m := cfg.ModelsFor("claude"); m != (ModelsConfig{}) {
+		t.Errorf("ModelsFor(claude) = %+v, want empty", m)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/memvra/memvra/internal/memory"
@@ -22,6 +23,9 @@ type BuildOptions struct {
 	SessionTokenBudget  int      // max tokens for session history block
 	SimilarityThreshold float64
 	ExtraFiles          []string // paths to always include
+	ChangedFiles        []string // files changed in the git working tree; memories about them are preferred
+	Tags                []string // memories with any of these tags are prioritised
+	RestrictTags        bool     // drop tagged memories that carry none of Tags
 }
 
 // BuiltContext is the result of a context build operation.
@@ -94,9 +98,13 @@ func (b *Builder) Build(ctx context.Context, opts BuildOptions) (*BuiltContext,
 	ts, _ := scanner.TechStackFromJSON(proj.TechStack)
 
 	// --- Step 2: Conventions + constraints (always included) ---
+	opts.Tags = memory.NormalizeTags(opts.Tags)
 	conventions, _ := b.store.ListMemories(memory.TypeConvention)
 	constraints, _ := b.store.ListMemories(memory.TypeConstraint)
 	decisions, _ := b.store.ListMemories(memory.TypeDecision)
+	conventions = scopeByTags(conventions, opts)
+	constraints = scopeByTags(constraints, opts)
+	decisions = scopeByTags(decisions, opts)
 
 	systemPrompt := b.formatter.FormatSystemPrompt(proj, ts, conventions, constraints)
 
@@ -153,6 +161,12 @@ func (b *Builder) Build(ctx context.Context, opts BuildOptions) (*BuiltContext,
 		SimilarityThreshold: opts.SimilarityThreshold,
 	})
 
+	// --- Step 4b: Files in focus ---
+	// Memories tied to the files being asked about, retrieved, or changed in
+	// the working tree are moved ahead of the rest.
+	focus := b.focusFiles(opts, retrieval)
+	decisions = boostRelated(decisions, focus)
+
 	// --- Step 5: Decision block ---
 	if len(decisions) > 0 {
 		block := b.formatter.FormatMemories(memory.TypeDecision, decisions)
@@ -170,22 +184,29 @@ func (b *Builder) Build(ctx context.Context, opts BuildOptions) (*BuiltContext,
 	chunksUsed := 0
 	memoriesUsed := 0
 
-	if retrieval != nil {
-		// Add relevant memories first.
-		for _, m := range retrieval.Memories {
-			if m.MemoryType == memory.TypeConvention || m.MemoryType == memory.TypeConstraint || m.MemoryType == memory.TypeDecision {
-				continue // Already included via system prompt or decisions block.
+	// Add relevant memories first.
+	for _, m := range scopeByTags(b.memoryCandidates(retrieval, focus), opts) {
+		if m.MemoryType == memory.TypeConvention || m.MemoryType == memory.TypeConstraint || m.MemoryType == memory.TypeDecision {
+			continue // Already included via system prompt or decisions block.
+		}
+		block := "- " + m.Content + "\n"
+		tokens := b.tokenizer.Count(block)
+		if tokens <= remaining {
+			contextSections = append(contextSections, block)
+			remaining -= tokens
+			memoriesUsed++
+			label := string(m.MemoryType)
+			if m.RelatesTo(focus) {
+				label += ", file match"
 			}
-			block := "- " + m.Content + "\n"
-			tokens := b.tokenizer.Count(block)
-			if tokens <= remaining {
-				contextSections = append(contextSections, block)
-				remaining -= tokens
-				memoriesUsed++
-				sources = append(sources, fmt.Sprintf("memory (%s): %s", m.MemoryType, truncateStr(m.Content, 60)))
+			if m.HasAnyTag(opts.Tags) {
+				label += ", tag match"
 			}
+			sources = append(sources, fmt.Sprintf("memory (%s): %s", label, truncateStr(m.Content, 60)))
 		}
+	}
 
+	if retrieval != nil {
 		// Add relevant chunks.
 		for _, c := range retrieval.Chunks {
 			// Resolve file path from the file record.
@@ -230,6 +251,89 @@ func (b *Builder) Build(ctx context.Context, opts BuildOptions) (*BuiltContext,
 	}, nil
 }
 
+// focusFiles returns the project-relative paths the current question is
+// about: explicitly requested files, files of retrieved chunks, and
+// opts.ChangedFiles.
+func (b *Builder) focusFiles(opts BuildOptions, retrieval *memory.RetrievalResult) []string {
+	var files []string
+	add := func(p string) {
+		if p = memory.NormalizeFilePath(opts.ProjectRoot, p); p != "" && !slices.Contains(files, p) {
+			files = append(files, p)
+		}
+	}
+	for _, f := range opts.ExtraFiles {
+		add(f)
+	}
+	if retrieval != nil {
+		for _, c := range retrieval.Chunks {
+			if file, err := b.store.GetFileByID(c.FileID); err == nil {
+				add(file.Path)
+			}
+		}
+	}
+	for _, f := range opts.ChangedFiles {
+		add(f)
+	}
+	return files
+}
+
+// memoryCandidates returns the retrieved memories plus any other active
+// memory related to a focus file, with the file-related ones first.
+func (b *Builder) memoryCandidates(retrieval *memory.RetrievalResult, focus []string) []memory.Memory {
+	var candidates []memory.Memory
+	if retrieval != nil {
+		candidates = append(candidates, retrieval.Memories...)
+	}
+	if len(focus) > 0 {
+		all, _ := b.store.ListMemories("")
+		for _, m := range all {
+			if !m.RelatesTo(focus) {
+				continue
+			}
+			if !slices.ContainsFunc(candidates, func(c memory.Memory) bool { return c.ID == m.ID }) {
+				candidates = append(candidates, m)
+			}
+		}
+	}
+	return boostRelated(candidates, focus)
+}
+
+// scopeByTags applies opts.Tags to memories: with RestrictTags, memories
+// tagged only with other tags are dropped; the remaining memories carrying
+// one of the tags are stably moved to the front.
+func scopeByTags(memories []memory.Memory, opts BuildOptions) []memory.Memory {
+	if len(opts.Tags) == 0 {
+		return memories
+	}
+	var matched, rest []memory.Memory
+	for _, m := range memories {
+		switch {
+		case m.HasAnyTag(opts.Tags):
+			matched = append(matched, m)
+		case !opts.RestrictTags || m.InTagScope(opts.Tags):
+			rest = append(rest, m)
+		}
+	}
+	return append(matched, rest...)
+}
+
+// boostRelated stably moves memories related to any of files to the front.
+func boostRelated(memories []memory.Memory, files []string) []memory.Memory {
+	if len(files) == 0 {
+		return memories
+	}
+	out := make([]memory.Memory, 0, len(memories))
+	var rest []memory.Memory
+	for _, m := range memories {
+		if m.RelatesTo(files) {
+			out = append(out, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+	return append(out, rest...)
+}
+
 func truncateStr(s string, max int) string {
 	if len(s) <= max {
 		return s
@@ -464,3 +464,47 @@ func TestBuilder_Build_MultipleChunksUntilBudgetExhausted(t *testing.T) {
 		t.Error("expected at least some chunks to be used")
 	}
 }
+
+func TestBoostRelated(t *testing.T) {
+	memories := []memory.Memory{
+		{ID: "a", Content: "unrelated"},
+		{ID: "b", Content: "auth", RelatedFiles: []string{"internal/auth"}},
+		{ID: "c", Content: "also unrelated"},
+		{ID: "d", Content: "main", RelatedFiles: []string{"cmd/main.go"}},
+	}
+	got := boostRelated(memories, []string{"internal/auth/jwt.go", "cmd/main.go"})
+	var ids []string
+	for _, m := range got {
+		ids = append(ids, m.ID)
+	}
+	if strings.Join(ids, "") != "bdac" {
+		t.Errorf("order: got %v, want [b d a c]", ids)
+	}
+	if out := boostRelated(memories, nil); out[0].ID != "a" {
+		t.Errorf("no focus files should keep order, got %v", out[0].ID)
+	}
+}
+
+func TestScopeByTags(t *testing.T) {
+	memories := []memory.Memory{
+		{ID: "a"},
+		{ID: "b", Tags: []string{"backend"}},
+		{ID: "c", Tags: []string{"frontend"}},
+	}
+	ids := func(ms []memory.Memory) string {
+		var out string
+		for _, m := range ms {
+			out += m.ID
+		}
+		return out
+	}
+	if got := ids(scopeByTags(memories, BuildOptions{Tags: []string{"frontend"}})); got != "cab" {
+		t.Errorf("boost: got %q, want %q", got, "cab")
+	}
+	if got := ids(scopeByTags(memories, BuildOptions{Tags: []string{"frontend"}, RestrictTags: true})); got != "ca" {
+		t.Errorf("restrict: got %q, want %q", got, "ca")
+	}
+	if got := ids(scopeByTags(memories, BuildOptions{})); got != "abc" {
+		t.Errorf("no tags: got %q, want %q", got, "abc")
+	}
+}
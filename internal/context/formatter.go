@@ -59,7 +59,11 @@ func (f *Formatter) FormatMemories(memType memory.MemoryType, items []memory.Mem
 func (f *Formatter) FormatChunk(c memory.Chunk, filePath string) string {
 	var b strings.Builder
 	if filePath != "" {
-		fmt.Fprintf(&b, "### %s (lines %d-%d)\n", filePath, c.StartLine, c.EndLine)
+		if c.Symbol != "" {
+			fmt.Fprintf(&b, "### %s (lines %d-%d) — %s %s\n", filePath, c.StartLine, c.EndLine, c.SymbolKind, c.Symbol)
+		} else {
+			fmt.Fprintf(&b, "### %s (lines %d-%d)\n", filePath, c.StartLine, c.EndLine)
+		}
 	}
 	lang := chunkLang(c.ChunkType)
 	fmt.Fprintf(&b, "```%s\n%s\n```\n\n", lang, c.Content)
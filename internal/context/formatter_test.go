@@ -201,3 +201,20 @@ func TestFormatSessionHistory_Empty(t *testing.T) {
 		t.Errorf("expected empty string for no sessions, got %q", result)
 	}
 }
+
+func TestFormatChunk_WithSymbol(t *testing.T) {
+	f := NewFormatter()
+	c := memory.Chunk{
+		Content:    "func main() {}",
+		StartLine:  5,
+		EndLine:    5,
+		ChunkType:  "code",
+		Symbol:     "main",
+		SymbolKind: "func",
+	}
+
+	result := f.FormatChunk(c, "main.go")
+	if !strings.Contains(result, "### main.go (lines 5-5) — func main") {
+		t.Errorf("missing symbol in header, got:\n%s", result)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 
 	vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
 	_ "github.com/mattn/go-sqlite3"
@@ -17,8 +19,9 @@ func init() {
 }
 
 const (
-	// DefaultEmbeddingDimension is used when creating vec0 virtual tables.
-	// nomic-embed-text produces 768-dim vectors; text-embedding-3-small produces 1536.
+	// DefaultEmbeddingDimension is used when creating vec0 virtual tables
+	// before an embedder has been recorded on the project. nomic-embed-text
+	// produces 768-dim vectors; text-embedding-3-small produces 1536.
 	// We default to 768 to match nomic-embed-text (the default Ollama embed model).
 	DefaultEmbeddingDimension = 768
 )
@@ -26,6 +29,8 @@ const (
 // DB wraps a *sql.DB and exposes helpers.
 type DB struct {
 	conn *sql.DB
+	path string
+	fts  bool // FTS5 full-text tables are available
 }
 
 // Open opens (or creates) the SQLite database at path and applies migrations.
@@ -53,13 +58,77 @@ func Open(path string) (*DB, error) {
 		return nil, fmt.Errorf("apply migrations: %w", err)
 	}
 
-	if err := applyVectorTables(conn, DefaultEmbeddingDimension); err != nil {
+	// Size new vector tables for the embedder recorded on the project, if any.
+	dimension := recordedEmbeddingDimension(conn)
+	if dimension == 0 {
+		dimension = DefaultEmbeddingDimension
+	}
+	if err := applyVectorTables(conn, dimension); err != nil {
 		// Non-fatal: sqlite-vec may not be available in all build configurations.
 		// Vector search will degrade gracefully to keyword/type-based retrieval.
 		_ = err
 	}
 
-	return &DB{conn: conn}, nil
+	// Non-fatal: FTS5 is only compiled in with the sqlite_fts5 build tag.
+	// Lexical search falls back to LIKE scans without it.
+	fts := applyFTSTables(conn) == nil
+
+	return &DB{conn: conn, path: absPath, fts: fts}, nil
+}
+
+// Path returns the absolute path of the database file.
+func (d *DB) Path() string {
+	return d.path
+}
+
+// VectorDimension returns the embedding dimension the vector tables were
+// created with, or 0 if they don't exist.
+func (d *DB) VectorDimension() int {
+	var ddl string
+	if err := d.conn.QueryRow(
+		`SELECT sql FROM sqlite_master WHERE name = 'vec_chunks'`,
+	).Scan(&ddl); err != nil {
+		return 0
+	}
+	m := vecDimensionRe.FindStringSubmatch(ddl)
+	if m == nil {
+		return 0
+	}
+	dim, _ := strconv.Atoi(m[1])
+	return dim
+}
+
+var vecDimensionRe = regexp.MustCompile(`float\[(\d+)\]`)
+
+// execer runs statements on the connection or within a transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// ResetVectorTables drops every stored embedding and recreates the vector
+// tables for the given dimension.
+func (d *DB) ResetVectorTables(dimension int) error {
+	return resetVectorTables(d.conn, dimension)
+}
+
+// ResetVectorTablesTx is ResetVectorTables within tx, so that the reset is
+// undone if the transaction is rolled back.
+func (d *DB) ResetVectorTablesTx(tx *sql.Tx, dimension int) error {
+	return resetVectorTables(tx, dimension)
+}
+
+func resetVectorTables(conn execer, dimension int) error {
+	for _, table := range []string{"vec_chunks", "vec_memories"} {
+		if _, err := conn.Exec(`DROP TABLE IF EXISTS ` + table); err != nil {
+			return fmt.Errorf("drop %s: %w", table, err)
+		}
+	}
+	return applyVectorTables(conn, dimension)
+}
+
+// HasFTS reports whether the FTS5 full-text tables are available.
+func (d *DB) HasFTS() bool {
+	return d.fts
 }
 
 // Conn returns the underlying *sql.DB for use by store/vector layers.
@@ -112,6 +112,43 @@ func TestOpen_VectorTables(t *testing.T) {
 	}
 }
 
+func TestVectorDimension_ResetAndReopen(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if database.VectorDimension() == 0 {
+		database.Close()
+		t.Skip("sqlite-vec not available")
+	}
+	if got := database.VectorDimension(); got != DefaultEmbeddingDimension {
+		t.Errorf("default dimension: got %d, want %d", got, DefaultEmbeddingDimension)
+	}
+
+	if err := database.ResetVectorTables(1536); err != nil {
+		t.Fatalf("ResetVectorTables: %v", err)
+	}
+	if got := database.VectorDimension(); got != 1536 {
+		t.Errorf("after reset: got %d, want 1536", got)
+	}
+
+	// A recorded dimension is used when the vector tables are recreated.
+	database.Conn().Exec(`INSERT INTO project (name, root_path, embedding_dimension) VALUES ('p', '/p', 384)`)
+	database.Conn().Exec(`DROP TABLE vec_chunks`)
+	database.Conn().Exec(`DROP TABLE vec_memories`)
+	database.Close()
+
+	reopened, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	if got := reopened.VectorDimension(); got != 384 {
+		t.Errorf("after reopen: got %d, want 384", got)
+	}
+}
+
 func TestConn_ReturnsNonNil(t *testing.T) {
 	dbPath := filepath.Join(t.TempDir(), "test.db")
 	database, err := Open(dbPath)
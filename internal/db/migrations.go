@@ -74,6 +74,55 @@ var migrations = []string{
 		version    INTEGER PRIMARY KEY,
 		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	)`,
+
+	// Migration 2: declaration-aware chunks
+	`ALTER TABLE chunks ADD COLUMN symbol TEXT`,
+	`ALTER TABLE chunks ADD COLUMN symbol_kind TEXT`,
+
+	// Migration 3: symbol index
+	`CREATE TABLE IF NOT EXISTS symbols (
+		id         TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+		file_id    TEXT NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+		name       TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		start_line INTEGER,
+		end_line   INTEGER,
+		signature  TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_symbols_name ON symbols(name)`,
+	`CREATE INDEX IF NOT EXISTS idx_symbols_file ON symbols(file_id)`,
+
+	// Migration 4: embedding model tracking
+	`ALTER TABLE project ADD COLUMN embedding_model TEXT`,
+	`ALTER TABLE project ADD COLUMN embedding_dimension INTEGER`,
+
+	// Migration 5: memory status and supersession
+	`ALTER TABLE memories ADD COLUMN status TEXT NOT NULL DEFAULT 'active'`,
+	`ALTER TABLE memories ADD COLUMN supersedes TEXT`,
+	`CREATE INDEX IF NOT EXISTS idx_memories_supersedes ON memories(supersedes)`,
+
+	// Migration 6: memory tags
+	`CREATE TABLE IF NOT EXISTS memory_tags (
+		memory_id TEXT NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+		tag       TEXT NOT NULL,
+		PRIMARY KEY (memory_id, tag)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_memory_tags_tag ON memory_tags(tag)`,
+
+	// Migration 7: directory-scoped memories
+	`ALTER TABLE memories ADD COLUMN scope TEXT`,
+
+	// Migration 8: session transcripts
+	`CREATE TABLE IF NOT EXISTS session_turns (
+		session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		turn       INTEGER NOT NULL,
+		question   TEXT NOT NULL,
+		response   TEXT NOT NULL,
+		summary    TEXT,
+		sources    TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (session_id, turn)
+	)`,
 }
 
 // applyMigrations runs any migrations that have not yet been applied.
@@ -110,7 +159,7 @@ func applyMigrations(conn *sql.DB) error {
 
 // applyVectorTables creates the sqlite-vec virtual tables.
 // Called separately after the vec extension is confirmed loaded.
-func applyVectorTables(conn *sql.DB, dimension int) error {
+func applyVectorTables(conn execer, dimension int) error {
 	stmts := []string{
 		fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS vec_chunks USING vec0(
 			id TEXT PRIMARY KEY,
@@ -130,3 +179,71 @@ func applyVectorTables(conn *sql.DB, dimension int) error {
 
 	return nil
 }
+
+// recordedEmbeddingDimension returns the embedding dimension stored on the
+// project record, or 0 if none has been recorded yet.
+func recordedEmbeddingDimension(conn *sql.DB) int {
+	var dim int
+	_ = conn.QueryRow(`SELECT COALESCE(embedding_dimension, 0) FROM project LIMIT 1`).Scan(&dim)
+	return dim
+}
+
+// applyFTSTables creates the FTS5 full-text indexes over chunk and memory
+// content, plus the triggers that keep them in sync with their source
+// tables. The indexes are external-content tables keyed by rowid and are
+// rebuilt whenever their triggers are (re)created. Called separately
+// because FTS5 may not be compiled in; in that case the triggers are
+// dropped so writes keep working on databases created by an FTS5 build.
+func applyFTSTables(conn *sql.DB) error {
+	for _, table := range []string{"chunks", "memories"} {
+		if err := applyFTSTable(conn, table); err != nil {
+			for _, t := range []string{"chunks", "memories"} {
+				for _, op := range []string{"insert", "delete", "update"} {
+					_, _ = conn.Exec(fmt.Sprintf(`DROP TRIGGER IF EXISTS %s_fts_%s`, t, op))
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func applyFTSTable(conn *sql.DB, table string) error {
+	fts := "fts_" + table
+
+	var synced int
+	if err := conn.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?`, table+"_fts_insert",
+	).Scan(&synced); err != nil {
+		return fmt.Errorf("check %s: %w", fts, err)
+	}
+
+	stmts := []string{
+		fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %[1]s USING fts5(
+			content,
+			content='%[2]s',
+			content_rowid='rowid',
+			tokenize="unicode61 tokenchars '_'"
+		)`, fts, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_fts_insert AFTER INSERT ON %[2]s BEGIN
+			INSERT INTO %[1]s(rowid, content) VALUES (new.rowid, new.content);
+		END`, fts, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_fts_delete AFTER DELETE ON %[2]s BEGIN
+			INSERT INTO %[1]s(%[1]s, rowid, content) VALUES ('delete', old.rowid, old.content);
+		END`, fts, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_fts_update AFTER UPDATE OF content ON %[2]s BEGIN
+			INSERT INTO %[1]s(%[1]s, rowid, content) VALUES ('delete', old.rowid, old.content);
+			INSERT INTO %[1]s(rowid, content) VALUES (new.rowid, new.content);
+		END`, fts, table),
+	}
+	if synced == 0 {
+		stmts = append(stmts, fmt.Sprintf(`INSERT INTO %[1]s(%[1]s) VALUES ('rebuild')`, fts))
+	}
+
+	for _, stmt := range stmts {
+		if _, err := conn.Exec(stmt); err != nil {
+			return fmt.Errorf("create full-text table %s: %w", fts, err)
+		}
+	}
+	return nil
+}
@@ -9,7 +9,9 @@ CREATE TABLE IF NOT EXISTS project (
     file_count   INTEGER DEFAULT 0,
     chunk_count  INTEGER DEFAULT 0,
     created_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at   DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+    embedding_model     TEXT,                  -- Embedder behind the stored vectors, e.g. ollama/nomic-embed-text
+    embedding_dimension INTEGER                -- Vector size; vec tables are created with it
 );
 
 -- Source file index
@@ -31,7 +33,20 @@ CREATE TABLE IF NOT EXISTS chunks (
     end_line   INTEGER,
     chunk_type TEXT DEFAULT 'code',             -- code, comment, config, test, docs
     embedding  BLOB,                            -- Vector stored as blob for sqlite-vec
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    symbol      TEXT,                           -- Top-level declaration covered by the chunk
+    symbol_kind TEXT                            -- func, method, type, class, ...
+);
+
+-- Top-level declarations defined in indexed files
+CREATE TABLE IF NOT EXISTS symbols (
+    id         TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+    file_id    TEXT NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,                  -- e.g. Start, Server.Start, UserService
+    kind       TEXT NOT NULL,                  -- func, method, type, class, interface, ...
+    start_line INTEGER,
+    end_line   INTEGER,
+    signature  TEXT                            -- The declaration line
 );
 
 -- Persistent memories (decisions, conventions, constraints)
@@ -43,10 +58,20 @@ CREATE TABLE IF NOT EXISTS memories (
     embedding     BLOB,
     source        TEXT,                         -- 'user' (manual) or 'extracted' (from session)
     related_files TEXT,                         -- JSON array of file paths
+    status        TEXT NOT NULL DEFAULT 'active', -- active, superseded, archived
+    supersedes    TEXT,                         -- ID of the memory this one replaces
+    scope         TEXT,                         -- directory the memory applies to; NULL = whole project
     created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at    DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Free-form memory tags (e.g. 'billing', 'frontend')
+CREATE TABLE IF NOT EXISTS memory_tags (
+    memory_id TEXT NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    tag       TEXT NOT NULL,                    -- lower-case, no commas or spaces
+    PRIMARY KEY (memory_id, tag)
+);
+
 -- Session history
 CREATE TABLE IF NOT EXISTS sessions (
     id               TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
@@ -58,11 +83,27 @@ CREATE TABLE IF NOT EXISTS sessions (
     created_at       DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Full transcript of each session, one row per question and answer
+CREATE TABLE IF NOT EXISTS session_turns (
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    turn       INTEGER NOT NULL,                -- 1-based position in the session
+    question   TEXT NOT NULL,
+    response   TEXT NOT NULL,                   -- Full response text
+    summary    TEXT,                            -- Brief summary of the response
+    sources    TEXT,                            -- JSON: context labels injected for this turn
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, turn)
+);
+
 -- Virtual table for vector similarity search (sqlite-vec)
 -- NOTE: These are created conditionally in Go code after the extension loads.
 
 -- Indexes
 CREATE INDEX IF NOT EXISTS idx_memories_type   ON memories(memory_type);
+CREATE INDEX IF NOT EXISTS idx_memories_supersedes ON memories(supersedes);
+CREATE INDEX IF NOT EXISTS idx_memory_tags_tag ON memory_tags(tag);
 CREATE INDEX IF NOT EXISTS idx_chunks_file      ON chunks(file_id);
 CREATE INDEX IF NOT EXISTS idx_sessions_created ON sessions(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_files_path       ON files(path);
+CREATE INDEX IF NOT EXISTS idx_symbols_name     ON symbols(name);
+CREATE INDEX IF NOT EXISTS idx_symbols_file     ON symbols(file_id);
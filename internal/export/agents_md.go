@@ -0,0 +1,14 @@
+package export
+
+// AgentsMDExporter renders context in the AGENTS.md format read by Codex,
+// Jules, Amp and other coding agents. Nested AGENTS.md files apply to their
+// directory, so scoped memories get their own file.
+type AgentsMDExporter struct{}
+
+func (e *AgentsMDExporter) Export(data ExportData) (string, error) {
+	if data.Scope != "" {
+		return renderScopedMarkdown(data, "AGENTS.md"), nil
+	}
+	return renderInstructions(data, "Agent Instructions", regionHeader, true) +
+		renderScopesMarkdown(data.Scopes, "AGENTS.md") + renderOmittedNote(data), nil
+}
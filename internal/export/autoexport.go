@@ -3,22 +3,44 @@ package export
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/memvra/memvra/internal/config"
+	ctxpkg "github.com/memvra/memvra/internal/context"
 	gitpkg "github.com/memvra/memvra/internal/git"
 	"github.com/memvra/memvra/internal/memory"
 	"github.com/memvra/memvra/internal/scanner"
 )
 
-// FormatToFilename maps an export format name to the file it should be written to.
+// generatedMarker identifies whole files written by Memvra, so nested
+// exports never overwrite or delete a hand-written file.
+const generatedMarker = "Generated by [Memvra]"
+
+// nestedManifest lists the nested exports written by the last AutoExport,
+// relative to the project config dir.
+const nestedManifest = "nested_exports"
+
+// FormatToFilename maps a built-in export format name to the file it should
+// be written to. Templates.Filename also knows a project's template formats.
 func FormatToFilename(format string) string {
 	switch format {
 	case "claude":
 		return "CLAUDE.md"
 	case "cursor":
 		return ".cursorrules"
+	case "cursor-mdc":
+		return ".cursor/rules/memvra.mdc"
+	case "agents":
+		return "AGENTS.md"
+	case "gemini":
+		return "GEMINI.md"
+	case "copilot":
+		return ".github/copilot-instructions.md"
+	case "windsurf":
+		return ".windsurfrules"
 	case "markdown":
 		return "PROJECT_CONTEXT.md"
 	case "json":
@@ -28,13 +50,54 @@ func FormatToFilename(format string) string {
 	}
 }
 
+// NestedPath returns where format's export for the directory scope is
+// written, relative to the project root, or "" if the format is only
+// written at the root. Most formats nest a file inside the directory;
+// Cursor attaches a separate rule to the directory's files instead.
+func NestedPath(format, scope string) string {
+	switch format {
+	case "claude":
+		return path.Join(scope, "CLAUDE.md")
+	case "agents":
+		return path.Join(scope, "AGENTS.md")
+	case "gemini":
+		return path.Join(scope, "GEMINI.md")
+	case "cursor-mdc":
+		return ".cursor/rules/memvra-" + strings.ReplaceAll(scope, "/", "-") + ".mdc"
+	default:
+		return ""
+	}
+}
+
+// NestedExports returns the nested per-directory files written by the last
+// auto-export, relative to root.
+func NestedExports(root string) []string {
+	data, err := os.ReadFile(filepath.Join(config.ProjectConfigDirPath(root), nestedManifest))
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
 // AutoExport regenerates all configured export files in the project root.
+// Directory-scoped memories go into nested files (e.g.
+// services/billing/CLAUDE.md) for formats that support them.
 // It is best-effort: failures are logged to stderr but never abort the caller.
 func AutoExport(root string, store *memory.Store) {
 	gcfg, _ := config.Load(root)
 	if !gcfg.AutoExport.Enabled || len(gcfg.AutoExport.Formats) == 0 {
 		return
 	}
+	templates, err := LoadTemplates(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  warn: %v\n", err)
+	}
 
 	proj, err := store.GetProject()
 	if err != nil {
@@ -47,6 +110,21 @@ func AutoExport(root string, store *memory.Store) {
 		return
 	}
 
+	var unscoped []memory.Memory
+	scoped := make(map[string][]memory.Memory)
+	for _, m := range memories {
+		if m.Scope == "" {
+			unscoped = append(unscoped, m)
+		} else {
+			scoped[m.Scope] = append(scoped[m.Scope], m)
+		}
+	}
+	var scopes []string
+	for s := range scoped {
+		scopes = append(scopes, s)
+	}
+	slices.Sort(scopes)
+
 	sessions, _ := store.GetLastNSessions(5)
 	gitState := gitpkg.CaptureWorkingState(root)
 
@@ -58,31 +136,174 @@ func AutoExport(root string, store *memory.Store) {
 		GitState: gitState,
 	}
 
-	var exported []string
+	count := budgetCounter(gcfg.AutoExport)
+
+	var exported, nested []string
 	for _, format := range gcfg.AutoExport.Formats {
-		exporter, ok := Get(format)
+		exporter, ok := templates.Get(format)
 		if !ok {
 			continue
 		}
-		output, err := exporter.Export(data)
+		filename := templates.Filename(format)
+		if filename == "" {
+			continue
+		}
+		nests := NestedPath(format, "") != ""
+		formatData := data
+		formatData.Reserved = reservedChars(root, filename, format)
+		if nests && len(scopes) > 0 {
+			formatData.Memories = unscoped
+			formatData.Scopes = scopes
+		}
+		budget := gcfg.AutoExport.Budgets[format]
+		output, err := FitBudget(exporter, formatData, budget, count)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  warn: auto-export %s failed: %v\n", format, err)
 			continue
 		}
 
-		filename := FormatToFilename(format)
-		if filename == "" {
-			continue
-		}
-		outPath := filepath.Join(root, filename)
-		if err := os.WriteFile(outPath, []byte(output), 0o644); err != nil {
+		if err := writeExport(root, filename, format, output); err != nil {
 			fmt.Fprintf(os.Stderr, "  warn: write %s failed: %v\n", filename, err)
 			continue
 		}
 		exported = append(exported, filename)
+
+		if !nests {
+			continue
+		}
+		for _, scope := range scopes {
+			rel := NestedPath(format, scope)
+			output, err := FitBudget(exporter, ExportData{
+				Project:  proj,
+				Stack:    ts,
+				Memories: scoped[scope],
+				Scope:    scope,
+				Reserved: reservedChars(root, rel, format),
+			}, budget, count)
+			if err == nil {
+				err = writeNestedExport(root, scope, rel, format, output)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  warn: auto-export %s skipped: %v\n", rel, err)
+				continue
+			}
+			nested = append(nested, rel)
+		}
+	}
+
+	removeStaleNestedExports(root, nested)
+	if len(nested) > 0 {
+		EnsureGitignore(root, nested)
+		exported = append(exported, nested...)
 	}
 
 	if len(exported) > 0 {
 		fmt.Fprintf(os.Stderr, "  auto-exported: %s\n", strings.Join(exported, ", "))
 	}
 }
+
+// budgetCounter returns the token counter for enforcing cfg's budgets. If
+// the tokenizer can't be loaded, budgets are not enforced.
+func budgetCounter(cfg config.AutoExportConfig) func(string) int {
+	noLimit := func(string) int { return 0 }
+	if len(cfg.Budgets) == 0 {
+		return noLimit
+	}
+	tokenizer, err := ctxpkg.NewTokenizer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  warn: export token budgets not applied: %v\n", err)
+		return noLimit
+	}
+	return tokenizer.Count
+}
+
+// writeExport writes format's output to rel under root. Formats with a
+// managed region replace only the text between the memvra markers and keep
+// the rest of the file; a warning is printed if that text was edited by
+// hand. Other formats replace the whole file.
+func writeExport(root, rel, format, output string) error {
+	outPath := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return err
+	}
+	if hasRegion(format) {
+		existing, err := os.ReadFile(outPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		merged, edited, err := mergeRegion(string(existing), output)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if edited {
+			fmt.Fprintf(os.Stderr, "  warn: the Memvra section of %s was edited by hand and has been regenerated; keep hand-written notes outside the memvra markers\n", rel)
+		}
+		output = merged
+	}
+	return os.WriteFile(outPath, []byte(output), 0o644)
+}
+
+// reservedChars returns how many characters the file at rel will hold
+// besides format's output once writeExport has merged it: the region
+// markers and the hand-written text it keeps.
+func reservedChars(root, rel, format string) int {
+	if !hasRegion(format) || !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return 0
+	}
+	existing, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	merged, _, err := mergeRegion(string(existing), "\n")
+	if err != nil {
+		return 0
+	}
+	return len(merged) - len("\n")
+}
+
+// writeNestedExport writes the export for scope to rel under root. The
+// scope must be an existing directory inside the project: scopes can come
+// from shared.toml or a bundle, not only from Memvra's own checks. A file
+// without a managed region is only replaced if Memvra generated it.
+func writeNestedExport(root, scope, rel, format, output string) error {
+	if err := memory.CheckScope(root, scope); err != nil {
+		return err
+	}
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return fmt.Errorf("%s is outside the project", rel)
+	}
+	if !hasRegion(format) {
+		existing, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err == nil && !isGenerated(string(existing)) {
+			return fmt.Errorf("%s exists and was not generated by Memvra", rel)
+		}
+	}
+	return writeExport(root, rel, format, output)
+}
+
+// removeStaleNestedExports deletes nested exports from the previous run that
+// were not written this time (their scope has no memories left), and
+// records current as the new manifest. Files with hand-written content
+// outside the managed region only lose the region.
+func removeStaleNestedExports(root string, current []string) {
+	for _, rel := range NestedExports(root) {
+		if slices.Contains(current, rel) {
+			continue
+		}
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		content, err := os.ReadFile(p)
+		if err != nil || !isGenerated(string(content)) {
+			continue
+		}
+		// Keep hand-written content around the managed region.
+		if rest := removeRegion(string(content)); strings.TrimSpace(rest) != "" && rest != string(content) {
+			_ = os.WriteFile(p, []byte(rest), 0o644)
+			continue
+		}
+		_ = os.Remove(p)
+	}
+
+	manifest := filepath.Join(config.ProjectConfigDirPath(root), nestedManifest)
+	if len(current) == 0 {
+		_ = os.Remove(manifest)
+		return
+	}
+	_ = os.WriteFile(manifest, []byte(strings.Join(current, "\n")+"\n"), 0o644)
+}
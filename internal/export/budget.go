@@ -0,0 +1,139 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// sessionImportance ranks sessions against memories when trimming an export
+// to its token budget: a session counts as much as an ordinary note.
+const sessionImportance = 0.5
+
+// budgetItem is a memory or session that may be dropped to fit a budget.
+type budgetItem struct {
+	importance float64
+	at         time.Time
+	memory     int // index into ExportData.Memories, or -1
+	session    int // index into ExportData.Sessions, or -1
+}
+
+// FitBudget renders data with e, dropping the lowest-importance and oldest
+// memories and sessions until the output is at most maxTokens tokens as
+// measured by count. The output then notes how much was omitted. A
+// maxTokens of 0 means no limit. If the export doesn't fit even without
+// any memories or sessions, that smallest export is returned.
+func FitBudget(e Exporter, data ExportData, maxTokens int, count func(string) int) (string, error) {
+	out, err := e.Export(data)
+	if err != nil || maxTokens <= 0 || count(out) <= maxTokens {
+		return out, err
+	}
+
+	order := dropOrder(data)
+	if len(order) == 0 {
+		return out, nil
+	}
+
+	// Binary search for the fewest items to drop.
+	best := ""
+	lo, hi := 1, len(order)
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		out, err := e.Export(withoutItems(data, order[:mid]))
+		if err != nil {
+			return "", err
+		}
+		if count(out) <= maxTokens {
+			best, hi = out, mid-1
+		} else {
+			lo = mid + 1
+		}
+	}
+	if best == "" {
+		return e.Export(withoutItems(data, order))
+	}
+	return best, nil
+}
+
+// dropOrder returns data's memories and sessions in the order they are
+// dropped: lowest importance first, oldest first among equals.
+func dropOrder(data ExportData) []budgetItem {
+	items := make([]budgetItem, 0, len(data.Memories)+len(data.Sessions))
+	for i, m := range data.Memories {
+		items = append(items, budgetItem{importance: m.Importance, at: m.CreatedAt, memory: i, session: -1})
+	}
+	for i, s := range data.Sessions {
+		items = append(items, budgetItem{importance: sessionImportance, at: s.CreatedAt, memory: -1, session: i})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].importance != items[j].importance {
+			return items[i].importance < items[j].importance
+		}
+		return items[i].at.Before(items[j].at)
+	})
+	return items
+}
+
+// withoutItems returns a copy of data without the dropped items, keeping
+// the order of the rest and recording how many were omitted.
+func withoutItems(data ExportData, drop []budgetItem) ExportData {
+	droppedMemories := make(map[int]bool)
+	droppedSessions := make(map[int]bool)
+	for _, it := range drop {
+		if it.memory >= 0 {
+			droppedMemories[it.memory] = true
+		} else {
+			droppedSessions[it.session] = true
+		}
+	}
+
+	out := data
+	out.Memories = nil
+	for i, m := range data.Memories {
+		if !droppedMemories[i] {
+			out.Memories = append(out.Memories, m)
+		}
+	}
+	out.Sessions = nil
+	for i, s := range data.Sessions {
+		if !droppedSessions[i] {
+			out.Sessions = append(out.Sessions, s)
+		}
+	}
+	out.OmittedMemories = data.OmittedMemories + len(droppedMemories)
+	out.OmittedSessions = data.OmittedSessions + len(droppedSessions)
+	return out
+}
+
+// renderOmittedNote renders the footnote of an export trimmed to its token
+// budget, or "" if nothing was omitted.
+func renderOmittedNote(data ExportData) string {
+	if data.OmittedMemories == 0 && data.OmittedSessions == 0 {
+		return ""
+	}
+	return fmt.Sprintf("> %s omitted to fit the token budget. Use the Memvra MCP tool `memvra_search` to find them.\n",
+		omittedCounts(data))
+}
+
+// omittedCounts describes the omitted items, e.g. "3 memories and 1 session".
+func omittedCounts(data ExportData) string {
+	var parts []string
+	switch data.OmittedMemories {
+	case 0:
+	case 1:
+		parts = append(parts, "1 memory")
+	default:
+		parts = append(parts, fmt.Sprintf("%d memories", data.OmittedMemories))
+	}
+	switch data.OmittedSessions {
+	case 0:
+	case 1:
+		parts = append(parts, "1 session")
+	default:
+		parts = append(parts, fmt.Sprintf("%d sessions", data.OmittedSessions))
+	}
+	if len(parts) == 2 {
+		return parts[0] + " and " + parts[1]
+	}
+	return parts[0]
+}
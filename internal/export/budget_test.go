@@ -0,0 +1,101 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memvra/memvra/internal/memory"
+)
+
+// wordCount stands in for the tokenizer, which needs a downloaded encoding.
+func wordCount(s string) int { return len(strings.Fields(s)) }
+
+func budgetExportData() ExportData {
+	now := time.Now()
+	data := sampleExportData()
+	data.Memories = []memory.Memory{
+		{ID: "1", Content: "Never store secrets in code", MemoryType: memory.TypeConstraint, Importance: 0.9, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "2", Content: "Old low-value observation about logging: " + strings.Repeat("verbose detail ", 20), MemoryType: memory.TypeNote, Importance: 0.3, CreatedAt: now.Add(-72 * time.Hour)},
+		{ID: "3", Content: "Recent low-value observation about metrics", MemoryType: memory.TypeNote, Importance: 0.3, CreatedAt: now.Add(-time.Hour)},
+		{ID: "4", Content: "Use PostgreSQL for JSONB support", MemoryType: memory.TypeDecision, Importance: 0.8, CreatedAt: now.Add(-24 * time.Hour)},
+	}
+	data.Sessions = []memory.Session{
+		{Question: "Add rate limiting to the API", ResponseSummary: "Implemented a token bucket", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	return data
+}
+
+func TestFitBudget_NoLimit(t *testing.T) {
+	data := budgetExportData()
+	full, _ := (&ClaudeMDExporter{}).Export(data)
+
+	out, err := FitBudget(&ClaudeMDExporter{}, data, 0, wordCount)
+	if err != nil || out != full {
+		t.Errorf("budget 0 should export everything unchanged (err=%v)", err)
+	}
+	out, _ = FitBudget(&ClaudeMDExporter{}, data, wordCount(full), wordCount)
+	if out != full || strings.Contains(out, "omitted") {
+		t.Error("an export within budget should be unchanged")
+	}
+}
+
+func TestFitBudget_DropsLeastImportantOldestFirst(t *testing.T) {
+	data := budgetExportData()
+	oneDropped, _ := (&ClaudeMDExporter{}).Export(withoutItems(data, dropOrder(data)[:1]))
+	budget := wordCount(oneDropped)
+
+	out, err := FitBudget(&ClaudeMDExporter{}, data, budget, wordCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wordCount(out) > budget {
+		t.Errorf("output exceeds the budget: %d words", wordCount(out))
+	}
+	if strings.Contains(out, "about logging") {
+		t.Error("the oldest low-importance memory should be dropped first")
+	}
+	for _, want := range []string{"Never store secrets", "Use PostgreSQL", "rate limiting", "about metrics"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q should survive trimming:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "> 1 memory omitted to fit the token budget. Use the Memvra MCP tool `memvra_search` to find them.\n") {
+		t.Errorf("trimmed export should end with the omitted note:\n%s", out)
+	}
+}
+
+func TestFitBudget_DropsSessionsBeforeImportantMemories(t *testing.T) {
+	data := budgetExportData()
+	base, _ := (&ClaudeMDExporter{}).Export(withoutItems(data, dropOrder(data)[:3]))
+
+	out, _ := FitBudget(&ClaudeMDExporter{}, data, wordCount(base), wordCount)
+	if strings.Contains(out, "rate limiting") || strings.Contains(out, "observation") {
+		t.Errorf("notes and the session should be dropped before decisions and constraints:\n%s", out)
+	}
+	if !strings.Contains(out, "2 memories and 1 session omitted") {
+		t.Errorf("expected omitted counts in the note:\n%s", out)
+	}
+}
+
+func TestFitBudget_JSON(t *testing.T) {
+	data := budgetExportData()
+	full, _ := (&JSONExporter{}).Export(data)
+
+	out, err := FitBudget(&JSONExporter{}, data, wordCount(full)-1, wordCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Omitted struct {
+			Memories int `json:"memories"`
+		} `json:"omitted"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("trimmed JSON should stay valid: %v", err)
+	}
+	if parsed.Omitted.Memories == 0 {
+		t.Errorf("JSON should report omitted memories:\n%s", out)
+	}
+}
@@ -22,12 +22,16 @@ This project uses [Memvra](https://memvra.com) for persistent AI memory across s
 type ClaudeMDExporter struct{}
 
 func (e *ClaudeMDExporter) Export(data ExportData) (string, error) {
+	if data.Scope != "" {
+		return renderScopedMarkdown(data, "CLAUDE.md"), nil
+	}
+
 	ts := data.Stack
 	proj := data.Project
 
 	var b strings.Builder
 	fmt.Fprintf(&b, "# %s — Project Context\n\n", proj.Name)
-	fmt.Fprintf(&b, "> Generated by [Memvra](https://memvra.com). Do not edit manually.\n\n")
+	b.WriteString(regionHeader)
 
 	b.WriteString(mcpInstructions)
 
@@ -61,6 +65,8 @@ func (e *ClaudeMDExporter) Export(data ExportData) (string, error) {
 	b.WriteString(memorySection("Constraints", memory.TypeConstraint, data.Memories))
 	b.WriteString(memorySection("Notes", memory.TypeNote, data.Memories))
 	b.WriteString(memorySection("TODOs", memory.TypeTodo, data.Memories))
+	b.WriteString(renderScopesMarkdown(data.Scopes, "CLAUDE.md"))
+	b.WriteString(renderOmittedNote(data))
 
 	return b.String(), nil
 }
@@ -0,0 +1,13 @@
+package export
+
+// copilotMaxChars is how much of a custom instructions file GitHub Copilot
+// code review reads.
+const copilotMaxChars = 4000
+
+// CopilotExporter renders context as .github/copilot-instructions.md.
+type CopilotExporter struct{}
+
+func (e *CopilotExporter) Export(data ExportData) (string, error) {
+	doc := renderInstructions(data, "Copilot Instructions", regionHeader, false) + renderOmittedNote(data)
+	return fitLimit(doc, sizeLimit(copilotMaxChars, data), 0, "GitHub Copilot"), nil
+}
@@ -0,0 +1,37 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cursorMaxLines is Cursor's recommended maximum length for a rule file.
+const cursorMaxLines = 500
+
+// CursorMDCExporter renders context as a Cursor project rule
+// (.cursor/rules/*.mdc). The root rule is always applied; scoped memories
+// become rules attached to files under their directory.
+type CursorMDCExporter struct{}
+
+func (e *CursorMDCExporter) Export(data ExportData) (string, error) {
+	var b strings.Builder
+	b.WriteString("---\n")
+	if data.Scope != "" {
+		fmt.Fprintf(&b, "description: Rules for %s/, generated by Memvra\n", data.Scope)
+		fmt.Fprintf(&b, "globs: %s/**\n", data.Scope)
+		b.WriteString("alwaysApply: false\n")
+	} else {
+		fmt.Fprintf(&b, "description: Project context for %s, generated by Memvra\n", data.Project.Name)
+		b.WriteString("globs:\n")
+		b.WriteString("alwaysApply: true\n")
+	}
+	b.WriteString("---\n\n")
+
+	if data.Scope != "" {
+		b.WriteString(renderScopedMarkdown(data, ""))
+	} else {
+		b.WriteString(renderInstructions(data, "Project Rules", fileHeader, true))
+		b.WriteString(renderOmittedNote(data))
+	}
+	return fitLimit(b.String(), 0, cursorMaxLines, "Cursor"), nil
+}
@@ -16,7 +16,7 @@ func (e *CursorRulesExporter) Export(data ExportData) (string, error) {
 
 	var b strings.Builder
 	fmt.Fprintf(&b, "# %s — AI Rules\n", proj.Name)
-	fmt.Fprintf(&b, "# Generated by Memvra (https://memvra.com)\n\n")
+	fmt.Fprintf(&b, "# Generated by Memvra (https://memvra.com). Edit outside the memvra markers.\n\n")
 
 	b.WriteString(renderGitStatePlainText(data.GitState))
 	b.WriteString(renderSessionsPlainText(data.Sessions))
@@ -52,10 +52,15 @@ func (e *CursorRulesExporter) Export(data ExportData) (string, error) {
 		}
 		fmt.Fprintf(&b, "# %s\n", memType.label)
 		for _, m := range items {
-			fmt.Fprintf(&b, "- %s\n", m.Content)
+			fmt.Fprintf(&b, "- %s", m.Content)
+			if m.Scope != "" {
+				fmt.Fprintf(&b, " (applies to %s/)", m.Scope)
+			}
+			b.WriteString("\n")
 		}
 		b.WriteString("\n")
 	}
+	b.WriteString(renderOmittedNote(data))
 
 	return b.String(), nil
 }
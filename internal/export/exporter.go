@@ -3,6 +3,7 @@ package export
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/memvra/memvra/internal/git"
@@ -17,6 +18,20 @@ type ExportData struct {
 	Memories []memory.Memory
 	Sessions []memory.Session
 	GitState git.WorkingState
+	// Scope is the directory a nested export is written to, relative to
+	// the project root; "" for the root export.
+	Scope string
+	// Scopes lists the directories with their own nested export. Only set
+	// for the root export of formats that support nesting.
+	Scopes []string
+	// OmittedMemories and OmittedSessions count what FitBudget dropped to
+	// fit the token budget; exporters end with a note about them.
+	OmittedMemories int
+	OmittedSessions int
+	// Reserved counts the characters of the written file that are not the
+	// export: the memvra markers and the hand-written text around them.
+	// Exporters with a size limit leave room for them.
+	Reserved int
 }
 
 // Exporter renders ExportData to a string in a specific format.
@@ -24,26 +39,34 @@ type Exporter interface {
 	Export(data ExportData) (string, error)
 }
 
-// registry maps format names to Exporter implementations.
+// registry maps the built-in format names to Exporter implementations.
+// A project's own formats are loaded by LoadTemplates.
 var registry = map[string]Exporter{
-	"claude":   &ClaudeMDExporter{},
-	"cursor":   &CursorRulesExporter{},
-	"markdown": &MarkdownExporter{},
-	"json":     &JSONExporter{},
+	"claude":     &ClaudeMDExporter{},
+	"cursor":     &CursorRulesExporter{},
+	"cursor-mdc": &CursorMDCExporter{},
+	"agents":     &AgentsMDExporter{},
+	"gemini":     &GeminiMDExporter{},
+	"copilot":    &CopilotExporter{},
+	"windsurf":   &WindsurfExporter{},
+	"markdown":   &MarkdownExporter{},
+	"json":       &JSONExporter{},
 }
 
-// Get returns the Exporter registered under name, and whether it was found.
+// Get returns the built-in Exporter registered under name, and whether it
+// was found.
 func Get(name string) (Exporter, bool) {
 	e, ok := registry[name]
 	return e, ok
 }
 
-// ValidFormats returns the list of supported export format names.
+// ValidFormats returns the list of built-in export format names.
 func ValidFormats() []string {
 	formats := make([]string, 0, len(registry))
 	for k := range registry {
 		formats = append(formats, k)
 	}
+	sort.Strings(formats)
 	return formats
 }
 
@@ -60,12 +83,66 @@ func memorySection(heading string, memType memory.MemoryType, memories []memory.
 	}
 	out := fmt.Sprintf("## %s\n\n", heading)
 	for _, m := range items {
-		out += fmt.Sprintf("- %s\n", m.Content)
+		out += fmt.Sprintf("- %s%s\n", m.Content, scopeNote(m))
 	}
 	out += "\n"
 	return out
 }
 
+// scopeNote returns a suffix naming the directory a scoped memory applies
+// to, or "" for project-wide memories.
+func scopeNote(m memory.Memory) string {
+	if m.Scope == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (applies to `%s/`)", m.Scope)
+}
+
+// renderScopedMarkdown renders the nested context file written to
+// data.Scope: the memories scoped to that directory and, unless filename is
+// empty, a pointer to the root file of that name, which tools also load.
+func renderScopedMarkdown(data ExportData, filename string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — %s/\n\n", data.Project.Name, data.Scope)
+	if filename != "" {
+		b.WriteString(regionHeader)
+		fmt.Fprintf(&b, "Rules for `%s/` only. Project-wide context is in the root [%s](%s%s).\n\n",
+			data.Scope, filename, strings.Repeat("../", strings.Count(data.Scope, "/")+1), filename)
+	} else {
+		b.WriteString(fileHeader)
+		fmt.Fprintf(&b, "Rules for `%s/` only.\n\n", data.Scope)
+	}
+
+	// The scope is implied by the file's location.
+	memories := make([]memory.Memory, len(data.Memories))
+	for i, m := range data.Memories {
+		m.Scope = ""
+		memories[i] = m
+	}
+	b.WriteString(memorySection("Architectural Decisions", memory.TypeDecision, memories))
+	b.WriteString(memorySection("Coding Conventions", memory.TypeConvention, memories))
+	b.WriteString(memorySection("Constraints", memory.TypeConstraint, memories))
+	b.WriteString(memorySection("Notes", memory.TypeNote, memories))
+	b.WriteString(memorySection("TODOs", memory.TypeTodo, memories))
+	b.WriteString(renderOmittedNote(data))
+	return b.String()
+}
+
+// renderScopesMarkdown lists the nested context files of the root export.
+func renderScopesMarkdown(scopes []string, filename string) string {
+	if len(scopes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Directory-Specific Context\n\n")
+	fmt.Fprintf(&b, "These directories have their own %s with rules that apply only there:\n\n", filename)
+	for _, s := range scopes {
+		fmt.Fprintf(&b, "- [`%s/`](%s/%s)\n", s, s, filename)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
 // renderGitStateMarkdown renders the git working state as a markdown section.
 func renderGitStateMarkdown(gs git.WorkingState) string {
 	if gs.IsEmpty() || !gs.HasChanges() {
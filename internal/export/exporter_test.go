@@ -2,6 +2,9 @@ package export
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -58,7 +61,7 @@ func sampleExportData() ExportData {
 }
 
 func TestGet_ValidFormats(t *testing.T) {
-	for _, name := range []string{"claude", "cursor", "markdown", "json"} {
+	for _, name := range []string{"claude", "cursor", "cursor-mdc", "agents", "gemini", "copilot", "windsurf", "markdown", "json"} {
 		exp, ok := Get(name)
 		if !ok {
 			t.Errorf("Get(%q) returned false", name)
@@ -311,3 +314,180 @@ func TestMemorySection_Empty(t *testing.T) {
 		t.Errorf("expected empty string for no memories, got %q", result)
 	}
 }
+
+func TestClaudeMDExporter_Scoped(t *testing.T) {
+	data := ExportData{
+		Project:  memory.Project{Name: "testapp"},
+		Memories: []memory.Memory{{Content: "Amounts are in cents", MemoryType: memory.TypeConvention, Scope: "services/billing"}},
+		Scope:    "services/billing",
+	}
+	out, err := (&ClaudeMDExporter{}).Export(data)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	for _, want := range []string{"# testapp — services/billing/", "- Amounts are in cents\n", "(../../CLAUDE.md)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("scoped export should contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Project Profile") {
+		t.Error("scoped export should not repeat the project profile")
+	}
+}
+
+func TestClaudeMDExporter_ListsScopes(t *testing.T) {
+	data := sampleExportData()
+	data.Scopes = []string{"services/billing", "web"}
+	out, _ := (&ClaudeMDExporter{}).Export(data)
+	if !strings.Contains(out, "- [`web/`](web/CLAUDE.md)") {
+		t.Errorf("root export should list nested files:\n%s", out)
+	}
+}
+
+func TestInstructionsExporters(t *testing.T) {
+	for _, format := range []string{"agents", "gemini", "copilot", "windsurf", "cursor-mdc"} {
+		exp, _ := Get(format)
+		result, err := exp.Export(sampleExportData())
+		if err != nil {
+			t.Fatalf("%s: Export error: %v", format, err)
+		}
+		for _, check := range []string{"testapp", "This is a Go project using Gin", "Never store secrets in code", "Use camelCase", "Generated by [Memvra]"} {
+			if !strings.Contains(result, check) {
+				t.Errorf("%s export missing %q", format, check)
+			}
+		}
+		// Constraints come before decisions so size limits trim the latter first.
+		if strings.Index(result, "Never store secrets") > strings.Index(result, "Use PostgreSQL") {
+			t.Errorf("%s: constraints should precede decisions", format)
+		}
+	}
+}
+
+func TestCursorMDCExporter_Frontmatter(t *testing.T) {
+	exp, _ := Get("cursor-mdc")
+	root, _ := exp.Export(sampleExportData())
+	if !strings.HasPrefix(root, "---\ndescription: Project context for testapp, generated by Memvra\nglobs:\nalwaysApply: true\n---\n") {
+		t.Errorf("unexpected root frontmatter:\n%s", root)
+	}
+
+	scoped, _ := exp.Export(ExportData{
+		Project:  memory.Project{Name: "testapp"},
+		Memories: []memory.Memory{{Content: "Amounts are in cents", MemoryType: memory.TypeConvention}},
+		Scope:    "services/billing",
+	})
+	for _, want := range []string{"globs: services/billing/**\n", "alwaysApply: false\n", "- Amounts are in cents"} {
+		if !strings.Contains(scoped, want) {
+			t.Errorf("scoped rule missing %q:\n%s", want, scoped)
+		}
+	}
+}
+
+func TestSizeLimitedExporters(t *testing.T) {
+	data := sampleExportData()
+	for i := 0; i < 600; i++ {
+		data.Memories = append(data.Memories, memory.Memory{
+			Content:    fmt.Sprintf("Note number %d about some part of the system", i),
+			MemoryType: memory.TypeNote,
+		})
+	}
+
+	copilot, _ := (&CopilotExporter{}).Export(data)
+	if len(copilot) > copilotMaxChars {
+		t.Errorf("copilot export is %d chars, limit %d", len(copilot), copilotMaxChars)
+	}
+	windsurf, _ := (&WindsurfExporter{}).Export(data)
+	if len(windsurf) > windsurfMaxChars {
+		t.Errorf("windsurf export is %d chars, limit %d", len(windsurf), windsurfMaxChars)
+	}
+	mdc, _ := (&CursorMDCExporter{}).Export(data)
+	if n := strings.Count(mdc, "\n"); n > cursorMaxLines {
+		t.Errorf("cursor rule is %d lines, limit %d", n, cursorMaxLines)
+	}
+	for name, out := range map[string]string{"copilot": copilot, "windsurf": windsurf, "cursor-mdc": mdc} {
+		if !strings.Contains(out, "Never store secrets in code") {
+			t.Errorf("%s: constraints should survive trimming", name)
+		}
+		if !strings.Contains(out, "Trimmed to fit") {
+			t.Errorf("%s: trimmed export should say so", name)
+		}
+	}
+}
+
+func TestSizeLimitedExporters_LeaveRoomForHandWrittenText(t *testing.T) {
+	data := sampleExportData()
+	for i := 0; i < 600; i++ {
+		data.Memories = append(data.Memories, memory.Memory{
+			Content:    fmt.Sprintf("Note number %d about some part of the system", i),
+			MemoryType: memory.TypeNote,
+		})
+	}
+	root := t.TempDir()
+	handWritten := "# Team rules\n\n" + strings.Repeat("- Review every migration with the DBA team.\n", 40)
+
+	for format, limit := range map[string]int{"copilot": copilotMaxChars, "windsurf": windsurfMaxChars} {
+		rel := FormatToFilename(format)
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, rel)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, rel), []byte(handWritten), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		exporter, _ := Get(format)
+		data.Reserved = reservedChars(root, rel, format)
+		out, _ := exporter.Export(data)
+		if err := writeExport(root, rel, format, out); err != nil {
+			t.Fatal(err)
+		}
+		written, _ := os.ReadFile(filepath.Join(root, rel))
+		if len(written) > limit {
+			t.Errorf("%s: file is %d chars, limit %d", format, len(written), limit)
+		}
+		if !strings.Contains(string(written), handWritten) || !strings.Contains(string(written), "Never store secrets in code") {
+			t.Errorf("%s: file lost hand-written text or constraints:\n%s", format, written)
+		}
+	}
+}
+
+func TestNestedPath(t *testing.T) {
+	cases := map[string]string{
+		"claude":     "services/billing/CLAUDE.md",
+		"agents":     "services/billing/AGENTS.md",
+		"gemini":     "services/billing/GEMINI.md",
+		"cursor-mdc": ".cursor/rules/memvra-services-billing.mdc",
+		"copilot":    "",
+		"json":       "",
+	}
+	for format, want := range cases {
+		if got := NestedPath(format, "services/billing"); got != want {
+			t.Errorf("NestedPath(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestWriteNestedExport_StaysInProject(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "repo")
+	os.MkdirAll(filepath.Join(root, "web"), 0o755)
+	os.MkdirAll(filepath.Join(parent, "other"), 0o755)
+
+	if err := writeNestedExport(root, "web", "web/CLAUDE.md", "claude", "# Web\n"); err != nil {
+		t.Fatalf("scope in the project: %v", err)
+	}
+	if err := writeNestedExport(root, "../other", "../other/CLAUDE.md", "claude", "# Other\n"); err == nil {
+		t.Error("expected a scope outside the project to be refused")
+	}
+	if _, err := os.Stat(filepath.Join(parent, "other", "CLAUDE.md")); err == nil {
+		t.Error("wrote a file outside the project")
+	}
+}
+
+func TestNestedExports_PathsWithSpaces(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".memvra"), 0o755)
+	os.WriteFile(filepath.Join(root, ".memvra", nestedManifest), []byte("my docs/CLAUDE.md\nweb/CLAUDE.md\n"), 0o644)
+
+	got := NestedExports(root)
+	if len(got) != 2 || got[0] != "my docs/CLAUDE.md" || got[1] != "web/CLAUDE.md" {
+		t.Errorf("NestedExports = %q", got)
+	}
+}
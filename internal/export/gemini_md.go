@@ -0,0 +1,13 @@
+package export
+
+// GeminiMDExporter renders context in GEMINI.md format for Gemini CLI,
+// which also loads GEMINI.md files from subdirectories.
+type GeminiMDExporter struct{}
+
+func (e *GeminiMDExporter) Export(data ExportData) (string, error) {
+	if data.Scope != "" {
+		return renderScopedMarkdown(data, "GEMINI.md"), nil
+	}
+	return renderInstructions(data, "Project Context", regionHeader, true) +
+		renderScopesMarkdown(data.Scopes, "GEMINI.md") + renderOmittedNote(data), nil
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// EnsureGitignore appends entries missing from the project's .gitignore
+// under a Memvra heading, creating the file if needed.
+func EnsureGitignore(root string, entries []string) {
+	path := filepath.Join(root, ".gitignore")
+	content, _ := os.ReadFile(path)
+	existing := string(content)
+
+	var toAdd []string
+	for _, entry := range entries {
+		if !strings.Contains(existing, entry) {
+			toAdd = append(toAdd, entry)
+		}
+	}
+	if len(toAdd) == 0 {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	if len(content) > 0 && !strings.HasSuffix(existing, "\n") {
+		_, _ = f.WriteString("\n")
+	}
+
+	_, _ = f.WriteString("\n# Memvra auto-generated context files\n")
+	for _, entry := range toAdd {
+		_, _ = f.WriteString(entry + "\n")
+	}
+}
+
+// TrackMemvraFile makes sure .memvra/<name> can be committed although the
+// rest of .memvra/ is ignored. git can't re-include a file inside an
+// ignored directory, so an ignored ".memvra/" becomes ".memvra/*" followed
+// by an exception for the file.
+func TrackMemvraFile(root, name string) {
+	path := filepath.Join(root, ".gitignore")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	exception := "!.memvra/" + name
+
+	lines := strings.Split(string(content), "\n")
+	changed := false
+	for i, line := range lines {
+		switch strings.TrimSpace(line) {
+		case ".memvra/", ".memvra", "/.memvra/", "/.memvra":
+			lines[i] = ".memvra/*"
+			changed = true
+		}
+	}
+	if !changed && !slices.Contains(lines, ".memvra/*") {
+		return // .memvra/ isn't ignored as a whole
+	}
+
+	out := strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+	if !slices.Contains(lines, exception) {
+		out += exception + "\n"
+	} else if !changed {
+		return
+	}
+	_ = os.WriteFile(path, []byte(out), 0o644)
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrackMemvraFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".gitignore")
+	os.WriteFile(path, []byte("node_modules/\n.memvra/\nCLAUDE.md\n"), 0o644)
+
+	TrackMemvraFile(root, "shared.toml")
+	TrackMemvraFile(root, "shared.toml")
+
+	got, _ := os.ReadFile(path)
+	want := "node_modules/\n.memvra/*\nCLAUDE.md\n!.memvra/shared.toml\n"
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTrackMemvraFile_NotIgnored(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".gitignore")
+	os.WriteFile(path, []byte("node_modules/\n"), 0o644)
+
+	TrackMemvraFile(root, "shared.toml")
+
+	got, _ := os.ReadFile(path)
+	if string(got) != "node_modules/\n" {
+		t.Errorf(".gitignore should be unchanged, got:\n%s", got)
+	}
+}
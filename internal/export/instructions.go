@@ -0,0 +1,90 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/memvra/memvra/internal/memory"
+	"github.com/memvra/memvra/internal/scanner"
+)
+
+// renderInstructions renders an agent instructions file (AGENTS.md,
+// GEMINI.md, Copilot and Windsurf rules). Sections are ordered by how much
+// they constrain the agent — constraints and conventions first, activity
+// last — so that trimming to a tool's size limit drops the least important
+// content. header is regionHeader or fileHeader.
+func renderInstructions(data ExportData, title, header string, withMCP bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — %s\n\n", data.Project.Name, title)
+	b.WriteString(header)
+
+	if profile := stackSummary(data.Stack); profile != "" {
+		fmt.Fprintf(&b, "%s\n\n", profile)
+	}
+
+	b.WriteString(memorySection("Constraints", memory.TypeConstraint, data.Memories))
+	b.WriteString(memorySection("Coding Conventions", memory.TypeConvention, data.Memories))
+	b.WriteString(memorySection("Architectural Decisions", memory.TypeDecision, data.Memories))
+	b.WriteString(memorySection("Notes", memory.TypeNote, data.Memories))
+	b.WriteString(memorySection("TODOs", memory.TypeTodo, data.Memories))
+
+	if withMCP {
+		b.WriteString(mcpInstructions)
+	}
+	b.WriteString(renderGitStateMarkdown(data.GitState))
+	b.WriteString(renderSessionsMarkdown(data.Sessions))
+	return b.String()
+}
+
+// stackSummary describes the tech stack in one sentence, e.g. "This is a Go
+// project using Gin, backed by PostgreSQL."
+func stackSummary(ts scanner.TechStack) string {
+	if ts.Language == "" {
+		return ""
+	}
+	s := "This is a " + ts.Language + " project"
+	if ts.Framework != "" {
+		s += " using " + ts.Framework
+	}
+	if ts.Database != "" {
+		s += ", backed by " + ts.Database
+	}
+	s += "."
+	if ts.Architecture != "" {
+		s += " Architecture: " + ts.Architecture + "."
+	}
+	if ts.TestFramework != "" {
+		s += " Tests use " + ts.TestFramework + "."
+	}
+	return s
+}
+
+// sizeLimit returns how much of a tool's limit of maxChars is left for the
+// export once the rest of the file is counted. At least one character is
+// left, as 0 would mean no limit.
+func sizeLimit(maxChars int, data ExportData) int {
+	return max(maxChars-data.Reserved, 1)
+}
+
+// fitLimit trims doc at a line boundary so that it stays within maxChars
+// characters and maxLines lines (0 means no limit), ending it with a note
+// naming the tool whose limit applied.
+func fitLimit(doc string, maxChars, maxLines int, tool string) string {
+	if (maxChars <= 0 || len(doc) <= maxChars) && (maxLines <= 0 || strings.Count(doc, "\n") <= maxLines) {
+		return doc
+	}
+
+	note := fmt.Sprintf("\n> Trimmed to fit %s's size limit. Use the Memvra MCP tools (memvra_search) for the full project memory.\n", tool)
+	lines := strings.SplitAfter(doc, "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if maxChars > 0 && b.Len()+len(line)+len(note) > maxChars {
+			break
+		}
+		if maxLines > 0 && i+strings.Count(note, "\n") >= maxLines {
+			break
+		}
+		b.WriteString(line)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n" + note
+}
@@ -16,6 +16,14 @@ type jsonOutput struct {
 	Project    jsonProject              `json:"project"`
 	Stack      jsonStack                `json:"stack"`
 	Memories   map[string][]jsonMemory  `json:"memories"`
+	Omitted    *jsonOmitted             `json:"omitted,omitempty"`
+}
+
+// jsonOmitted reports what was dropped to fit the token budget.
+type jsonOmitted struct {
+	Memories int    `json:"memories"`
+	Sessions int    `json:"sessions"`
+	Note     string `json:"note"`
 }
 
 type jsonGitState struct {
@@ -50,10 +58,12 @@ type jsonStack struct {
 }
 
 type jsonMemory struct {
-	ID         string  `json:"id"`
-	Content    string  `json:"content"`
-	Importance float64 `json:"importance"`
-	Source     string  `json:"source"`
+	ID         string   `json:"id"`
+	Content    string   `json:"content"`
+	Importance float64  `json:"importance"`
+	Source     string   `json:"source"`
+	Scope      string   `json:"scope,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
 }
 
 func (e *JSONExporter) Export(data ExportData) (string, error) {
@@ -100,6 +110,14 @@ func (e *JSONExporter) Export(data ExportData) (string, error) {
 		}
 	}
 
+	if data.OmittedMemories > 0 || data.OmittedSessions > 0 {
+		out.Omitted = &jsonOmitted{
+			Memories: data.OmittedMemories,
+			Sessions: data.OmittedSessions,
+			Note:     "Use the Memvra MCP tool memvra_search to find them.",
+		}
+	}
+
 	b, err := json.MarshalIndent(out, "", "  ")
 	if err != nil {
 		return "", err
@@ -116,6 +134,8 @@ func groupMemoriesByType(memories []memory.Memory) map[string][]jsonMemory {
 			Content:    m.Content,
 			Importance: m.Importance,
 			Source:     m.Source,
+			Scope:      m.Scope,
+			Tags:       m.Tags,
 		})
 	}
 	// Return nil map as empty object in JSON.
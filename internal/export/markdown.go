@@ -50,6 +50,7 @@ func (e *MarkdownExporter) Export(data ExportData) (string, error) {
 	} {
 		b.WriteString(memorySection(section.heading, section.mt, data.Memories))
 	}
+	b.WriteString(renderOmittedNote(data))
 
 	return b.String(), nil
 }
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// regionBegin and regionEnd delimit the Memvra-managed region of a context
+// file. Everything outside the region is left alone, so hand-written rules
+// can live in the same file. The begin marker carries a checksum of the
+// region, which reveals hand edits inside it.
+const (
+	regionBegin = "<!-- memvra:begin"
+	regionEnd   = "<!-- memvra:end -->"
+)
+
+// hasRegion reports whether format is written into a managed region of a
+// file that may also hold hand-written content. The other formats own their
+// whole file.
+func hasRegion(format string) bool {
+	switch format {
+	case "claude", "cursor", "agents", "gemini", "copilot", "windsurf":
+		return true
+	default:
+		return false
+	}
+}
+
+// Headers written under the title of generated files. Files with a managed
+// region say where hand-written text belongs; other files are regenerated
+// whole.
+const (
+	regionHeader = "> " + generatedMarker + "(https://memvra.com). Edit outside the memvra markers; the text between them is regenerated.\n\n"
+	fileHeader   = "> " + generatedMarker + "(https://memvra.com). Do not edit manually.\n\n"
+)
+
+// isGenerated reports whether content was generated by Memvra, either as a
+// whole file or as a file with a managed region.
+func isGenerated(content string) bool {
+	return strings.Contains(content, regionBegin) || hasGeneratedHeader(content)
+}
+
+// hasGeneratedHeader reports whether content starts like a file written by
+// Memvra: the generated header is the first line under the title, after
+// any front matter. The phrase quoted further down doesn't count.
+func hasGeneratedHeader(content string) bool {
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		if end := strings.Index(rest, "\n---\n"); end >= 0 {
+			content = strings.TrimLeft(rest[end+len("\n---\n"):], "\n")
+		}
+	}
+	_, rest, _ := strings.Cut(content, "\n")
+	header, _, _ := strings.Cut(strings.TrimLeft(rest, "\n"), "\n")
+	return strings.HasPrefix(header, "> "+generatedMarker) ||
+		strings.HasPrefix(header, "# Generated by Memvra")
+}
+
+// regionChecksum returns the checksum recorded in the begin marker for body.
+func regionChecksum(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:6])
+}
+
+// renderRegion wraps output in the region markers.
+func renderRegion(output string) string {
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return fmt.Sprintf("%s checksum:%s -->\n%s%s\n", regionBegin, regionChecksum(output), output, regionEnd)
+}
+
+// findRegion locates the managed region in content. start and end span the
+// markers, body is the text between them and sum the checksum recorded in
+// the begin marker ("" if none, e.g. for markers added by hand).
+func findRegion(content string) (start, end int, body, sum string, ok bool, err error) {
+	start = strings.Index(content, regionBegin)
+	if start < 0 {
+		return 0, 0, "", "", false, nil
+	}
+	closeTag := strings.Index(content[start:], "-->")
+	if closeTag < 0 {
+		return 0, 0, "", "", false, fmt.Errorf("unterminated %s marker", regionBegin)
+	}
+	attrs := strings.TrimSpace(content[start+len(regionBegin) : start+closeTag])
+	sum = strings.TrimPrefix(attrs, "checksum:")
+
+	bodyStart := start + closeTag + len("-->")
+	if strings.HasPrefix(content[bodyStart:], "\n") {
+		bodyStart++
+	}
+	n := strings.Index(content[bodyStart:], regionEnd)
+	if n < 0 {
+		return 0, 0, "", "", false, fmt.Errorf("missing %s marker", regionEnd)
+	}
+	end = bodyStart + n + len(regionEnd)
+	if strings.HasPrefix(content[end:], "\n") {
+		end++
+	}
+	return start, end, content[bodyStart : bodyStart+n], sum, true, nil
+}
+
+// mergeRegion returns existing with its managed region replaced by output.
+// A file without markers gets the region appended, unless it is a whole
+// file generated by an earlier Memvra version, which is replaced. edited
+// reports that the old region was changed by hand since it was written.
+func mergeRegion(existing, output string) (merged string, edited bool, err error) {
+	region := renderRegion(output)
+
+	start, end, body, sum, ok, err := findRegion(existing)
+	if err != nil {
+		return "", false, err
+	}
+	if !ok {
+		if strings.TrimSpace(existing) == "" || isGenerated(existing) {
+			return region, false, nil
+		}
+		return strings.TrimRight(existing, "\n") + "\n\n" + region, false, nil
+	}
+
+	edited = sum != "" && sum != regionChecksum(body)
+	return existing[:start] + region + existing[end:], edited, nil
+}
+
+// HandWritten returns the parts of a context file that Memvra did not
+// generate: everything outside the managed region, or "" for a file
+// generated whole by an earlier version.
+func HandWritten(content string) string {
+	if _, _, _, _, ok, _ := findRegion(content); ok {
+		return removeRegion(content)
+	}
+	if isGenerated(content) {
+		return ""
+	}
+	return content
+}
+
+// removeRegion returns content without its managed region.
+func removeRegion(content string) string {
+	start, end, _, _, ok, err := findRegion(content)
+	if err != nil || !ok {
+		return content
+	}
+	return content[:start] + content[end:]
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeRegion_NewFile(t *testing.T) {
+	merged, edited, err := mergeRegion("", "# Project\n")
+	if err != nil || edited {
+		t.Fatalf("mergeRegion: edited=%v err=%v", edited, err)
+	}
+	if !strings.HasPrefix(merged, regionBegin+" checksum:") || !strings.HasSuffix(merged, regionEnd+"\n") {
+		t.Errorf("expected only the managed region, got:\n%s", merged)
+	}
+}
+
+func TestMergeRegion_ReplacesLegacyGeneratedFile(t *testing.T) {
+	legacy := "# app — Project Context\n\n> Generated by [Memvra](https://memvra.com). Do not edit manually.\n\nold\n"
+	merged, _, err := mergeRegion(legacy, "new\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(merged, "old") || !strings.Contains(merged, "new") {
+		t.Errorf("legacy generated file should be replaced, got:\n%s", merged)
+	}
+}
+
+func TestMergeRegion_KeepsSurroundingContent(t *testing.T) {
+	first, _, _ := mergeRegion("", "v1\n")
+	existing := "intro\n\n" + first + "\noutro\n"
+
+	merged, edited, err := mergeRegion(existing, "v2\n")
+	if err != nil || edited {
+		t.Fatalf("mergeRegion: edited=%v err=%v", edited, err)
+	}
+	if !strings.HasPrefix(merged, "intro\n\n") || !strings.HasSuffix(merged, "\noutro\n") {
+		t.Errorf("content outside the region changed:\n%s", merged)
+	}
+	if strings.Contains(merged, "v1") || !strings.Contains(merged, "v2") {
+		t.Errorf("region not replaced:\n%s", merged)
+	}
+}
+
+func TestMergeRegion_DetectsHandEdits(t *testing.T) {
+	first, _, _ := mergeRegion("", "generated\n")
+	tampered := strings.Replace(first, "generated", "generated, then edited", 1)
+
+	_, edited, err := mergeRegion(tampered, "generated\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !edited {
+		t.Error("expected hand edits inside the region to be detected")
+	}
+
+	// Markers added by hand have no checksum and are filled in silently.
+	_, edited, _ = mergeRegion("rules\n<!-- memvra:begin -->\n<!-- memvra:end -->\n", "generated\n")
+	if edited {
+		t.Error("empty hand-placed markers should not count as an edit")
+	}
+}
+
+func TestMergeRegion_Unterminated(t *testing.T) {
+	if _, _, err := mergeRegion("<!-- memvra:begin -->\nno end\n", "x\n"); err == nil {
+		t.Error("expected an error for a region without an end marker")
+	}
+}
+
+func TestRemoveRegion(t *testing.T) {
+	region, _, _ := mergeRegion("", "generated\n")
+	if got := removeRegion("before\n" + region + "after\n"); got != "before\nafter\n" {
+		t.Errorf("removeRegion = %q", got)
+	}
+}
+
+func TestHandWritten(t *testing.T) {
+	region, _, _ := mergeRegion("", "generated\n")
+	if got := HandWritten("mine\n" + region); got != "mine\n" {
+		t.Errorf("HandWritten should drop the managed region, got %q", got)
+	}
+	if got := HandWritten("# app\n\n> Generated by [Memvra](https://memvra.com). Do not edit manually.\n"); got != "" {
+		t.Errorf("whole generated files have no hand-written content, got %q", got)
+	}
+	if got := HandWritten("mine\n"); got != "mine\n" {
+		t.Errorf("hand-written file should be returned as is, got %q", got)
+	}
+}
+
+func TestIsGenerated_OnlyTheHeader(t *testing.T) {
+	cases := map[string]bool{
+		"# app — Project Context\n\n> Generated by [Memvra](https://memvra.com). Do not edit manually.\n\nold\n": true,
+		"# app — AI Rules\n# Generated by Memvra (https://memvra.com)\n\nrules\n":                                true,
+		"---\ndescription: x\n---\n\n# app — Project Rules\n\n> Generated by [Memvra](https://memvra.com).\n":    true,
+		"# Team notes\n\nOur CLAUDE.md used to say \"Generated by [Memvra]\" at the top.\n":                      false,
+		"# Team notes\n\nWe keep these rules by hand.\n\n> Generated by [Memvra](https://memvra.com)\n":          false,
+	}
+	for content, want := range cases {
+		if got := isGenerated(content); got != want {
+			t.Errorf("isGenerated(%q) = %v, want %v", content, got, want)
+		}
+	}
+
+	handWritten := "# Team notes\n\nWe quote \"Generated by [Memvra]\" here.\n"
+	merged, _, _ := mergeRegion(handWritten, "new\n")
+	if !strings.HasPrefix(merged, handWritten) {
+		t.Errorf("hand-written file mentioning the header was replaced:\n%s", merged)
+	}
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/memvra/memvra/internal/config"
+	"github.com/memvra/memvra/internal/git"
+	"github.com/memvra/memvra/internal/memory"
+)
+
+// templatesDir holds user-defined export templates, relative to the
+// project config dir. Each <name>.tmpl file defines the format <name>.
+const templatesDir = "templates"
+
+// outputDirective matches the optional first-line comment naming the file a
+// template is written to, e.g. {{/* output: docs/AI_CONTEXT.md */}}.
+var outputDirective = regexp.MustCompile(`^\{\{-?\s*/\*\s*output:\s*(\S+)\s*\*/\s*-?\}\}\r?\n?`)
+
+// TemplateExporter renders ExportData with a user-defined text/template.
+type TemplateExporter struct {
+	Name string
+	// Output is the file the format is written to, relative to the project
+	// root (slash-separated).
+	Output string
+	tmpl   *template.Template
+}
+
+// Export executes the template over data.
+func (e *TemplateExporter) Export(data ExportData) (string, error) {
+	var b bytes.Buffer
+	if err := e.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("template %s: %w", e.Name, err)
+	}
+	return b.String(), nil
+}
+
+// templateFuncs are the helpers available to export templates.
+var templateFuncs = template.FuncMap{
+	// byType returns the memories of one type, e.g. {{range byType .Memories "decision"}}.
+	"byType": func(memories []memory.Memory, memType string) []memory.Memory {
+		var out []memory.Memory
+		for _, m := range memories {
+			if m.MemoryType == memory.MemoryType(memType) {
+				out = append(out, m)
+			}
+		}
+		return out
+	},
+	// section renders memories of one type as a markdown list under a heading.
+	"section": func(heading, memType string, memories []memory.Memory) string {
+		return memorySection(heading, memory.MemoryType(memType), memories)
+	},
+	"sessions":  func(sessions []memory.Session) string { return renderSessionsMarkdown(sessions) },
+	"gitState":  func(gs git.WorkingState) string { return renderGitStateMarkdown(gs) },
+	"stack":     stackSummary,
+	"scopeNote": scopeNote,
+	// omitted renders the note about items dropped to fit the token budget.
+	"omitted": renderOmittedNote,
+	"join":    strings.Join,
+	"date":    func(t time.Time) string { return t.Format("2006-01-02") },
+}
+
+// LoadTemplate parses the export template at file. The format name is the
+// file name without its .tmpl extension; the output file comes from a
+// leading output comment and defaults to <name>.md.
+func LoadTemplate(file string) (*TemplateExporter, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("export: read template: %w", err)
+	}
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(file), ".tmpl"))
+
+	e := &TemplateExporter{Name: name, Output: name + ".md"}
+	text := string(src)
+	if m := outputDirective.FindStringSubmatch(text); m != nil {
+		e.Output = path.Clean(filepath.ToSlash(m[1]))
+		text = text[len(m[0]):]
+	}
+	if !filepath.IsLocal(filepath.FromSlash(e.Output)) {
+		return nil, fmt.Errorf("export: template %s: output %q must be a path inside the project", name, e.Output)
+	}
+	if top, _, _ := strings.Cut(e.Output, "/"); strings.EqualFold(top, ".memvra") || strings.EqualFold(top, ".git") {
+		return nil, fmt.Errorf("export: template %s: output %q must not be inside %s/", name, e.Output, top)
+	}
+
+	e.tmpl, err = template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("export: parse template: %w", err)
+	}
+	return e, nil
+}
+
+// Templates are the export formats a project defines in its
+// .memvra/templates directory, by name.
+type Templates map[string]*TemplateExporter
+
+// LoadTemplates loads the templates in root's .memvra/templates directory.
+// Templates named after a built-in format are rejected. Templates that fail
+// to load are reported in the returned error; the others are still
+// returned.
+func LoadTemplates(root string) (Templates, error) {
+	paths, _ := filepath.Glob(filepath.Join(config.ProjectConfigDirPath(root), templatesDir, "*.tmpl"))
+	sort.Strings(paths)
+
+	templates := make(Templates)
+	var errs []error
+	for _, p := range paths {
+		e, err := LoadTemplate(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, exists := registry[e.Name]; exists {
+			errs = append(errs, fmt.Errorf("export: template %s: %q is already a format", filepath.Base(p), e.Name))
+			continue
+		}
+		templates[e.Name] = e
+	}
+	return templates, errors.Join(errs...)
+}
+
+// Get returns the Exporter for format: a built-in one or one of t.
+func (t Templates) Get(format string) (Exporter, bool) {
+	if e, ok := Get(format); ok {
+		return e, true
+	}
+	e, ok := t[format]
+	return e, ok
+}
+
+// Filename returns the file format is written to, relative to the project
+// root, or "" for an unknown format.
+func (t Templates) Filename(format string) string {
+	if name := FormatToFilename(format); name != "" {
+		return name
+	}
+	if e, ok := t[format]; ok {
+		return e.Output
+	}
+	return ""
+}
+
+// Formats returns the built-in format names and those of t, sorted.
+func (t Templates) Formats() []string {
+	formats := ValidFormats()
+	for name := range t {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
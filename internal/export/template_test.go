@@ -0,0 +1,107 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, root, name, text string) {
+	t.Helper()
+	dir := filepath.Join(root, ".memvra", "templates")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	root := t.TempDir()
+
+	writeTemplate(t, root, "onboarding.tmpl", `{{/* output: docs/ONBOARDING.md */}}
+# {{.Project.Name}}
+{{stack .Stack}}
+{{range byType .Memories "decision"}}- {{.Content}}
+{{end}}{{section "Rules" "constraint" .Memories}}{{sessions .Sessions}}`)
+	writeTemplate(t, root, "plain.tmpl", `{{len .Memories}} memories`)
+
+	templates, err := LoadTemplates(root)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+
+	e, ok := templates.Get("onboarding")
+	if !ok {
+		t.Fatalf("template format not loaded; formats: %v", templates.Formats())
+	}
+	out, err := e.Export(sampleExportData())
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if !strings.HasPrefix(out, "# testapp\n") {
+		t.Errorf("output directive should be stripped, got:\n%s", out)
+	}
+	for _, want := range []string{"This is a Go project using Gin", "- Use PostgreSQL", "## Rules", "Never store secrets", "Recent Activity"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Use camelCase") {
+		t.Error("byType should only return memories of the given type")
+	}
+
+	if got := templates.Filename("onboarding"); got != "docs/ONBOARDING.md" {
+		t.Errorf("Filename(onboarding) = %q", got)
+	}
+	if got := templates.Filename("plain"); got != "plain.md" {
+		t.Errorf("Filename(plain) = %q, want default plain.md", got)
+	}
+	if got := templates.Filename("claude"); got != "CLAUDE.md" {
+		t.Errorf("Filename(claude) = %q, want the built-in CLAUDE.md", got)
+	}
+
+	// Templates belong to their project; nothing is registered globally.
+	if _, ok := Get("onboarding"); ok || FormatToFilename("onboarding") != "" {
+		t.Error("a project's templates should not be known outside its Templates")
+	}
+	other, _ := LoadTemplates(t.TempDir())
+	if _, ok := other.Get("onboarding"); ok {
+		t.Error("another project should not see these templates")
+	}
+}
+
+func TestLoadTemplates_Errors(t *testing.T) {
+	root := t.TempDir()
+
+	writeTemplate(t, root, "claude.tmpl", `shadow`)
+	writeTemplate(t, root, "broken.tmpl", `{{range}}`)
+	writeTemplate(t, root, "escape.tmpl", "{{/* output: ../outside.md */}}\nx")
+	writeTemplate(t, root, "config.tmpl", "{{/* output: .memvra/config.toml */}}\nx")
+	writeTemplate(t, root, "hook.tmpl", "{{/* output: .git/hooks/pre-commit */}}\nx")
+	writeTemplate(t, root, "good.tmpl", `ok`)
+
+	templates, err := LoadTemplates(root)
+	if err == nil {
+		t.Fatal("expected errors for invalid templates")
+	}
+	for _, want := range []string{"already a format", "broken", "inside the project", "inside .memvra/", "inside .git/"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error should mention %q, got: %v", want, err)
+		}
+	}
+
+	if e, _ := templates.Get("claude"); e != registry["claude"] || templates.Filename("claude") != "CLAUDE.md" {
+		t.Error("built-in format should not be replaced by a template")
+	}
+	if _, ok := templates.Get("good"); !ok {
+		t.Error("valid templates should still be loaded")
+	}
+	for _, name := range []string{"broken", "config", "hook"} {
+		if _, ok := templates.Get(name); ok {
+			t.Errorf("template %s should not be loaded", name)
+		}
+	}
+}
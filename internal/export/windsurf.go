@@ -0,0 +1,12 @@
+package export
+
+// windsurfMaxChars is Windsurf's size limit for a workspace rules file.
+const windsurfMaxChars = 6000
+
+// WindsurfExporter renders context as .windsurfrules.
+type WindsurfExporter struct{}
+
+func (e *WindsurfExporter) Export(data ExportData) (string, error) {
+	doc := renderInstructions(data, "Windsurf Rules", regionHeader, true) + renderOmittedNote(data)
+	return fitLimit(doc, sizeLimit(windsurfMaxChars, data), 0, "Windsurf"), nil
+}
@@ -3,6 +3,8 @@
 package git
 
 import (
+	"errors"
+	"fmt"
 	"os/exec"
 	"strings"
 )
@@ -84,6 +86,30 @@ func CaptureWorkingState(dir string) WorkingState {
 	return ws
 }
 
+// Diff returns the output of git diff in dir: the unstaged changes, or the
+// staged ones when staged is set, limited to paths when any are given.
+// Unlike CaptureWorkingState it reports errors, such as dir not being a repo.
+func Diff(dir string, staged bool, paths ...string) (string, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git diff: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git diff: %w", err)
+	}
+	return string(out), nil
+}
+
 // gitOutput runs a git command and returns trimmed stdout.
 // Returns "" on any error.
 func gitOutput(dir string, args ...string) string {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -171,6 +172,34 @@ func TestCaptureWorkingState_MixedState(t *testing.T) {
 	}
 }
 
+func TestDiff(t *testing.T) {
+	dir := initTestRepo(t)
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "b.go"), []byte("package b\n"), 0o644)
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "add files")
+
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n// unstaged\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "b.go"), []byte("package b\n// staged\n"), 0o644)
+	gitCmd(t, dir, "add", "b.go")
+
+	unstaged, err := Diff(dir, false)
+	if err != nil || !strings.Contains(unstaged, "+// unstaged") || strings.Contains(unstaged, "b.go") {
+		t.Errorf("unstaged diff: %v\n%s", err, unstaged)
+	}
+	staged, _ := Diff(dir, true)
+	if !strings.Contains(staged, "+// staged") || strings.Contains(staged, "a.go") {
+		t.Errorf("staged diff:\n%s", staged)
+	}
+	if only, _ := Diff(dir, false, "b.go"); only != "" {
+		t.Errorf("path-limited diff should be empty, got:\n%s", only)
+	}
+
+	if _, err := Diff(t.TempDir(), false); err == nil {
+		t.Error("expected an error outside a repository")
+	}
+}
+
 // initTestRepo creates a temp dir with a git repo and an initial commit.
 func initTestRepo(t *testing.T) string {
 	t.Helper()
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Handshake launches the server the way a client configured with e would,
+// from the directory dir, and returns the names of the tools it lists. The
+// process is killed when ctx ends.
+func Handshake(ctx context.Context, e ServerEntry, dir string) ([]string, error) {
+	stdio := transport.NewStdioWithOptions(e.Command, nil, e.Args,
+		transport.WithCommandFunc(func(ctx context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+			cmd := exec.CommandContext(ctx, command, args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), env...)
+			return cmd, nil
+		}),
+	)
+	if err := stdio.Start(ctx); err != nil {
+		return nil, fmt.Errorf("start %s: %w", e.Command, err)
+	}
+	c := client.NewClient(stdio)
+	defer func() { _ = c.Close() }()
+
+	init := mcp.InitializeRequest{}
+	init.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	init.Params.ClientInfo = mcp.Implementation{Name: "memvra-doctor", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, init); err != nil {
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+
+	res, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("list tools: %w", err)
+	}
+	names := make([]string, 0, len(res.Tools))
+	for _, t := range res.Tools {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/memvra/memvra/internal/config"
+)
+
+const (
+	// HTTPEndpoint is the path the streamable HTTP transport is served on.
+	HTTPEndpoint = "/mcp"
+
+	// RootParam is the query parameter that selects the project a request
+	// is for, e.g. /mcp?root=/home/me/api.
+	RootParam = "root"
+
+	// shutdownTimeout bounds how long in-flight tool calls may take to
+	// finish once the server is asked to stop.
+	shutdownTimeout = 10 * time.Second
+
+	// heartbeatInterval keeps idle SSE streams open through proxies.
+	heartbeatInterval = 30 * time.Second
+)
+
+// HTTPOptions configures ServeHTTP.
+type HTTPOptions struct {
+	// Addr is the address to listen on, e.g. "127.0.0.1:8765". An empty
+	// host (":8765") means 127.0.0.1; addresses other than loopback ones
+	// require a Token.
+	Addr string
+	// Token, when set, must be sent by clients as "Authorization: Bearer <token>".
+	Token string
+	// AllowedHosts are host names, besides localhost and loopback
+	// addresses, that requests may name in their Host and Origin headers,
+	// e.g. the machine's name when listening on 0.0.0.0. A specific listen
+	// address is allowed as well.
+	AllowedHosts []string
+	// Root is the project served to requests without a root parameter.
+	// When empty, every request must name its project.
+	Root string
+	// OnListen, if set, is called with the bound address once the server
+	// is listening.
+	OnListen func(addr net.Addr)
+}
+
+// ServeHTTP serves the MCP server over streamable HTTP, with SSE streams
+// for notifications, until ctx is cancelled. Several clients can connect at
+// once, each to the project named by its root parameter; every project is
+// opened once and shared by all of its clients. On cancellation open
+// streams are closed and in-flight calls get shutdownTimeout to finish.
+func ServeHTTP(ctx context.Context, opts HTTPOptions) error {
+	addr, err := listenAddr(opts.Addr, opts.Token)
+	if err != nil {
+		return err
+	}
+	allowed := opts.AllowedHosts
+	if host, _, _ := net.SplitHostPort(addr); !net.ParseIP(host).IsUnspecified() {
+		allowed = append(allowed, host)
+	}
+
+	h := newHTTPHandler(ctx, opts.Token, allowed)
+	defer h.Close()
+
+	if opts.Root != "" {
+		p, err := h.project(opts.Root)
+		if err != nil {
+			return err
+		}
+		h.defaultRoot = p.srv.root
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	if opts.OnListen != nil {
+		opts.OnListen(ln.Addr())
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(HTTPEndpoint, h)
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shut down: %w", err)
+	}
+	return nil
+}
+
+// listenAddr returns addr with an empty host replaced by 127.0.0.1. Without
+// a token it refuses addresses other than loopback ones: anyone who could
+// reach the server could use every tool on every initialized project on
+// the machine.
+func listenAddr(addr, token string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if token == "" && !isLoopback(host) {
+		return "", fmt.Errorf("refusing to listen on %s without a token; set --token or MEMVRA_MCP_TOKEN, or listen on 127.0.0.1", addr)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// isLoopback reports whether host names the loopback interface.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// httpHandler routes MCP requests to a server per project.
+type httpHandler struct {
+	token        string
+	allowedHosts map[string]bool
+	defaultRoot  string
+	// streams is cancelled when the server stops, ending SSE streams that
+	// would otherwise keep their connections busy forever.
+	streams context.Context
+
+	mu       sync.Mutex
+	projects map[string]*httpProject
+}
+
+type httpProject struct {
+	srv     *Server
+	handler *server.StreamableHTTPServer
+}
+
+func newHTTPHandler(streams context.Context, token string, allowedHosts []string) *httpHandler {
+	h := &httpHandler{
+		token:        token,
+		allowedHosts: make(map[string]bool, len(allowedHosts)),
+		streams:      streams,
+		projects:     make(map[string]*httpProject),
+	}
+	for _, host := range allowedHosts {
+		h.allowedHosts[strings.ToLower(strings.Trim(host, "[]"))] = true
+	}
+	return h
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.allowedOrigin(r) {
+		http.Error(w, "host or origin not allowed", http.StatusForbidden)
+		return
+	}
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="memvra"`)
+		http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	root := r.URL.Query().Get(RootParam)
+	if root == "" {
+		root = h.defaultRoot
+	}
+	if root == "" {
+		http.Error(w, fmt.Sprintf("no project: add ?%s=<project path> to the URL", RootParam), http.StatusBadRequest)
+		return
+	}
+	p, err := h.project(root)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(h.streams, cancel)
+		defer stop()
+		r = r.WithContext(ctx)
+	}
+	if r.Method == http.MethodPost && p.serveSubscription(w, r) {
+		return
+	}
+	p.handler.ServeHTTP(w, r)
+}
+
+// serveSubscription answers r if it is a subscribe or unsubscribe request,
+// which mcp-go doesn't route, and reports whether it was. Any other request
+// gets its body back for the MCP handler to read.
+func (p *httpProject) serveSubscription(w http.ResponseWriter, r *http.Request) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %v", err), http.StatusBadRequest)
+		return true
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp, ok := p.srv.subs.handle(r.Header.Get(server.HeaderKeySessionID), body)
+	if !ok {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+	return true
+}
+
+// allowedOrigin reports whether r's Host header, and its Origin header if
+// it has one, name this server: localhost or an allowed host. Browsers set
+// both, so a web page can't reach the server by pointing its own domain at
+// 127.0.0.1 (DNS rebinding).
+func (h *httpHandler) allowedOrigin(r *http.Request) bool {
+	host := r.Host
+	if hostOnly, _, err := net.SplitHostPort(host); err == nil {
+		host = hostOnly
+	}
+	if !h.allowedHost(host) {
+		return false
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !h.allowedHost(u.Hostname()) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowedHost reports whether requests may name host.
+func (h *httpHandler) allowedHost(host string) bool {
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	return isLoopback(host) || h.allowedHosts[host]
+}
+
+// authorized reports whether r carries the configured bearer token.
+func (h *httpHandler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return true
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) == 1
+}
+
+// project returns the Memvra project at root, opening it on first use.
+// Only initialized projects are served: the server never creates a
+// database for a path a client made up.
+func (h *httpHandler) project(root string) (*httpProject, error) {
+	if !filepath.IsAbs(root) {
+		return nil, fmt.Errorf("project root %q must be an absolute path", root)
+	}
+	root = filepath.Clean(root)
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if p, ok := h.projects[root]; ok {
+		return p, nil
+	}
+
+	if _, err := os.Stat(config.ProjectDBPath(root)); err != nil {
+		return nil, fmt.Errorf("no Memvra project at %s (run `memvra init` there)", root)
+	}
+	srv, err := NewServer(root)
+	if err != nil {
+		return nil, fmt.Errorf("open project %s: %w", root, err)
+	}
+	p := &httpProject{
+		srv: srv,
+		handler: server.NewStreamableHTTPServer(srv.newMCPServer(),
+			server.WithEndpointPath(HTTPEndpoint),
+			server.WithHeartbeatInterval(heartbeatInterval),
+		),
+	}
+	h.projects[root] = p
+	return p, nil
+}
+
+// Close closes the databases of all opened projects.
+func (h *httpHandler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for root, p := range h.projects {
+		p.srv.Close()
+		delete(h.projects, root)
+	}
+}
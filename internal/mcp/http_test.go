@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+
+func postMCP(t *testing.T, ts *httptest.Server, query, token, body string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+HTTPEndpoint+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	out, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(out)
+}
+
+func newTestHTTPHandler(t *testing.T, token string) (*httptest.Server, *httpHandler) {
+	t.Helper()
+	h := newHTTPHandler(context.Background(), token, []string{"mcp.example.internal"})
+	t.Cleanup(h.Close)
+	mux := http.NewServeMux()
+	mux.Handle(HTTPEndpoint, h)
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts, h
+}
+
+func TestHTTP_RoutesByRoot(t *testing.T) {
+	srv := setupTestServer(t)
+	ts, _ := newTestHTTPHandler(t, "")
+
+	status, body := postMCP(t, ts, "?"+RootParam+"="+url.QueryEscape(srv.root), "", initializeRequest)
+	if status != http.StatusOK || !strings.Contains(body, `"name":"memvra"`) {
+		t.Fatalf("initialize: %d %s", status, body)
+	}
+
+	if status, body := postMCP(t, ts, "", "", initializeRequest); status != http.StatusBadRequest {
+		t.Errorf("without a root: %d %s", status, body)
+	}
+	if status, body := postMCP(t, ts, "?"+RootParam+"="+url.QueryEscape(t.TempDir()), "", initializeRequest); status != http.StatusNotFound {
+		t.Errorf("uninitialized root: %d %s", status, body)
+	}
+	if status, body := postMCP(t, ts, "?"+RootParam+"=relative", "", initializeRequest); status != http.StatusNotFound {
+		t.Errorf("relative root: %d %s", status, body)
+	}
+}
+
+func TestHTTP_DefaultRootAndSharing(t *testing.T) {
+	srv := setupTestServer(t)
+	ts, h := newTestHTTPHandler(t, "")
+	p, err := h.project(srv.root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.defaultRoot = p.srv.root
+
+	if status, body := postMCP(t, ts, "", "", initializeRequest); status != http.StatusOK {
+		t.Fatalf("default root: %d %s", status, body)
+	}
+	again, err := h.project(srv.root + "/")
+	if err != nil || again != p {
+		t.Errorf("expected the project to be opened once, got %p and %p (%v)", p, again, err)
+	}
+}
+
+func TestHTTP_Subscribe(t *testing.T) {
+	srv := setupTestServer(t)
+	ts, h := newTestHTTPHandler(t, "")
+	p, err := h.project(srv.root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.defaultRoot = p.srv.root
+
+	subscribe := `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"` + uriSessionsRecent + `"}}`
+	if _, body := postMCP(t, ts, "", "", subscribe); !strings.Contains(body, `"error"`) {
+		t.Errorf("subscribe without a session: %s", body)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+HTTPEndpoint, strings.NewReader(subscribe))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Mcp-Session-Id", "session-1")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"result"`) {
+		t.Fatalf("subscribe: %d %s", resp.StatusCode, body)
+	}
+	if got := p.srv.subs.subscribers(uriSessionsRecent); len(got) != 1 || got[0] != "session-1" {
+		t.Errorf("subscribers = %v, want [session-1]", got)
+	}
+
+	// Other requests still reach the MCP handler, body intact.
+	if status, body := postMCP(t, ts, "", "", initializeRequest); status != http.StatusOK || !strings.Contains(body, `"name":"memvra"`) {
+		t.Errorf("initialize after subscribe: %d %s", status, body)
+	}
+}
+
+func TestHTTP_BearerToken(t *testing.T) {
+	srv := setupTestServer(t)
+	ts, _ := newTestHTTPHandler(t, "s3cret")
+	query := "?" + RootParam + "=" + url.QueryEscape(srv.root)
+
+	if status, _ := postMCP(t, ts, query, "", initializeRequest); status != http.StatusUnauthorized {
+		t.Errorf("no token: got %d", status)
+	}
+	if status, _ := postMCP(t, ts, query, "wrong", initializeRequest); status != http.StatusUnauthorized {
+		t.Errorf("wrong token: got %d", status)
+	}
+	if status, body := postMCP(t, ts, query, "s3cret", initializeRequest); status != http.StatusOK {
+		t.Errorf("valid token: %d %s", status, body)
+	}
+}
+
+func TestHTTP_RejectsForeignHostAndOrigin(t *testing.T) {
+	srv := setupTestServer(t)
+	ts, _ := newTestHTTPHandler(t, "")
+	query := "?" + RootParam + "=" + url.QueryEscape(srv.root)
+
+	tests := []struct {
+		host, origin string
+		want         int
+	}{
+		{"", "", http.StatusOK},
+		{"localhost:8765", "http://localhost:3000", http.StatusOK},
+		{"mcp.example.internal:8765", "", http.StatusOK},
+		{"attacker.example:8765", "", http.StatusForbidden},
+		{"", "http://attacker.example", http.StatusForbidden},
+		{"", "null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+HTTPEndpoint+query, strings.NewReader(initializeRequest))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if tt.host != "" {
+			req.Host = tt.host
+		}
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("host %q origin %q: got %d, want %d", tt.host, tt.origin, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		addr, token string
+		want        string
+		wantErr     bool
+	}{
+		{":8765", "", "127.0.0.1:8765", false},
+		{"127.0.0.1:8765", "", "127.0.0.1:8765", false},
+		{"localhost:8765", "", "localhost:8765", false},
+		{"[::1]:8765", "", "[::1]:8765", false},
+		{"0.0.0.0:8765", "", "", true},
+		{"192.168.1.10:8765", "", "", true},
+		{"0.0.0.0:8765", "s3cret", "0.0.0.0:8765", false},
+		{"8765", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := listenAddr(tt.addr, tt.token)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("listenAddr(%q, %q) = %q, %v", tt.addr, tt.token, got, err)
+		}
+	}
+}
@@ -2,11 +2,27 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
 )
 
+// ServerName is the key Memvra's entry is stored under in client configs.
+const ServerName = "memvra"
+
+// ServerEntry is how a client launches the Memvra MCP server.
+type ServerEntry struct {
+	Command string
+	Args    []string
+}
+
+// defaultEntry is the entry written by Install.
+var defaultEntry = ServerEntry{Command: "memvra", Args: []string{"mcp"}}
+
 // mcpConfig represents the MCP configuration file structure used by
 // Claude Code and Cursor.
 type mcpConfig struct {
@@ -18,59 +34,377 @@ type mcpServerEntry struct {
 	Args    []string `json:"args"`
 }
 
-// InstallClaudeCode registers Memvra as an MCP server in Claude Code's
-// global config at ~/.claude/mcp.json.
-func InstallClaudeCode() error {
+// configFormat is the layout of a client's MCP configuration.
+type configFormat int
+
+const (
+	// formatMCPServers is {"mcpServers": {name: {command, args}}}, used by
+	// Claude Code, Cursor, Windsurf and Gemini CLI.
+	formatMCPServers configFormat = iota
+	// formatVSCode is {"servers": {name: {type: "stdio", command, args}}}.
+	formatVSCode
+	// formatZed is Zed's settings.json: {"context_servers": {name: {...}}}.
+	formatZed
+	// formatCodex is Codex's config.toml: [mcp_servers.name].
+	formatCodex
+)
+
+// Client is an AI tool whose MCP configuration Memvra can manage.
+type Client struct {
+	ID      string // short name used on the command line, e.g. "vscode"
+	Name    string // display name, e.g. "VS Code"
+	Path    string // the config file
+	Project bool   // the config belongs to the project, not the user
+	format  configFormat
+}
+
+// Clients returns the MCP clients Memvra knows about, with the config file
+// each one reads. Project-level clients are left out when projectRoot is "".
+func Clients(projectRoot string) ([]Client, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("cannot determine home directory: %w", err)
+		return nil, fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	codexHome := os.Getenv("CODEX_HOME")
+	if codexHome == "" {
+		codexHome = filepath.Join(home, ".codex")
+	}
+
+	clients := []Client{
+		{ID: "claude", Name: "Claude Code", Path: filepath.Join(home, ".claude", "mcp.json"), format: formatMCPServers},
+	}
+	if projectRoot != "" {
+		clients = append(clients,
+			Client{ID: "cursor", Name: "Cursor", Path: filepath.Join(projectRoot, ".cursor", "mcp.json"), Project: true, format: formatMCPServers},
+			Client{ID: "vscode", Name: "VS Code", Path: filepath.Join(projectRoot, ".vscode", "mcp.json"), Project: true, format: formatVSCode},
+		)
+	}
+	clients = append(clients,
+		Client{ID: "windsurf", Name: "Windsurf", Path: filepath.Join(home, ".codeium", "windsurf", "mcp_config.json"), format: formatMCPServers},
+		Client{ID: "zed", Name: "Zed", Path: filepath.Join(home, ".config", "zed", "settings.json"), format: formatZed},
+		Client{ID: "gemini", Name: "Gemini CLI", Path: filepath.Join(home, ".gemini", "settings.json"), format: formatMCPServers},
+		Client{ID: "codex", Name: "Codex", Path: filepath.Join(codexHome, "config.toml"), format: formatCodex},
+	)
+	return clients, nil
+}
+
+// Detected reports whether the client appears to be in use: its config file
+// or the directory holding it exists.
+func (c Client) Detected() bool {
+	_, err := os.Stat(filepath.Dir(c.Path))
+	return err == nil
+}
+
+// Install adds or replaces the Memvra entry in the client's config, keeping
+// everything else in the file.
+func (c Client) Install() error {
+	if c.format == formatCodex {
+		return installCodex(c.Path, defaultEntry)
+	}
+	return updateJSONConfig(c.Path, func(doc *jsoncDoc) ([]byte, bool, error) {
+		entry := c.encodeEntry(defaultEntry)
+		i := doc.root.member(c.section())
+		if i < 0 {
+			out, err := doc.set(doc.root, c.section(), map[string]any{ServerName: entry})
+			return out, true, err
+		}
+		servers, ok := doc.object(doc.root.members[i])
+		if !ok {
+			out, err := doc.set(doc.root, c.section(), map[string]any{ServerName: entry})
+			return out, true, err
+		}
+		out, err := doc.set(servers, ServerName, entry)
+		return out, true, err
+	})
+}
+
+// Uninstall removes the Memvra entry from the client's config. It reports
+// whether there was one. A config left empty is deleted.
+func (c Client) Uninstall() (bool, error) {
+	if _, err := os.Stat(c.Path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if c.format == formatCodex {
+		return uninstallCodex(c.Path)
+	}
+
+	var removed bool
+	err := updateJSONConfig(c.Path, func(doc *jsoncDoc) ([]byte, bool, error) {
+		i := doc.root.member(c.section())
+		if i < 0 {
+			return nil, false, nil
+		}
+		servers, _ := doc.object(doc.root.members[i])
+		j := servers.member(ServerName)
+		if j < 0 {
+			return nil, false, nil
+		}
+		removed = true
+		out := doc.remove(servers, j)
+
+		// Drop the section too if nothing else is left in it.
+		doc, err := parseJSONC(out)
+		if err != nil {
+			return nil, false, err
+		}
+		if i = doc.root.member(c.section()); i >= 0 {
+			if servers, ok := doc.object(doc.root.members[i]); ok && doc.isEmpty(servers) {
+				out = doc.remove(doc.root, i)
+			}
+		}
+		return out, true, nil
+	})
+	return removed, err
+}
+
+// Entry returns the Memvra entry in the client's config and whether there
+// is one. A config that exists but doesn't parse is an error.
+func (c Client) Entry() (ServerEntry, bool, error) {
+	data, err := os.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ServerEntry{}, false, nil
+	}
+	if err != nil {
+		return ServerEntry{}, false, err
+	}
+
+	switch c.format {
+	case formatCodex:
+		var cfg struct {
+			MCPServers map[string]ServerEntry `toml:"mcp_servers"`
+		}
+		if _, err := toml.Decode(string(data), &cfg); err != nil {
+			return ServerEntry{}, false, fmt.Errorf("parse %s: %w", filepath.Base(c.Path), err)
+		}
+		e, ok := cfg.MCPServers[ServerName]
+		return e, ok, nil
+
+	case formatMCPServers:
+		var cfg mcpConfig
+		if err := json.Unmarshal(stripJSONC(data), &cfg); err != nil {
+			return ServerEntry{}, false, fmt.Errorf("parse %s: %w", filepath.Base(c.Path), err)
+		}
+		e, ok := cfg.MCPServers[ServerName]
+		return ServerEntry(e), ok, nil
+
+	default:
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(stripJSONC(data), &doc); err != nil {
+			return ServerEntry{}, false, fmt.Errorf("parse %s: %w", filepath.Base(c.Path), err)
+		}
+		var servers map[string]json.RawMessage
+		if section, ok := doc[c.section()]; ok {
+			if err := json.Unmarshal(section, &servers); err != nil {
+				return ServerEntry{}, false, fmt.Errorf("parse %s: %s: %w", filepath.Base(c.Path), c.section(), err)
+			}
+		}
+		raw, ok := servers[ServerName]
+		if !ok {
+			return ServerEntry{}, false, nil
+		}
+		e, err := decodeEntry(raw)
+		if err != nil {
+			return ServerEntry{}, true, fmt.Errorf("parse %s: %s entry: %w", filepath.Base(c.Path), ServerName, err)
+		}
+		return e, true, nil
+	}
+}
+
+// section is the top-level key holding MCP servers in a JSON config.
+func (c Client) section() string {
+	switch c.format {
+	case formatVSCode:
+		return "servers"
+	case formatZed:
+		return "context_servers"
+	default:
+		return "mcpServers"
+	}
+}
+
+// encodeEntry returns e as the client's JSON config expects it.
+func (c Client) encodeEntry(e ServerEntry) any {
+	switch c.format {
+	case formatVSCode:
+		return struct {
+			Type    string   `json:"type"`
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		}{"stdio", e.Command, e.Args}
+	case formatZed:
+		return struct {
+			Source  string   `json:"source"`
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		}{"custom", e.Command, e.Args}
+	default:
+		return mcpServerEntry{Command: e.Command, Args: e.Args}
 	}
-	path := filepath.Join(home, ".claude", "mcp.json")
-	return installMCPConfig(path)
 }
 
-// InstallCursor registers Memvra as an MCP server in Cursor's
-// project-level config at .cursor/mcp.json.
-func InstallCursor(projectRoot string) error {
-	path := filepath.Join(projectRoot, ".cursor", "mcp.json")
-	return installMCPConfig(path)
+// decodeEntry parses a server entry. Besides {command, args} it accepts the
+// older Zed layout, {"command": {"path": ..., "args": [...]}}.
+func decodeEntry(raw json.RawMessage) (ServerEntry, error) {
+	var e struct {
+		Command json.RawMessage `json:"command"`
+		Args    []string        `json:"args"`
+	}
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return ServerEntry{}, err
+	}
+	var command string
+	if err := json.Unmarshal(e.Command, &command); err == nil {
+		return ServerEntry{Command: command, Args: e.Args}, nil
+	}
+	var nested struct {
+		Path string   `json:"path"`
+		Args []string `json:"args"`
+	}
+	if err := json.Unmarshal(e.Command, &nested); err != nil {
+		return ServerEntry{}, fmt.Errorf("command must be a string")
+	}
+	return ServerEntry{Command: nested.Path, Args: nested.Args}, nil
 }
 
 // installMCPConfig reads an existing MCP config (or creates a new one),
 // merges the memvra server entry, and writes it back.
 func installMCPConfig(path string) error {
-	cfg := mcpConfig{
-		MCPServers: make(map[string]mcpServerEntry),
-	}
+	return Client{Path: path, format: formatMCPServers}.Install()
+}
 
-	// Read existing config if present.
+// updateJSONConfig applies change to the JSON config in path, which may
+// hold comments and trailing commas, and writes the result back if change
+// reports a modification. change edits the text, so everything outside
+// Memvra's entry stays as it was. A missing or blank file starts out as {};
+// one that doesn't parse is left alone, and one left empty is deleted.
+func updateJSONConfig(path string, change func(doc *jsoncDoc) ([]byte, bool, error)) error {
 	data, err := os.ReadFile(path)
-	if err == nil {
-		_ = json.Unmarshal(data, &cfg)
-		if cfg.MCPServers == nil {
-			cfg.MCPServers = make(map[string]mcpServerEntry)
-		}
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	}
+	if isBlank(data) {
+		data = []byte("{}\n")
+	}
+	doc, err := parseJSONC(data)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w (edit it by hand)", path, err)
 	}
 
-	// Add/update memvra entry.
-	cfg.MCPServers["memvra"] = mcpServerEntry{
-		Command: "memvra",
-		Args:    []string{"mcp"},
+	out, changed, err := change(doc)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", path, err)
+	}
+	if !changed {
+		return nil
+	}
+	if doc, err := parseJSONC(out); err == nil && doc.isEmpty(doc.root) && isBlank(out[doc.root.close+1:]) && isBlank(out[:doc.root.open]) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
 	}
 
 	// Ensure directory exists.
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return fmt.Errorf("create directory: %w", err)
 	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
 
-	out, err := json.MarshalIndent(cfg, "", "  ")
+	return nil
+}
+
+// installCodex writes e as the [mcp_servers.memvra] table of a Codex
+// config. The file is edited as text so comments and formatting elsewhere
+// survive.
+func installCodex(path string, e ServerEntry) error {
+	rest, _, err := readCodexWithout(path)
 	if err != nil {
-		return fmt.Errorf("marshal config: %w", err)
+		return err
 	}
 
-	if err := os.WriteFile(path, out, 0o644); err != nil {
-		return fmt.Errorf("write config: %w", err)
+	var sb strings.Builder
+	if rest != "" {
+		sb.WriteString(rest)
+		sb.WriteString("\n\n")
+	}
+	fmt.Fprintf(&sb, "[mcp_servers.%s]\n", ServerName)
+	table := struct {
+		Command string   `toml:"command"`
+		Args    []string `toml:"args"`
+	}{e.Command, e.Args}
+	if err := toml.NewEncoder(&sb).Encode(table); err != nil {
+		return fmt.Errorf("encode config: %w", err)
 	}
 
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
 	return nil
 }
+
+// uninstallCodex removes the [mcp_servers.memvra] tables from a Codex config.
+func uninstallCodex(path string) (bool, error) {
+	rest, removed, err := readCodexWithout(path)
+	if err != nil || !removed {
+		return false, err
+	}
+	if rest == "" {
+		return true, os.Remove(path)
+	}
+	return true, os.WriteFile(path, []byte(rest+"\n"), 0o644)
+}
+
+// readCodexWithout returns the Codex config at path with Memvra's tables
+// cut out, and whether there were any. The config must parse, so that a
+// broken file is never made worse.
+func readCodexWithout(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	var probe map[string]any
+	if _, err := toml.Decode(string(data), &probe); err != nil {
+		return "", false, fmt.Errorf("parse %s: %w (edit it by hand)", path, err)
+	}
+
+	var kept []string
+	skipping, removed := false, false
+	for _, line := range strings.Split(string(data), "\n") {
+		if header, ok := tomlHeader(line); ok {
+			skipping = header == "mcp_servers."+ServerName || strings.HasPrefix(header, "mcp_servers."+ServerName+".")
+			removed = removed || skipping
+		}
+		if !skipping {
+			kept = append(kept, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), removed, nil
+}
+
+// tomlHeader returns the table name of a [table] or [[array]] header line,
+// with spaces and quotes around key parts removed.
+func tomlHeader(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	name := strings.Trim(line, "[]")
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, "."), true
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_JSONFormats_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	for _, c := range []Client{
+		{Name: "VS Code", Path: filepath.Join(dir, "vscode.json"), format: formatVSCode},
+		{Name: "Zed", Path: filepath.Join(dir, "zed.json"), format: formatZed},
+		{Name: "Gemini CLI", Path: filepath.Join(dir, "gemini.json"), format: formatMCPServers},
+	} {
+		os.WriteFile(c.Path, []byte(`{"theme": "One Dark", "`+c.section()+`": {"other": {"command": "other"}}}`), 0o644)
+
+		if err := c.Install(); err != nil {
+			t.Fatalf("%s: install: %v", c.Name, err)
+		}
+		e, found, err := c.Entry()
+		if err != nil || !found || e.Command != "memvra" || len(e.Args) != 1 || e.Args[0] != "mcp" {
+			t.Errorf("%s: entry after install: %+v %v %v", c.Name, e, found, err)
+		}
+
+		removed, err := c.Uninstall()
+		if err != nil || !removed {
+			t.Fatalf("%s: uninstall: %v %v", c.Name, removed, err)
+		}
+		data, _ := os.ReadFile(c.Path)
+		if !strings.Contains(string(data), "One Dark") || !strings.Contains(string(data), `"other"`) {
+			t.Errorf("%s: other settings lost:\n%s", c.Name, data)
+		}
+		if _, found, _ := c.Entry(); found {
+			t.Errorf("%s: entry still present after uninstall", c.Name)
+		}
+		if removed, _ := c.Uninstall(); removed {
+			t.Errorf("%s: second uninstall should find nothing", c.Name)
+		}
+	}
+}
+
+func TestClient_VSCodeEntryHasType(t *testing.T) {
+	c := Client{Path: filepath.Join(t.TempDir(), ".vscode", "mcp.json"), format: formatVSCode}
+	if err := c.Install(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(c.Path)
+	if !strings.Contains(string(data), `"servers"`) || !strings.Contains(string(data), `"type": "stdio"`) {
+		t.Errorf("unexpected VS Code config:\n%s", data)
+	}
+}
+
+func TestClient_UninstallDeletesEmptyConfig(t *testing.T) {
+	c := Client{Path: filepath.Join(t.TempDir(), "mcp.json"), format: formatMCPServers}
+	c.Install()
+	if removed, err := c.Uninstall(); err != nil || !removed {
+		t.Fatalf("uninstall: %v %v", removed, err)
+	}
+	if _, err := os.Stat(c.Path); !os.IsNotExist(err) {
+		t.Error("a config left empty should be deleted")
+	}
+}
+
+func TestClient_KeepsCommentsAndFormatting(t *testing.T) {
+	c := Client{Path: filepath.Join(t.TempDir(), "settings.json"), format: formatZed}
+	original := `// Zed settings
+{
+    "theme": "One Dark", // dark mode
+    "ui_font_size": 16.0,
+    /* fonts */
+    "buffer_font_family": "Zed Mono",
+    "context_servers": {
+        "other": { "command": "other" },
+    },
+}
+`
+	os.WriteFile(c.Path, []byte(original), 0o644)
+
+	if err := c.Install(); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	e, found, err := c.Entry()
+	if err != nil || !found || e.Command != "memvra" {
+		t.Fatalf("entry after install: %+v %v %v", e, found, err)
+	}
+	data, _ := os.ReadFile(c.Path)
+	want := `// Zed settings
+{
+    "theme": "One Dark", // dark mode
+    "ui_font_size": 16.0,
+    /* fonts */
+    "buffer_font_family": "Zed Mono",
+    "context_servers": {
+        "other": { "command": "other" },
+        "memvra": {
+            "source": "custom",
+            "command": "memvra",
+            "args": [
+                "mcp"
+            ]
+        },
+    },
+}
+`
+	if string(data) != want {
+		t.Errorf("after install:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Installing again replaces the entry in place.
+	if err := c.Install(); err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+	if data, _ := os.ReadFile(c.Path); string(data) != want {
+		t.Errorf("after reinstall:\n%s", data)
+	}
+
+	if removed, err := c.Uninstall(); err != nil || !removed {
+		t.Fatalf("uninstall: %v %v", removed, err)
+	}
+	if data, _ := os.ReadFile(c.Path); string(data) != original {
+		t.Errorf("after uninstall:\n%s\nwant:\n%s", data, original)
+	}
+}
+
+func TestClient_AddsSectionToSettings(t *testing.T) {
+	c := Client{Path: filepath.Join(t.TempDir(), "settings.json"), format: formatZed}
+	original := "{\n  \"vim_mode\": true // keep\n}\n"
+	os.WriteFile(c.Path, []byte(original), 0o644)
+
+	if err := c.Install(); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	data, _ := os.ReadFile(c.Path)
+	if !strings.HasPrefix(string(data), "{\n  \"vim_mode\": true, // keep\n  \"context_servers\": {\n    \"memvra\": {") {
+		t.Errorf("after install:\n%s", data)
+	}
+	if removed, err := c.Uninstall(); err != nil || !removed {
+		t.Fatalf("uninstall: %v %v", removed, err)
+	}
+	if data, _ := os.ReadFile(c.Path); string(data) != "{\n  \"vim_mode\": true // keep\n}\n" {
+		t.Errorf("after uninstall:\n%s", data)
+	}
+}
+
+func TestClient_RefusesUnparsableJSON(t *testing.T) {
+	c := Client{Path: filepath.Join(t.TempDir(), "settings.json"), format: formatZed}
+	original := "{\n  \"theme\": \"One Dark\"\n  \"vim_mode\": true\n}\n"
+	os.WriteFile(c.Path, []byte(original), 0o644)
+
+	if err := c.Install(); err == nil {
+		t.Error("expected an error for a broken settings file")
+	}
+	if _, _, err := c.Entry(); err == nil {
+		t.Error("expected Entry to report the parse error")
+	}
+	if data, _ := os.ReadFile(c.Path); string(data) != original {
+		t.Errorf("file was modified:\n%s", data)
+	}
+}
+
+func TestClient_Codex(t *testing.T) {
+	c := Client{Path: filepath.Join(t.TempDir(), "config.toml"), format: formatCodex}
+	os.WriteFile(c.Path, []byte(`# my settings
+model = "o3"
+
+[mcp_servers.memvra]
+command = "old-memvra"
+
+[mcp_servers.memvra.env]
+FOO = "bar"
+
+[mcp_servers.other]
+command = "other"
+`), 0o644)
+
+	if err := c.Install(); err != nil {
+		t.Fatal(err)
+	}
+	e, found, err := c.Entry()
+	if err != nil || !found || e.Command != "memvra" {
+		t.Fatalf("entry after install: %+v %v %v", e, found, err)
+	}
+	data, _ := os.ReadFile(c.Path)
+	text := string(data)
+	if strings.Count(text, "[mcp_servers.memvra]") != 1 || strings.Contains(text, "FOO") || strings.Contains(text, "old-memvra") {
+		t.Errorf("old entry not replaced:\n%s", text)
+	}
+	if !strings.Contains(text, "# my settings") || !strings.Contains(text, "[mcp_servers.other]") {
+		t.Errorf("other settings lost:\n%s", text)
+	}
+
+	if removed, err := c.Uninstall(); err != nil || !removed {
+		t.Fatalf("uninstall: %v %v", removed, err)
+	}
+	data, _ = os.ReadFile(c.Path)
+	if strings.Contains(string(data), "memvra") || !strings.Contains(string(data), `model = "o3"`) {
+		t.Errorf("after uninstall:\n%s", data)
+	}
+}
@@ -0,0 +1,332 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Editors keep their settings in JSON with comments and trailing commas
+// (Zed's settings.json among them). Client configs are therefore edited as
+// text: the file is parsed only to find the section Memvra owns, and that
+// section is spliced in or cut out, leaving every other byte as it was.
+
+// jsonMember is a member of a JSON object, located by byte offsets.
+type jsonMember struct {
+	key        string
+	start      int // the key's opening quote
+	valueStart int
+	valueEnd   int // just past the value
+}
+
+// jsonObject is an object located by byte offsets.
+type jsonObject struct {
+	open, close int // the braces
+	members     []jsonMember
+}
+
+// member returns the index of the last member named key, or -1. The last
+// one wins, as it does when the file is decoded.
+func (o jsonObject) member(key string) int {
+	for i := len(o.members) - 1; i >= 0; i-- {
+		if o.members[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// jsoncDoc is a JSON-with-comments document and a copy of it with comments
+// and trailing commas blanked out, which is plain JSON at the same offsets.
+type jsoncDoc struct {
+	data  []byte
+	plain []byte
+	root  jsonObject
+}
+
+// parseJSONC parses data, whose top level must be an object.
+func parseJSONC(data []byte) (*jsoncDoc, error) {
+	plain := stripJSONC(data)
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(plain, &probe); err != nil {
+		return nil, err
+	}
+	root, ok := parseObject(plain, 0)
+	if !ok {
+		return nil, fmt.Errorf("top level is not an object")
+	}
+	return &jsoncDoc{data: data, plain: plain, root: root}, nil
+}
+
+// object returns the object that is the value of m, if it is one.
+func (d *jsoncDoc) object(m jsonMember) (jsonObject, bool) {
+	return parseObject(d.plain, m.valueStart)
+}
+
+// indentUnit is the indentation of the root object's members, or two
+// spaces when that can't be told.
+func (d *jsoncDoc) indentUnit() string {
+	if len(d.root.members) > 0 {
+		if indent := lineIndent(d.data, d.root.members[0].start); indent != "" {
+			return indent
+		}
+	}
+	return "  "
+}
+
+// memberIndent is the indentation for members of o.
+func (d *jsoncDoc) memberIndent(o jsonObject) string {
+	for _, m := range o.members {
+		if lineStart(d.data, m.start) > o.open {
+			return lineIndent(d.data, m.start)
+		}
+	}
+	return lineIndent(d.data, o.open) + d.indentUnit()
+}
+
+// set returns the document with o's member key set to value: the existing
+// value is replaced, or a new member is added after the others.
+func (d *jsoncDoc) set(o jsonObject, key string, value any) ([]byte, error) {
+	indent := d.memberIndent(o)
+	encoded, err := json.MarshalIndent(value, indent, d.indentUnit())
+	if err != nil {
+		return nil, err
+	}
+	if i := o.member(key); i >= 0 {
+		m := o.members[i]
+		return splice(d.data, m.valueStart, m.valueEnd, string(encoded)), nil
+	}
+
+	name, _ := json.Marshal(key)
+	text := string(name) + ": " + string(encoded)
+	if len(o.members) == 0 {
+		if isBlank(d.data[o.open+1 : o.close]) {
+			return splice(d.data, o.open+1, o.close, "\n"+indent+text+"\n"+lineIndent(d.data, o.open)), nil
+		}
+		return d.insertBeforeClose(o, indent, text, false), nil
+	}
+
+	last := o.members[len(o.members)-1]
+	comma := skipJSONCSpace(d.data, last.valueEnd)
+	trailing := comma < o.close && d.data[comma] == ','
+	out := d.insertBeforeClose(o, indent, text, trailing)
+	if !trailing {
+		out = splice(out, last.valueEnd, last.valueEnd, ",")
+	}
+	return out, nil
+}
+
+// insertBeforeClose adds the member text to the end of o: on a line of its
+// own when o's closing brace starts a line, otherwise just before the brace.
+// With trailing, the text keeps the trailing comma style of the object.
+func (d *jsoncDoc) insertBeforeClose(o jsonObject, indent, text string, trailing bool) []byte {
+	if trailing {
+		text += ","
+	}
+	ls := lineStart(d.data, o.close)
+	if ls > o.open && isBlank(d.data[ls:o.close]) {
+		return splice(d.data, ls, ls, indent+text+"\n")
+	}
+	return splice(d.data, o.close, o.close, "\n"+indent+text+"\n"+lineIndent(d.data, o.close))
+}
+
+// remove returns the document without o's member at index i, along with
+// its line when the member had the line to itself.
+func (d *jsoncDoc) remove(o jsonObject, i int) []byte {
+	m := o.members[i]
+	start, end := m.start, m.valueEnd
+	prevComma := -1
+	if j := skipJSONCSpace(d.data, end); j < o.close && d.data[j] == ',' {
+		end = j + 1
+	} else if i > 0 {
+		prevComma = skipJSONCSpace(d.data, o.members[i-1].valueEnd)
+	}
+
+	if ls := lineStart(d.data, start); isBlank(d.data[ls:start]) {
+		if le := bytes.IndexByte(d.data[end:], '\n'); le >= 0 && isBlank(d.data[end:end+le]) {
+			start, end = ls, end+le+1
+		}
+	}
+	out := splice(d.data, start, end, "")
+	if prevComma >= 0 {
+		out = splice(out, prevComma, prevComma+1, "")
+	}
+	return out
+}
+
+// isEmpty reports whether o has no members and nothing but whitespace
+// between its braces.
+func (d *jsoncDoc) isEmpty(o jsonObject) bool {
+	return len(o.members) == 0 && isBlank(d.data[o.open+1:o.close])
+}
+
+// stripJSONC returns a copy of data with comments and trailing commas
+// replaced by spaces. Newlines are kept, so offsets and lines still match.
+func stripJSONC(data []byte) []byte {
+	out := append([]byte(nil), data...)
+	lastComma := -1 // a comma not yet followed by a value
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			i = skipString(out, i) - 1
+			lastComma = -1
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			stop := len(out)
+			if end := bytes.Index(out[i+2:], []byte("*/")); end >= 0 {
+				stop = i + 2 + end + 2
+			}
+			for ; i < stop; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case isSpace(c):
+		default:
+			lastComma = -1
+		}
+	}
+	return out
+}
+
+// parseObject locates the object starting at the first non-space byte at
+// or after i in plain, which must be valid JSON.
+func parseObject(plain []byte, i int) (jsonObject, bool) {
+	i = skipSpace(plain, i)
+	if i >= len(plain) || plain[i] != '{' {
+		return jsonObject{}, false
+	}
+	o := jsonObject{open: i}
+	for i = skipSpace(plain, i+1); plain[i] != '}'; i = skipSpace(plain, i) {
+		if plain[i] == ',' {
+			i = skipSpace(plain, i+1)
+		}
+		keyEnd := skipString(plain, i)
+		var key string
+		_ = json.Unmarshal(plain[i:keyEnd], &key)
+		valueStart := skipSpace(plain, skipSpace(plain, keyEnd)+1)
+		valueEnd := skipValue(plain, valueStart)
+		o.members = append(o.members, jsonMember{key: key, start: i, valueStart: valueStart, valueEnd: valueEnd})
+		i = valueEnd
+	}
+	o.close = i
+	return o, true
+}
+
+// skipValue returns the offset just past the JSON value at i.
+func skipValue(plain []byte, i int) int {
+	switch plain[i] {
+	case '"':
+		return skipString(plain, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(plain); i++ {
+			switch plain[i] {
+			case '"':
+				i = skipString(plain, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	default:
+		for i < len(plain) && !isSpace(plain[i]) && plain[i] != ',' && plain[i] != '}' && plain[i] != ']' {
+			i++
+		}
+		return i
+	}
+}
+
+// skipString returns the offset just past the string whose opening quote
+// is at i.
+func skipString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// skipJSONCSpace returns the offset of the first byte at or after i that
+// is neither whitespace nor part of a comment.
+func skipJSONCSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case isSpace(data[i]):
+			i++
+		case bytes.HasPrefix(data[i:], []byte("//")):
+			if nl := bytes.IndexByte(data[i:], '\n'); nl >= 0 {
+				i += nl
+			} else {
+				i = len(data)
+			}
+		case bytes.HasPrefix(data[i:], []byte("/*")):
+			if end := bytes.Index(data[i+2:], []byte("*/")); end >= 0 {
+				i += 2 + end + 2
+			} else {
+				i = len(data)
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && isSpace(data[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isBlank(b []byte) bool {
+	return len(bytes.TrimSpace(b)) == 0
+}
+
+// lineStart returns the offset of the start of the line holding offset i.
+func lineStart(data []byte, i int) int {
+	return bytes.LastIndexByte(data[:i], '\n') + 1
+}
+
+// lineIndent returns the spaces and tabs that begin the line holding
+// offset i.
+func lineIndent(data []byte, i int) string {
+	start := lineStart(data, i)
+	end := start
+	for end < i && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
+
+// splice returns data with the bytes from start to end replaced by text.
+func splice(data []byte, start, end int, text string) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(text))
+	out = append(out, data[:start]...)
+	out = append(out, text...)
+	return append(out, data[end:]...)
+}
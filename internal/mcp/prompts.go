@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/memvra/memvra/internal/memory"
+)
+
+// registerPrompts adds Memvra's prompts to the MCP server. Each one is
+// built from the same project context `memvra ask` injects.
+func (s *Server) registerPrompts(mcpServer *server.MCPServer) {
+	mcpServer.AddPrompt(mcp.NewPrompt("resume-session",
+		mcp.WithPromptDescription("Pick up where the last AI session left off, with the project context loaded"),
+		mcp.WithArgument("focus",
+			mcp.ArgumentDescription("What to concentrate on; defaults to the last session's task"),
+		),
+	), s.promptResumeSession)
+
+	mcpServer.AddPrompt(mcp.NewPrompt("review-against-constraints",
+		mcp.WithPromptDescription("Review a change against the project's constraints, conventions and decisions"),
+		mcp.WithArgument("change",
+			mcp.ArgumentDescription("The diff, or a description of the change, to review"),
+			mcp.RequiredArgument(),
+		),
+	), s.promptReviewAgainstConstraints)
+}
+
+func (s *Server) promptResumeSession(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	sessions, err := s.store.GetLastNSessions(1)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	focus := strings.TrimSpace(req.Params.Arguments["focus"])
+	if focus == "" && len(sessions) > 0 {
+		focus = sessions[0].Question
+	}
+
+	built, err := s.buildContext(ctx, focus, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("build context: %w", err)
+	}
+
+	var sb strings.Builder
+	writeBuiltContext(&sb, built.SystemPrompt, built.ContextText)
+	if len(sessions) == 0 {
+		sb.WriteString("No previous session is recorded. Summarize the project context above and ask what to work on.\n")
+	} else {
+		last := sessions[0]
+		fmt.Fprintf(&sb, "Resume the work from the last session (%s, %s).\n\nTask: %s\n",
+			last.ModelUsed, last.CreatedAt.Format("2006-01-02 15:04"), last.Question)
+		if last.ResponseSummary != "" {
+			fmt.Fprintf(&sb, "\nWhere it stopped:\n%s\n", last.ResponseSummary)
+		}
+		if f := req.Params.Arguments["focus"]; f != "" {
+			fmt.Fprintf(&sb, "\nFocus on: %s\n", f)
+		}
+		sb.WriteString("\nBriefly restate where things stand, then continue with the next step. Call memvra_save_progress before you stop.\n")
+	}
+
+	return mcp.NewGetPromptResult("Resume the last session",
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(sb.String()))}), nil
+}
+
+func (s *Server) promptReviewAgainstConstraints(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	change := strings.TrimSpace(req.Params.Arguments["change"])
+	if change == "" {
+		return nil, fmt.Errorf("missing required argument: change")
+	}
+
+	// The rules are listed in full: the context below is trimmed to a
+	// token budget and might leave some out.
+	var rules []memory.Memory
+	for _, t := range []memory.MemoryType{memory.TypeConstraint, memory.TypeConvention, memory.TypeDecision} {
+		memories, err := s.store.ListMemories(t)
+		if err != nil {
+			return nil, fmt.Errorf("list memories: %w", err)
+		}
+		rules = append(rules, memories...)
+	}
+
+	built, err := s.buildContext(ctx, change, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("build context: %w", err)
+	}
+
+	var sb strings.Builder
+	writeBuiltContext(&sb, built.SystemPrompt, built.ContextText)
+	if len(rules) == 0 {
+		sb.WriteString("No constraints, conventions or decisions are recorded for this project; review the change against the context above.\n\n")
+	} else {
+		sb.WriteString("Rules to check:\n")
+		for i, m := range rules {
+			fmt.Fprintf(&sb, "%d. [%s] %s", i+1, m.MemoryType, m.Content)
+			if m.Scope != "" {
+				fmt.Fprintf(&sb, " (applies to %s/)", m.Scope)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Review the following change against these rules. For every violation, name the rule by number, quote the offending code and suggest a fix. Say so plainly if the change breaks none of them.\n\n")
+	fmt.Fprintf(&sb, "```\n%s\n```\n", change)
+
+	return mcp.NewGetPromptResult("Review a change against the project's rules",
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(sb.String()))}), nil
+}
+
+// writeBuiltContext writes a built context ahead of a prompt's instructions.
+func writeBuiltContext(sb *strings.Builder, systemPrompt, contextText string) {
+	if systemPrompt != "" {
+		sb.WriteString(systemPrompt)
+		sb.WriteString("\n\n")
+	}
+	if contextText != "" {
+		sb.WriteString(contextText)
+		sb.WriteString("\n\n")
+	}
+}
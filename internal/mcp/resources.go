@@ -0,0 +1,210 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/memvra/memvra/internal/memory"
+	"github.com/memvra/memvra/internal/scanner"
+)
+
+// Resource URIs. Clients that support resources can attach these to a
+// conversation without a tool round trip.
+const (
+	uriMemoriesPrefix = "memvra://memories/"
+	uriSessionsRecent = "memvra://sessions/recent"
+	uriProfile        = "memvra://project/profile"
+	uriFilePrefix     = "memvra://file/"
+	uriChunksSuffix   = "/chunks"
+
+	// recentSessions is how many sessions memvra://sessions/recent lists.
+	recentSessions = 10
+)
+
+// memoryTypes lists the memory types in the order resources present them.
+var memoryTypes = []memory.MemoryType{
+	memory.TypeDecision, memory.TypeConvention, memory.TypeConstraint, memory.TypeNote, memory.TypeTodo,
+}
+
+// registerResources adds Memvra's resources and resource templates to the
+// MCP server.
+func (s *Server) registerResources(mcpServer *server.MCPServer) {
+	mcpServer.AddResource(mcp.NewResource(uriSessionsRecent, "Recent sessions",
+		mcp.WithResourceDescription("What the last AI sessions worked on, oldest first"),
+		mcp.WithMIMEType("text/markdown"),
+	), s.readRecentSessions)
+	mcpServer.AddResource(mcp.NewResource(uriProfile, "Project profile",
+		mcp.WithResourceDescription("The project's name, tech stack and index size"),
+		mcp.WithMIMEType("text/markdown"),
+	), s.readProfile)
+
+	for _, t := range memoryTypes {
+		mcpServer.AddResource(mcp.NewResource(memoriesURI(t), fmt.Sprintf("Memories: %s", t),
+			mcp.WithResourceDescription(fmt.Sprintf("Active %s memories of this project", t)),
+			mcp.WithMIMEType("text/markdown"),
+		), s.readMemories)
+	}
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(uriMemoriesPrefix+"{type}", "Memories by type",
+		mcp.WithTemplateDescription("Active memories of one type: decision, convention, constraint, note or todo"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	), s.readMemories)
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(uriFilePrefix+"{+path}"+uriChunksSuffix, "Indexed file chunks",
+		mcp.WithTemplateDescription("The indexed chunks of a project file, with line ranges and symbols"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	), s.readFileChunks)
+}
+
+// memoriesURI returns the resource URI of the memories of type t.
+func memoriesURI(t memory.MemoryType) string {
+	return uriMemoriesPrefix + string(t)
+}
+
+// notifyResourcesUpdated tells the clients subscribed to the resources at
+// uris that they changed.
+func (s *Server) notifyResourcesUpdated(uris ...string) {
+	if s.mcpServer == nil {
+		return
+	}
+	for _, uri := range uris {
+		for _, id := range s.subs.subscribers(uri) {
+			_ = s.mcpServer.SendNotificationToSpecificClient(id,
+				string(mcp.MethodNotificationResourceUpdated), map[string]any{"uri": uri})
+		}
+	}
+}
+
+// notifyMemoriesChanged reports a change to memories of the given types.
+func (s *Server) notifyMemoriesChanged(types ...memory.MemoryType) {
+	seen := make(map[memory.MemoryType]bool)
+	var uris []string
+	for _, t := range types {
+		if t != "" && !seen[t] {
+			seen[t] = true
+			uris = append(uris, memoriesURI(t))
+		}
+	}
+	s.notifyResourcesUpdated(uris...)
+}
+
+func (s *Server) readMemories(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	t := memory.MemoryType(strings.TrimPrefix(req.Params.URI, uriMemoriesPrefix))
+	if !memory.ValidMemoryType(t) {
+		return nil, fmt.Errorf("unknown memory type %q (valid: decision, convention, constraint, note, todo)", t)
+	}
+	memories, err := s.store.ListMemories(t)
+	if err != nil {
+		return nil, fmt.Errorf("list memories: %w", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s memories\n\n", strings.ToUpper(string(t[:1]))+string(t[1:]))
+	if len(memories) == 0 {
+		sb.WriteString("None stored.\n")
+	}
+	for _, m := range memories {
+		fmt.Fprintf(&sb, "- %s\n  id: %s | source: %s | importance: %.2f", m.Content, m.ID, m.Source, m.Importance)
+		if len(m.Tags) > 0 {
+			fmt.Fprintf(&sb, " | tags: %s", strings.Join(m.Tags, ", "))
+		}
+		if m.Scope != "" {
+			fmt.Fprintf(&sb, " | scope: %s/", m.Scope)
+		}
+		if len(m.RelatedFiles) > 0 {
+			fmt.Fprintf(&sb, " | files: %s", strings.Join(m.RelatedFiles, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return markdownContents(req.Params.URI, sb.String()), nil
+}
+
+func (s *Server) readRecentSessions(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	sessions, err := s.store.GetLastNSessions(recentSessions)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Recent sessions\n\n")
+	if len(sessions) == 0 {
+		sb.WriteString("No sessions recorded.\n")
+	}
+	// Newest-first from the DB; oldest-first reads as a story.
+	for i := len(sessions) - 1; i >= 0; i-- {
+		sess := sessions[i]
+		fmt.Fprintf(&sb, "## %s (%s)\n\n%s\n", sess.CreatedAt.Format("2006-01-02 15:04"), sess.ModelUsed, sess.Question)
+		if sess.ResponseSummary != "" {
+			fmt.Fprintf(&sb, "\n%s\n", sess.ResponseSummary)
+		}
+		sb.WriteString("\n")
+	}
+	return markdownContents(req.Params.URI, sb.String()), nil
+}
+
+func (s *Server) readProfile(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	proj, err := s.store.GetProject()
+	if err != nil {
+		return nil, err
+	}
+	ts, _ := scanner.TechStackFromJSON(proj.TechStack)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", proj.Name)
+	for _, row := range [][2]string{
+		{"Language", ts.Language},
+		{"Framework", ts.Framework},
+		{"Database", ts.Database},
+		{"Frontend", ts.Frontend},
+		{"Tests", ts.TestFramework},
+		{"Architecture", ts.Architecture},
+		{"CI", ts.CI},
+		{"Entry points", strings.Join(ts.EntryPoints, ", ")},
+		{"Patterns", strings.Join(ts.DetectedPatterns, ", ")},
+	} {
+		if row[1] != "" {
+			fmt.Fprintf(&sb, "- %s: %s\n", row[0], row[1])
+		}
+	}
+	fmt.Fprintf(&sb, "- Indexed: %d files, %d chunks\n", proj.FileCount, proj.ChunkCount)
+	if proj.EmbeddingModel != "" {
+		fmt.Fprintf(&sb, "- Embedder: %s (%d dims)\n", proj.EmbeddingModel, proj.EmbeddingDimension)
+	}
+	return markdownContents(req.Params.URI, sb.String()), nil
+}
+
+func (s *Server) readFileChunks(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	raw := strings.TrimSuffix(strings.TrimPrefix(req.Params.URI, uriFilePrefix), uriChunksSuffix)
+	path, err := url.PathUnescape(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file path %q: %w", raw, err)
+	}
+	path = memory.NormalizeFilePath(s.root, path)
+
+	f, err := s.store.GetFileByPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("file %s is not indexed", path)
+	}
+	chunks, err := s.store.ListChunksByFileID(f.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list chunks: %w", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", path)
+	for _, c := range chunks {
+		fmt.Fprintf(&sb, "## Lines %d-%d", c.StartLine, c.EndLine)
+		if c.Symbol != "" {
+			fmt.Fprintf(&sb, " — %s %s", c.SymbolKind, c.Symbol)
+		}
+		fmt.Fprintf(&sb, "\n\n```%s\n%s\n```\n\n", f.Language, strings.TrimRight(c.Content, "\n"))
+	}
+	return markdownContents(req.Params.URI, sb.String()), nil
+}
+
+func markdownContents(uri, text string) []mcp.ResourceContents {
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: "text/markdown", Text: text}}
+}
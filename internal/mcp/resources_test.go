@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/memvra/memvra/internal/memory"
+)
+
+// readResource sends a resources/read request through a real MCP server,
+// so URI template matching is exercised too, and returns the text.
+func readResource(t *testing.T, srv *Server, uri string) (string, bool) {
+	t.Helper()
+	mcpServer := server.NewMCPServer("memvra", "test", server.WithResourceCapabilities(true, false))
+	srv.registerResources(mcpServer)
+
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "resources/read",
+		"params": map[string]any{"uri": uri},
+	})
+	resp := mcpServer.HandleMessage(context.Background(), msg)
+	out, _ := json.Marshal(resp)
+
+	var decoded struct {
+		Result *struct {
+			Contents []struct{ Text string } `json:"contents"`
+		} `json:"result"`
+		Error *struct{ Message string } `json:"error"`
+	}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("decode response: %v\n%s", err, out)
+	}
+	if decoded.Error != nil || decoded.Result == nil {
+		return string(out), false
+	}
+	var text strings.Builder
+	for _, c := range decoded.Result.Contents {
+		text.WriteString(c.Text)
+	}
+	return text.String(), true
+}
+
+func TestResource_MemoriesByType(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.store.InsertMemory(memory.Memory{Content: "Never log access tokens", MemoryType: memory.TypeConstraint, Source: "user", Importance: 0.8})
+	srv.store.InsertMemory(memory.Memory{Content: "Use pgx for Postgres", MemoryType: memory.TypeDecision, Source: "user", Importance: 0.8})
+
+	text, ok := readResource(t, srv, "memvra://memories/constraint")
+	if !ok {
+		t.Fatalf("read failed: %s", text)
+	}
+	if !strings.Contains(text, "Never log access tokens") || strings.Contains(text, "pgx") {
+		t.Errorf("unexpected constraint memories:\n%s", text)
+	}
+
+	if text, ok := readResource(t, srv, "memvra://memories/bogus"); ok {
+		t.Errorf("expected an error for an unknown type, got:\n%s", text)
+	}
+}
+
+func TestResource_RecentSessionsAndProfile(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.store.InsertSession(memory.Session{Question: "add refresh tokens", ResponseSummary: "half done", ModelUsed: "claude"})
+
+	text, ok := readResource(t, srv, uriSessionsRecent)
+	if !ok || !strings.Contains(text, "add refresh tokens") || !strings.Contains(text, "half done") {
+		t.Errorf("recent sessions:\n%s", text)
+	}
+
+	text, ok = readResource(t, srv, uriProfile)
+	if !ok || !strings.Contains(text, "testproject") || !strings.Contains(text, "Gin") {
+		t.Errorf("profile:\n%s", text)
+	}
+}
+
+func TestResource_FileChunks(t *testing.T) {
+	srv := setupTestServer(t)
+	fileID, err := srv.store.UpsertFile(memory.File{Path: "internal/auth/jwt.go", Language: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.store.InsertChunk(memory.Chunk{FileID: fileID, Content: "func Sign() {}", StartLine: 10, EndLine: 12, ChunkType: "code", Symbol: "Sign", SymbolKind: "func"})
+
+	text, ok := readResource(t, srv, "memvra://file/internal/auth/jwt.go/chunks")
+	if !ok {
+		t.Fatalf("read failed: %s", text)
+	}
+	if !strings.Contains(text, "Lines 10-12") || !strings.Contains(text, "func Sign() {}") {
+		t.Errorf("unexpected chunks:\n%s", text)
+	}
+
+	if text, ok := readResource(t, srv, "memvra://file/missing.go/chunks"); ok {
+		t.Errorf("expected an error for an unindexed file, got:\n%s", text)
+	}
+}
+
+func TestPrompt_ReviewRequiresChange(t *testing.T) {
+	srv := setupTestServer(t)
+	req := mcplib.GetPromptRequest{}
+	req.Params.Name = "review-against-constraints"
+	if _, err := srv.promptReviewAgainstConstraints(context.Background(), req); err == nil {
+		t.Fatal("expected an error without a change")
+	}
+}
+
+func TestNotifyMemoriesChanged_WithoutServer(t *testing.T) {
+	srv := setupTestServer(t)
+	// Handlers called outside Run have no MCP server to notify.
+	srv.notifyMemoriesChanged(memory.TypeDecision, "")
+}
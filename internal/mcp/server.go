@@ -1,15 +1,21 @@
 // Package mcp implements a Model Context Protocol server that exposes
-// Memvra's memory and context capabilities as MCP tools. AI coding
-// assistants (Claude Code, Cursor, Windsurf) can call these tools
-// automatically to save progress, store decisions, and retrieve context.
+// Memvra's memory and context capabilities as MCP tools, resources and
+// prompts. AI coding assistants (Claude Code, Cursor, Windsurf) can call
+// these tools automatically to save progress, store decisions, and retrieve
+// context, or attach the resources without a tool call.
 package mcp
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/memvra/memvra/internal/config"
 	"github.com/memvra/memvra/internal/db"
+	"github.com/memvra/memvra/internal/export"
 	"github.com/memvra/memvra/internal/memory"
 )
 
@@ -19,10 +25,19 @@ type Server struct {
 	database *db.DB
 	store    *memory.Store
 	vectors  *memory.VectorStore
+
+	mcpServer *server.MCPServer // set by newMCPServer; receives resource notifications
+	subs      *subscriptions    // set by newMCPServer; who to notify
+
+	// writeMu serializes the tools that change memories or sessions. Tool
+	// calls run concurrently, and a write is several statements (duplicate
+	// check, insert, embed, export) that must not interleave.
+	writeMu sync.Mutex
 }
 
 // NewServer opens the Memvra database at the given project root and prepares
-// an MCP server. Call Run() to start serving over stdio.
+// an MCP server. Call Run() to start serving over stdio, or pass the root to
+// ServeHTTP instead.
 func NewServer(root string) (*Server, error) {
 	dbPath := config.ProjectDBPath(root)
 	database, err := db.Open(dbPath)
@@ -38,19 +53,42 @@ func NewServer(root string) (*Server, error) {
 	}, nil
 }
 
-// Run registers all MCP tools and blocks serving over stdio until the
-// client disconnects. This is the main entry point for `memvra mcp`.
+// Run registers all MCP tools, resources and prompts and blocks serving
+// over stdio until the client disconnects. This is the main entry point for
+// `memvra mcp`.
 func (s *Server) Run() error {
+	return s.serveStdio(s.newMCPServer())
+}
+
+// newMCPServer creates the MCP server with all of Memvra's tools, resources
+// and prompts registered, and keeps it for resource notifications.
+func (s *Server) newMCPServer() *server.MCPServer {
+	s.subs = newSubscriptions()
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		s.subs.drop(session.SessionID())
+	})
+	hooks.AddBeforeCallTool(func(context.Context, any, *mcp.CallToolRequest) { s.syncShared() })
+	hooks.AddBeforeReadResource(func(context.Context, any, *mcp.ReadResourceRequest) { s.syncShared() })
+	hooks.AddBeforeGetPrompt(func(context.Context, any, *mcp.GetPromptRequest) { s.syncShared() })
+
 	mcpServer := server.NewMCPServer(
 		"memvra",
 		"1.0.0",
 		server.WithToolCapabilities(false),
+		// Subscription requests are answered by the transports; see
+		// subscriptions.
+		server.WithResourceCapabilities(true, false),
+		server.WithPromptCapabilities(false),
 		server.WithInstructions(serverInstructions),
+		server.WithHooks(hooks),
 	)
+	s.mcpServer = mcpServer
 
 	s.registerTools(mcpServer)
-
-	return server.ServeStdio(mcpServer)
+	s.registerResources(mcpServer)
+	s.registerPrompts(mcpServer)
+	return mcpServer
 }
 
 // Close releases the database connection.
@@ -66,19 +104,61 @@ const serverInstructions = `Memvra gives you persistent memory across AI session
 - Retrieve relevant project context (memvra_get_context)
 - Search code and memories semantically (memvra_search)
 
+Resources (memvra://memories/{type}, memvra://sessions/recent,
+memvra://project/profile, memvra://file/{path}/chunks) and the prompts
+resume-session and review-against-constraints give the same context without
+a tool call. Subscribe to the resources you attach (resources/subscribe) to get
+notifications/resources/updated when a tool call changes them.
+
 IMPORTANT: Always call memvra_save_progress before ending a conversation or when
 the user is about to switch to a different AI tool. This ensures continuity.`
 
 // registerTools adds all Memvra tools to the MCP server.
 func (s *Server) registerTools(mcpServer *server.MCPServer) {
-	mcpServer.AddTool(s.toolSaveProgress())
-	mcpServer.AddTool(s.toolRemember())
+	mcpServer.AddTool(s.exclusive(s.toolSaveProgress()))
+	mcpServer.AddTool(s.exclusive(s.toolRemember()))
 	mcpServer.AddTool(s.toolGetContext())
 	mcpServer.AddTool(s.toolSearch())
-	mcpServer.AddTool(s.toolForget())
+	mcpServer.AddTool(s.exclusive(s.toolUpdateMemory()))
+	mcpServer.AddTool(s.exclusive(s.toolForget()))
 	mcpServer.AddTool(s.toolProjectStatus())
 	mcpServer.AddTool(s.toolListMemories())
 	mcpServer.AddTool(s.toolListSessions())
+	mcpServer.AddTool(s.toolFindSymbol())
+}
+
+// exclusive wraps the handler of a tool that writes so that it runs alone.
+func (s *Server) exclusive(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+		return handler(ctx, req)
+	}
+}
+
+// syncShared reconciles the database with the shared memory file before a
+// request is handled, so that a `git pull` reaches a long-running server
+// as it reaches the next CLI command. A file that doesn't parse leaves the
+// database as it was.
+func (s *Server) syncShared() {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, _ = s.store.LoadShared()
+}
+
+// saveShared writes a change to shared memories back to the shared file,
+// so that the next sync doesn't undo it. It returns a warning to append to
+// the tool result, or "".
+func (s *Server) saveShared() string {
+	if err := s.store.SaveSharedIfPresent(); err != nil {
+		return fmt.Sprintf("\nWarning: %s was not updated: %v", memory.SharedFileName, err)
+	}
+	return ""
+}
+
+// autoExport regenerates the project's export files after a write.
+func (s *Server) autoExport() {
+	export.AutoExport(s.root, s.store)
 }
 
 // toolSaveProgress returns the tool definition and handler for saving
@@ -120,6 +200,20 @@ func (s *Server) toolRemember() (mcp.Tool, server.ToolHandlerFunc) {
 			mcp.Description("Memory type"),
 			mcp.Enum("decision", "convention", "constraint", "note", "todo"),
 		),
+		mcp.WithString("supersedes",
+			mcp.Description("ID of an existing memory this one replaces (see memvra_list_memories). The old memory is kept in history but no longer used."),
+		),
+		mcp.WithArray("files",
+			mcp.Description("Project files or directories this memory is about (relative paths). The memory is prioritised when those files are being worked on."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Areas this memory belongs to, e.g. 'billing', 'frontend', 'infra'"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Directory this memory applies to (relative path, e.g. 'services/billing'). Scoped memories are exported to a context file in that directory instead of the root one."),
+		),
 	)
 	return tool, s.handleRemember
 }
@@ -132,6 +226,13 @@ func (s *Server) toolGetContext() (mcp.Tool, server.ToolHandlerFunc) {
 		mcp.WithString("question",
 			mcp.Description("Optional focus query to retrieve the most relevant context"),
 		),
+		mcp.WithArray("tags",
+			mcp.Description("Prioritise memories with these tags, e.g. ['frontend']"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("only_tags",
+			mcp.Description("With tags, leave out memories tagged only with other tags (untagged memories are kept)"),
+		),
 	)
 	return tool, s.handleGetContext
 }
@@ -139,7 +240,7 @@ func (s *Server) toolGetContext() (mcp.Tool, server.ToolHandlerFunc) {
 // toolSearch returns the tool definition and handler for semantic search.
 func (s *Server) toolSearch() (mcp.Tool, server.ToolHandlerFunc) {
 	tool := mcp.NewTool("memvra_search",
-		mcp.WithDescription("Search across code chunks and stored memories using semantic similarity."),
+		mcp.WithDescription("Search across code chunks and stored memories, combining keyword (full-text) and semantic similarity matches. Exact identifiers match well."),
 		mcp.WithString("query",
 			mcp.Description("What to search for"),
 			mcp.Required(),
@@ -148,10 +249,49 @@ func (s *Server) toolSearch() (mcp.Tool, server.ToolHandlerFunc) {
 			mcp.Description("Maximum number of results"),
 			mcp.DefaultNumber(10),
 		),
+		mcp.WithString("tag",
+			mcp.Description("Only return memories with this tag"),
+		),
 	)
 	return tool, s.handleSearch
 }
 
+// toolUpdateMemory returns the tool definition and handler for changing an
+// existing memory in place.
+func (s *Server) toolUpdateMemory() (mcp.Tool, server.ToolHandlerFunc) {
+	tool := mcp.NewTool("memvra_update_memory",
+		mcp.WithDescription("Change an existing memory in place, keeping its ID and history. Only the given fields change; new content is re-embedded."),
+		mcp.WithString("id",
+			mcp.Description("The memory ID to update (see memvra_list_memories)"),
+			mcp.Required(),
+		),
+		mcp.WithString("content",
+			mcp.Description("New text for the memory"),
+		),
+		mcp.WithString("type",
+			mcp.Description("New memory type"),
+			mcp.Enum("decision", "convention", "constraint", "note", "todo"),
+		),
+		mcp.WithNumber("importance",
+			mcp.Description("New importance from 0.0 to 1.0"),
+			mcp.Min(0),
+			mcp.Max(1),
+		),
+		mcp.WithArray("files",
+			mcp.Description("Replace the related file paths (relative to the project root)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Replace the memory's tags"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Directory the memory applies to; '.' for the whole project"),
+		),
+	)
+	return tool, s.handleUpdateMemory
+}
+
 // toolForget returns the tool definition and handler for deleting a memory.
 func (s *Server) toolForget() (mcp.Tool, server.ToolHandlerFunc) {
 	tool := mcp.NewTool("memvra_forget",
@@ -177,11 +317,14 @@ func (s *Server) toolProjectStatus() (mcp.Tool, server.ToolHandlerFunc) {
 // stored memories.
 func (s *Server) toolListMemories() (mcp.Tool, server.ToolHandlerFunc) {
 	tool := mcp.NewTool("memvra_list_memories",
-		mcp.WithDescription("List all stored memories, optionally filtered by type."),
+		mcp.WithDescription("List all stored memories, optionally filtered by type or tag."),
 		mcp.WithString("type",
 			mcp.Description("Filter by memory type"),
 			mcp.Enum("decision", "convention", "constraint", "note", "todo"),
 		),
+		mcp.WithString("tag",
+			mcp.Description("Filter by tag, e.g. 'billing'"),
+		),
 	)
 	return tool, s.handleListMemories
 }
@@ -198,3 +341,26 @@ func (s *Server) toolListSessions() (mcp.Tool, server.ToolHandlerFunc) {
 	)
 	return tool, s.handleListSessions
 }
+
+// toolFindSymbol returns the tool definition and handler for looking up
+// where a symbol is defined.
+func (s *Server) toolFindSymbol() (mcp.Tool, server.ToolHandlerFunc) {
+	tool := mcp.NewTool("memvra_find_symbol",
+		mcp.WithDescription("Find where a function, method, type or class is defined. Returns file paths, line ranges and declaration signatures. Prefer this over semantic search for \"where is X defined?\" questions."),
+		mcp.WithString("name",
+			mcp.Description("Symbol name, e.g. NewStore or Server.Start (a bare method name also matches)"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("fuzzy",
+			mcp.Description("Match by case-insensitive substring instead of exact name. Exact lookups fall back to fuzzy when nothing matches."),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Only return symbols of this kind (func, method, type, class, interface, ...)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of results"),
+			mcp.DefaultNumber(20),
+		),
+	)
+	return tool, s.handleFindSymbol
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Subscription methods. mcp-go answers them with "method not found", so
+// the transports hand them to subscriptions.handle before the MCP server
+// sees a message.
+const (
+	methodSubscribe   = "resources/subscribe"
+	methodUnsubscribe = "resources/unsubscribe"
+)
+
+// subscriptions records which resources each client session subscribed to.
+type subscriptions struct {
+	mu        sync.Mutex
+	bySession map[string]map[string]bool // session ID → subscribed URIs
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{bySession: make(map[string]map[string]bool)}
+}
+
+// handle answers msg if it is a subscribe or unsubscribe request from the
+// given session, and reports whether it was one.
+func (s *subscriptions) handle(sessionID string, msg []byte) (mcp.JSONRPCMessage, bool) {
+	var req struct {
+		ID     mcp.RequestId `json:"id"`
+		Method string        `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return nil, false
+	}
+	if req.Method != methodSubscribe && req.Method != methodUnsubscribe {
+		return nil, false
+	}
+	if sessionID == "" {
+		return mcp.NewJSONRPCError(req.ID, mcp.INVALID_REQUEST, "subscriptions need a session", nil), true
+	}
+	if req.Params.URI == "" {
+		return mcp.NewJSONRPCError(req.ID, mcp.INVALID_PARAMS, "uri is required", nil), true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uris := s.bySession[sessionID]
+	if req.Method == methodSubscribe {
+		if uris == nil {
+			uris = make(map[string]bool)
+			s.bySession[sessionID] = uris
+		}
+		uris[req.Params.URI] = true
+	} else {
+		delete(uris, req.Params.URI)
+		if len(uris) == 0 {
+			delete(s.bySession, sessionID)
+		}
+	}
+	return mcp.NewJSONRPCResultResponse(req.ID, mcp.EmptyResult{}), true
+}
+
+// subscribers returns the sessions subscribed to uri.
+func (s *subscriptions) subscribers(uri string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id, uris := range s.bySession {
+		if uris[uri] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// drop forgets the subscriptions of a session that ended.
+func (s *subscriptions) drop(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.bySession, sessionID)
+}
+
+// stdioSessionID is the ID mcp-go gives the single stdio client.
+const stdioSessionID = "stdio"
+
+// serveStdio serves mcpServer on stdin and stdout like server.ServeStdio,
+// answering subscription requests itself.
+func (s *Server) serveStdio(mcpServer *server.MCPServer) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	stdout := &lockedWriter{w: os.Stdout}
+	return server.NewStdioServer(mcpServer).Listen(ctx, s.filterSubscriptions(os.Stdin, stdout), stdout)
+}
+
+// filterSubscriptions returns a reader of the lines of in that are not
+// subscription requests. Those are answered on out as they are read.
+func (s *Server) filterSubscriptions(in io.Reader, out io.Writer) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if resp, ok := s.subs.handle(stdioSessionID, line); ok {
+					if data, merr := json.Marshal(resp); merr == nil {
+						_, _ = fmt.Fprintf(out, "%s\n", data)
+					}
+				} else if _, werr := pw.Write(line); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr
+}
+
+// lockedWriter serializes writes, so that subscription answers don't
+// interleave with the messages the stdio server writes.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
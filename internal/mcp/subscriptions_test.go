@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/memvra/memvra/internal/memory"
+)
+
+func TestSubscriptions_Handle(t *testing.T) {
+	subs := newSubscriptions()
+	uri := memoriesURI(memory.TypeDecision)
+
+	if _, ok := subs.handle("a", []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"x"}}`)); ok {
+		t.Error("handled a resources/read request")
+	}
+	if _, ok := subs.handle("a", []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`)); !ok {
+		t.Fatal("subscribe not handled")
+	}
+	if got := subs.subscribers(uri); len(got) != 1 || got[0] != "a" {
+		t.Errorf("subscribers = %v, want [a]", got)
+	}
+	if got := subs.subscribers(uriSessionsRecent); len(got) != 0 {
+		t.Errorf("subscribers of another resource = %v", got)
+	}
+
+	resp, _ := subs.handle("a", []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{}}`))
+	if out, _ := json.Marshal(resp); !strings.Contains(string(out), `"error"`) {
+		t.Errorf("subscribe without a uri: %s", out)
+	}
+
+	subs.handle("a", []byte(`{"jsonrpc":"2.0","id":3,"method":"resources/unsubscribe","params":{"uri":"`+uri+`"}}`))
+	if got := subs.subscribers(uri); len(got) != 0 {
+		t.Errorf("subscribers after unsubscribe = %v", got)
+	}
+
+	subs.handle("b", []byte(`{"jsonrpc":"2.0","id":4,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`))
+	subs.drop("b")
+	if got := subs.subscribers(uri); len(got) != 0 {
+		t.Errorf("subscribers after the session ended = %v", got)
+	}
+}
+
+func TestStdio_NotifiesSubscribersOnly(t *testing.T) {
+	srv := setupTestServer(t)
+	mcpServer := srv.newMCPServer()
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stdout := &lockedWriter{w: outW}
+	go func() {
+		_ = server.NewStdioServer(mcpServer).Listen(ctx, srv.filterSubscriptions(inR, stdout), stdout)
+	}()
+
+	lines := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	next := func() string {
+		t.Helper()
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("no message from the server")
+			return ""
+		}
+	}
+	send := func(msg string) {
+		t.Helper()
+		if _, err := io.WriteString(inW, msg+"\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	if init := next(); !strings.Contains(init, `"subscribe":true`) {
+		t.Errorf("initialize result doesn't offer subscriptions: %s", init)
+	}
+	send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	decisions := memoriesURI(memory.TypeDecision)
+	send(`{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"` + decisions + `"}}`)
+	if resp := next(); !strings.Contains(resp, `"id":2`) || strings.Contains(resp, `"error"`) {
+		t.Fatalf("subscribe response: %s", resp)
+	}
+
+	srv.notifyResourcesUpdated(uriSessionsRecent, decisions)
+	notification := next()
+	if !strings.Contains(notification, "notifications/resources/updated") || !strings.Contains(notification, decisions) {
+		t.Fatalf("expected an update for %s, got %s", decisions, notification)
+	}
+
+	send(`{"jsonrpc":"2.0","id":3,"method":"resources/unsubscribe","params":{"uri":"` + decisions + `"}}`)
+	if resp := next(); !strings.Contains(resp, `"id":3`) {
+		t.Fatalf("unsubscribe response: %s", resp)
+	}
+	srv.notifyResourcesUpdated(decisions)
+	send(`{"jsonrpc":"2.0","id":4,"method":"ping"}`)
+	if resp := next(); !strings.Contains(resp, `"id":4`) {
+		t.Errorf("expected only the ping response after unsubscribing, got %s", resp)
+	}
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -10,7 +11,7 @@ import (
 	"github.com/memvra/memvra/internal/adapter"
 	"github.com/memvra/memvra/internal/config"
 	ctxpkg "github.com/memvra/memvra/internal/context"
-	"github.com/memvra/memvra/internal/export"
+	"github.com/memvra/memvra/internal/git"
 	"github.com/memvra/memvra/internal/memory"
 	"github.com/memvra/memvra/internal/scanner"
 )
@@ -45,25 +46,38 @@ func (s *Server) handleSaveProgress(_ context.Context, req mcp.CallToolRequest)
 		return mcp.NewToolResultError(fmt.Sprintf("failed to save progress: %v", insertErr)), nil
 	}
 
-	export.AutoExport(s.root, s.store)
+	s.autoExport()
+	s.notifyResourcesUpdated(uriSessionsRecent)
 	return mcp.NewToolResultText("Progress saved. Other AI tools will see this context in CLAUDE.md, .cursorrules, and PROJECT_CONTEXT.md."), nil
 }
 
-func (s *Server) handleRemember(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleRemember(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	content, err := req.RequireString("content")
 	if err != nil {
 		return mcp.NewToolResultError("missing required parameter: content"), nil
 	}
 
 	typeStr := req.GetString("type", "")
+	supersedes := req.GetString("supersedes", "")
+
+	var previous memory.Memory
+	if supersedes != "" {
+		previous, err = s.store.GetMemoryByID(supersedes)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("memory %q not found", supersedes)), nil
+		}
+	}
 
 	var mt memory.MemoryType
-	if typeStr != "" {
+	switch {
+	case typeStr != "":
 		mt = memory.MemoryType(typeStr)
 		if !memory.ValidMemoryType(mt) {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid type %q (valid: decision, convention, constraint, note, todo)", typeStr)), nil
 		}
-	} else {
+	case supersedes != "":
+		mt = previous.MemoryType
+	default:
 		mt = memory.ClassifyMemoryType(content)
 	}
 
@@ -72,33 +86,104 @@ func (s *Server) handleRemember(_ context.Context, req mcp.CallToolRequest) (*mc
 		MemoryType: mt,
 		Source:     "user",
 		Importance: 0.6,
+		Supersedes: supersedes,
+		Tags:       req.GetStringSlice("tags", nil),
+	}
+	if len(m.Tags) == 0 && supersedes != "" {
+		m.Tags = previous.Tags
+	}
+	if scope, ok := req.GetArguments()["scope"].(string); ok {
+		if m.Scope, err = memory.NormalizeScope(s.root, scope); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	} else if supersedes != "" {
+		m.Scope = previous.Scope
+	}
+	if err := memory.CheckScope(s.root, m.Scope); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	for _, f := range req.GetStringSlice("files", nil) {
+		if rel := memory.NormalizeFilePath(s.root, f); rel != "" {
+			m.RelatedFiles = append(m.RelatedFiles, rel)
+		}
 	}
 	if mt == memory.TypeDecision || mt == memory.TypeConstraint {
 		m.Importance = 0.8
 	}
 
-	id, insertErr := s.store.InsertMemory(m)
-	if insertErr != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to store memory: %v", insertErr)), nil
+	gcfg, _ := config.LoadGlobal()
+	orchestrator := memory.NewOrchestrator(s.store, s.vectors, memory.NewRanker(), s.compatibleEmbedder(gcfg))
+	orchestrator.SetDedupe(memory.DedupeOptions{
+		DuplicateThreshold: gcfg.Dedupe.DuplicateThreshold,
+		ConflictThreshold:  gcfg.Dedupe.ConflictThreshold,
+		Checker:            adapter.ConflictChecker(gcfg),
+	})
+
+	res, saveErr := orchestrator.Save(ctx, m)
+	if saveErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to store memory: %v", saveErr)), nil
 	}
 
-	// Best-effort embed.
-	s.embedMemory(id, content)
+	saved := res.Memory
+	var warning string
+	if saved.Source == memory.SourceShared || previous.Source == memory.SourceShared {
+		warning = s.saveShared()
+	}
+	s.autoExport()
+	s.notifyMemoriesChanged(res.Memory.MemoryType, previous.MemoryType)
 
-	export.AutoExport(s.root, s.store)
-	return mcp.NewToolResultText(fmt.Sprintf("Remembered as %s (id: %s)", mt, id)), nil
+	var sb strings.Builder
+	switch res.Outcome {
+	case memory.OutcomeMerged:
+		fmt.Fprintf(&sb, "Merged: already remembered as %s (id: %s, %.0f%% similar); importance raised to %.2f",
+			saved.MemoryType, saved.ID, res.Similarity*100, saved.Importance)
+	default:
+		fmt.Fprintf(&sb, "Stored: remembered as %s (id: %s)", saved.MemoryType, saved.ID)
+		if supersedes != "" {
+			fmt.Fprintf(&sb, ", superseding %s", supersedes)
+		}
+		if len(saved.RelatedFiles) > 0 {
+			fmt.Fprintf(&sb, ", related to %s", strings.Join(saved.RelatedFiles, ", "))
+		}
+		if len(saved.Tags) > 0 {
+			fmt.Fprintf(&sb, ", tagged %s", strings.Join(saved.Tags, ", "))
+		}
+		if saved.Scope != "" {
+			fmt.Fprintf(&sb, ", scoped to %s/", saved.Scope)
+		}
+	}
+	if res.Outcome == memory.OutcomeConflict {
+		fmt.Fprintf(&sb, "\nConflict: this may contradict an existing %s (id: %s, %.0f%% similar): %q\nIf the new memory replaces it, forget the old one with memvra_forget.",
+			res.Existing.MemoryType, res.Existing.ID, res.Similarity*100, res.Existing.Content)
+	}
+	sb.WriteString(warning)
+	return mcp.NewToolResultText(sb.String()), nil
 }
 
 func (s *Server) handleGetContext(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	question := req.GetString("question", "")
+	built, err := s.buildContext(ctx, req.GetString("question", ""),
+		req.GetStringSlice("tags", nil), req.GetBool("only_tags", false))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to build context: %v", err)), nil
+	}
 
+	var result strings.Builder
+	if built.SystemPrompt != "" {
+		result.WriteString(built.SystemPrompt)
+		result.WriteString("\n\n")
+	}
+	result.WriteString(built.ContextText)
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// buildContext assembles project context for question the way `memvra ask`
+// does, prioritising memories tagged with tags.
+func (s *Server) buildContext(ctx context.Context, question string, tags []string, restrictTags bool) (*ctxpkg.BuiltContext, error) {
 	gcfg, _ := config.Load(s.root)
 
 	// Build embedder for semantic search (best-effort).
-	var embedder adapter.Embedder
-	if emb := buildEmbedder(gcfg); emb != nil {
-		embedder = emb
-	}
+	embedder := s.compatibleEmbedder(gcfg)
 
 	ranker := memory.NewRanker()
 	orchestrator := memory.NewOrchestrator(s.store, s.vectors, ranker, embedder)
@@ -115,21 +200,11 @@ func (s *Server) handleGetContext(ctx context.Context, req mcp.CallToolRequest)
 		TopKSessions:        gcfg.Context.TopKSessions,
 		SessionTokenBudget:  gcfg.Context.SessionTokenBudget,
 		SimilarityThreshold: gcfg.Context.SimilarityThreshold,
+		ChangedFiles:        git.CaptureWorkingState(s.root).ChangedFiles(),
+		Tags:                tags,
+		RestrictTags:        restrictTags,
 	}
-
-	built, err := builder.Build(ctx, opts)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to build context: %v", err)), nil
-	}
-
-	var result strings.Builder
-	if built.SystemPrompt != "" {
-		result.WriteString(built.SystemPrompt)
-		result.WriteString("\n\n")
-	}
-	result.WriteString(built.ContextText)
-
-	return mcp.NewToolResultText(result.String()), nil
+	return builder.Build(ctx, opts)
 }
 
 func (s *Server) handleSearch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -138,13 +213,11 @@ func (s *Server) handleSearch(ctx context.Context, req mcp.CallToolRequest) (*mc
 		return mcp.NewToolResultError("missing required parameter: query"), nil
 	}
 	topK := req.GetInt("top_k", 10)
+	tag := memory.NormalizeTags([]string{req.GetString("tag", "")})
 
 	gcfg, _ := config.Load(s.root)
 
-	var embedder adapter.Embedder
-	if emb := buildEmbedder(gcfg); emb != nil {
-		embedder = emb
-	}
+	embedder := s.compatibleEmbedder(gcfg)
 
 	ranker := memory.NewRanker()
 	orchestrator := memory.NewOrchestrator(s.store, s.vectors, ranker, embedder)
@@ -153,6 +226,7 @@ func (s *Server) handleSearch(ctx context.Context, req mcp.CallToolRequest) (*mc
 		TopKChunks:          topK,
 		TopKMemories:        topK,
 		SimilarityThreshold: gcfg.Context.SimilarityThreshold,
+		Tags:                tag,
 	})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("search failed: %v", err)), nil
@@ -184,12 +258,78 @@ func (s *Server) handleSearch(ctx context.Context, req mcp.CallToolRequest) (*mc
 	return mcp.NewToolResultText(sb.String()), nil
 }
 
+func (s *Server) handleUpdateMemory(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError("missing required parameter: id"), nil
+	}
+
+	m, err := s.store.GetMemoryByID(id)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("memory %q not found", id)), nil
+	}
+
+	oldType := m.MemoryType
+	args := req.GetArguments()
+	if _, ok := args["content"]; ok {
+		m.Content = strings.TrimSpace(req.GetString("content", ""))
+		if m.Content == "" {
+			return mcp.NewToolResultError("content can't be empty; use memvra_forget to remove a memory"), nil
+		}
+	}
+	if typeStr := req.GetString("type", ""); typeStr != "" {
+		m.MemoryType = memory.MemoryType(typeStr)
+		if !memory.ValidMemoryType(m.MemoryType) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid type %q (valid: decision, convention, constraint, note, todo)", typeStr)), nil
+		}
+	}
+	if _, ok := args["importance"]; ok {
+		m.Importance = req.GetFloat("importance", m.Importance)
+	}
+	if _, ok := args["files"]; ok {
+		m.RelatedFiles = nil
+		for _, f := range req.GetStringSlice("files", nil) {
+			if rel := memory.NormalizeFilePath(s.root, f); rel != "" {
+				m.RelatedFiles = append(m.RelatedFiles, rel)
+			}
+		}
+	}
+	if _, ok := args["tags"]; ok {
+		m.Tags = req.GetStringSlice("tags", nil)
+	}
+	if _, ok := args["scope"]; ok {
+		if m.Scope, err = memory.NormalizeScope(s.root, req.GetString("scope", "")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := memory.CheckScope(s.root, m.Scope); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	gcfg, _ := config.LoadGlobal()
+	orchestrator := memory.NewOrchestrator(s.store, s.vectors, memory.NewRanker(), s.compatibleEmbedder(gcfg))
+	saved, err := orchestrator.UpdateMemory(ctx, m)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to update memory: %v", err)), nil
+	}
+
+	var warning string
+	if saved.Source == memory.SourceShared {
+		warning = s.saveShared()
+	}
+	s.autoExport()
+	s.notifyMemoriesChanged(oldType, saved.MemoryType)
+	return mcp.NewToolResultText(fmt.Sprintf("Memory %s updated: [%s] %s (importance %.2f)%s",
+		saved.ID, saved.MemoryType, saved.Content, saved.Importance, warning)), nil
+}
+
 func (s *Server) handleForget(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError("missing required parameter: id"), nil
 	}
 
+	m, _ := s.store.GetMemoryByID(id)
 	if delErr := s.store.DeleteMemory(id); delErr != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to delete memory: %v", delErr)), nil
 	}
@@ -197,8 +337,13 @@ func (s *Server) handleForget(_ context.Context, req mcp.CallToolRequest) (*mcp.
 	// Also remove vector embedding (best-effort).
 	_ = s.vectors.DeleteMemoryEmbedding(id)
 
-	export.AutoExport(s.root, s.store)
-	return mcp.NewToolResultText(fmt.Sprintf("Memory %s deleted.", id)), nil
+	var warning string
+	if m.Source == memory.SourceShared {
+		warning = s.saveShared()
+	}
+	s.autoExport()
+	s.notifyMemoriesChanged(m.MemoryType)
+	return mcp.NewToolResultText(fmt.Sprintf("Memory %s deleted.%s", id, warning)), nil
 }
 
 func (s *Server) handleProjectStatus(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -248,7 +393,11 @@ func (s *Server) handleProjectStatus(_ context.Context, _ mcp.CallToolRequest) (
 
 func (s *Server) handleListMemories(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	typeStr := req.GetString("type", "")
-	memories, err := s.store.ListMemories(memory.MemoryType(typeStr))
+	var tags []string
+	if tag := req.GetString("tag", ""); tag != "" {
+		tags = append(tags, tag)
+	}
+	memories, err := s.store.ListMemories(memory.MemoryType(typeStr), tags...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to list memories: %v", err)), nil
 	}
@@ -259,8 +408,12 @@ func (s *Server) handleListMemories(_ context.Context, req mcp.CallToolRequest)
 
 	var sb strings.Builder
 	for _, m := range memories {
-		fmt.Fprintf(&sb, "[%s] %s\n  id: %s | source: %s | created: %s\n\n",
+		fmt.Fprintf(&sb, "[%s] %s\n  id: %s | source: %s | created: %s",
 			m.MemoryType, m.Content, m.ID, m.Source, m.CreatedAt.Format("2006-01-02 15:04"))
+		if len(m.Tags) > 0 {
+			fmt.Fprintf(&sb, " | tags: %s", strings.Join(m.Tags, ", "))
+		}
+		sb.WriteString("\n\n")
 	}
 	return mcp.NewToolResultText(sb.String()), nil
 }
@@ -290,18 +443,46 @@ func (s *Server) handleListSessions(_ context.Context, req mcp.CallToolRequest)
 	return mcp.NewToolResultText(sb.String()), nil
 }
 
-// embedMemory generates and stores a vector embedding for a memory (best-effort).
-func (s *Server) embedMemory(id, content string) {
-	gcfg, _ := config.LoadGlobal()
-	embedder := buildEmbedder(gcfg)
-	if embedder == nil {
-		return
+func (s *Server) handleFindSymbol(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := req.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("missing required parameter: name"), nil
+	}
+	kind := req.GetString("kind", "")
+	fuzzy := req.GetBool("fuzzy", false)
+	limit := req.GetInt("limit", 20)
+
+	syms, err := s.store.FindSymbols(name, kind, fuzzy, limit)
+	if err == nil && len(syms) == 0 && !fuzzy {
+		syms, err = s.store.FindSymbols(name, kind, true, limit)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("symbol lookup failed: %v", err)), nil
+	}
+
+	if len(syms) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No symbols matching %q.", name)), nil
+	}
+
+	var sb strings.Builder
+	for _, sym := range syms {
+		fmt.Fprintf(&sb, "%s:%d-%d  %s %s\n", sym.FilePath, sym.StartLine, sym.EndLine, sym.Kind, sym.Name)
+		if sym.Signature != "" {
+			fmt.Fprintf(&sb, "  %s\n", sym.Signature)
+		}
 	}
-	vecs, err := embedder.Embed(context.Background(), []string{content})
-	if err != nil || len(vecs) == 0 {
-		return
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// compatibleEmbedder builds the configured embedder, returning nil when
+// there is none or when its vectors don't match the stored ones (in which
+// case a warning is logged to stderr; stdout carries the MCP protocol).
+func (s *Server) compatibleEmbedder(gcfg config.GlobalConfig) adapter.Embedder {
+	embedder, err := memory.CompatibleEmbedder(context.Background(), s.store, s.vectors, buildEmbedder(gcfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "memvra: %v\n", err)
 	}
-	_ = s.vectors.UpsertMemoryEmbedding(id, vecs[0])
+	return embedder
 }
 
 // buildEmbedder creates an embedder from config (returns nil on failure).
@@ -310,11 +491,7 @@ func buildEmbedder(gcfg config.GlobalConfig) adapter.Embedder {
 	if name == "" {
 		name = "ollama"
 	}
-	var apiKey string
-	if name == adapter.ProviderOpenAI {
-		apiKey = gcfg.Keys.OpenAI
-	}
-	emb, err := adapter.New(name, gcfg.Ollama.EmbedModel, apiKey, gcfg.Ollama.Host)
+	emb, err := adapter.FromConfig(gcfg, name)
 	if err != nil {
 		return nil
 	}
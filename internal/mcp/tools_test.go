@@ -162,6 +162,114 @@ func TestRemember_StoresMemory(t *testing.T) {
 	}
 }
 
+func TestRemember_RelatedFiles(t *testing.T) {
+	srv := setupTestServer(t)
+
+	req := callTool("memvra_remember", map[string]interface{}{
+		"content": "JWTs are signed with RS256",
+		"type":    "decision",
+		"files":   []interface{}{"./internal/auth/jwt.go", filepath.Join(srv.root, "internal", "auth", "keys.go")},
+	})
+
+	result, err := srv.handleRemember(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("handleRemember: %v %v", err, result)
+	}
+
+	memories, _ := srv.store.ListMemories(memory.TypeDecision)
+	if len(memories) != 1 {
+		t.Fatalf("expected 1 memory, got %d", len(memories))
+	}
+	got := strings.Join(memories[0].RelatedFiles, ",")
+	if got != "internal/auth/jwt.go,internal/auth/keys.go" {
+		t.Errorf("related files: got %q", got)
+	}
+}
+
+func TestSearch_FilterByTagBeforeTopK(t *testing.T) {
+	srv := setupTestServer(t)
+	for _, m := range []memory.Memory{
+		{Content: "Components use CSS modules", MemoryType: memory.TypeConvention, Tags: []string{"frontend"}, Importance: 0.9},
+		{Content: "Components are tested with Testing Library", MemoryType: memory.TypeConvention, Tags: []string{"frontend"}, Importance: 0.9},
+		{Content: "Billing components round amounts to cents", MemoryType: memory.TypeConvention, Tags: []string{"billing"}, Importance: 0.1},
+	} {
+		if _, err := srv.store.InsertMemory(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	search := func(tag string) string {
+		t.Helper()
+		result, err := srv.handleSearch(context.Background(), callTool("memvra_search", map[string]interface{}{
+			"query": "components", "top_k": float64(1), "tag": tag,
+		}))
+		if err != nil || result.IsError {
+			t.Fatalf("handleSearch: %v %v", err, result)
+		}
+		return result.Content[0].(mcplib.TextContent).Text
+	}
+
+	if text := search("billing"); !strings.Contains(text, "round amounts to cents") {
+		t.Errorf("expected the billing memory within top_k, got: %s", text)
+	}
+	if text := search("infra"); strings.Contains(text, "## Matching Memories") {
+		t.Errorf("no memory has the tag, yet the header is shown: %s", text)
+	}
+}
+
+func TestListMemories_FilterByTag(t *testing.T) {
+	srv := setupTestServer(t)
+
+	for _, args := range []map[string]interface{}{
+		{"content": "Prices are stored in cents", "type": "convention", "tags": []interface{}{"billing"}},
+		{"content": "Components use CSS modules", "type": "convention", "tags": []interface{}{"frontend"}},
+	} {
+		if res, err := srv.handleRemember(context.Background(), callTool("memvra_remember", args)); err != nil || res.IsError {
+			t.Fatalf("handleRemember: %v %v", err, res)
+		}
+	}
+
+	result, err := srv.handleListMemories(context.Background(), callTool("memvra_list_memories", map[string]interface{}{
+		"tag": "billing",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("handleListMemories: %v %v", err, result)
+	}
+	text := result.Content[0].(mcplib.TextContent).Text
+	if !strings.Contains(text, "Prices are stored in cents") || strings.Contains(text, "CSS modules") {
+		t.Errorf("unexpected listing: %s", text)
+	}
+	if !strings.Contains(text, "tags: billing") {
+		t.Errorf("listing should show tags: %s", text)
+	}
+}
+
+func TestRemember_Scope(t *testing.T) {
+	srv := setupTestServer(t)
+	os.MkdirAll(filepath.Join(srv.root, "services", "billing"), 0o755)
+
+	result, err := srv.handleRemember(context.Background(), callTool("memvra_remember", map[string]interface{}{
+		"content": "Amounts are stored in cents",
+		"type":    "convention",
+		"scope":   "services/billing/",
+	}))
+	if err != nil || result.IsError {
+		t.Fatalf("handleRemember: %v %v", err, result)
+	}
+	memories, _ := srv.store.ListMemories(memory.TypeConvention)
+	if len(memories) != 1 || memories[0].Scope != "services/billing" {
+		t.Fatalf("expected one memory scoped to services/billing, got %+v", memories)
+	}
+
+	result, _ = srv.handleRemember(context.Background(), callTool("memvra_remember", map[string]interface{}{
+		"content": "Use Tailwind",
+		"scope":   "web",
+	}))
+	if !result.IsError {
+		t.Error("expected an error for a scope that is not a directory")
+	}
+}
+
 func TestRemember_AutoClassifies(t *testing.T) {
 	srv := setupTestServer(t)
 
@@ -185,6 +293,112 @@ func TestRemember_AutoClassifies(t *testing.T) {
 	}
 }
 
+func TestRemember_Supersedes(t *testing.T) {
+	srv := setupTestServer(t)
+
+	oldID, _ := srv.store.InsertMemory(memory.Memory{Content: "Use MySQL", MemoryType: memory.TypeDecision})
+
+	req := callTool("memvra_remember", map[string]interface{}{
+		"content":    "Use PostgreSQL instead of MySQL",
+		"supersedes": oldID,
+	})
+	result, err := srv.handleRemember(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("tool returned error: %v", result.Content)
+	}
+
+	memories, _ := srv.store.ListMemories("")
+	if len(memories) != 1 || memories[0].Content != "Use PostgreSQL instead of MySQL" {
+		t.Fatalf("expected only the replacement memory, got %+v", memories)
+	}
+	if memories[0].MemoryType != memory.TypeDecision {
+		t.Errorf("type should be inherited from superseded memory, got %q", memories[0].MemoryType)
+	}
+
+	// Unknown IDs are rejected.
+	req = callTool("memvra_remember", map[string]interface{}{
+		"content":    "something",
+		"supersedes": "nope",
+	})
+	result, _ = srv.handleRemember(context.Background(), req)
+	if !result.IsError {
+		t.Error("expected error for unknown supersedes id")
+	}
+}
+
+func TestRemember_MergesDuplicate(t *testing.T) {
+	srv := setupTestServer(t)
+
+	for i := 0; i < 2; i++ {
+		req := callTool("memvra_remember", map[string]interface{}{
+			"content": "Use PostgreSQL for JSONB support",
+			"type":    "decision",
+		})
+		result, err := srv.handleRemember(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := result.Content[0].(mcplib.TextContent).Text
+		want := "Stored"
+		if i == 1 {
+			want = "Merged"
+		}
+		if !strings.HasPrefix(text, want) {
+			t.Errorf("call %d: expected %q outcome, got: %s", i+1, want, text)
+		}
+	}
+
+	memories, _ := srv.store.ListMemories(memory.TypeDecision)
+	if len(memories) != 1 {
+		t.Fatalf("expected 1 memory after duplicate, got %d", len(memories))
+	}
+}
+
+func TestUpdateMemory_ChangesFields(t *testing.T) {
+	srv := setupTestServer(t)
+
+	id, _ := srv.store.InsertMemory(memory.Memory{Content: "Use MySQL", MemoryType: memory.TypeNote, Importance: 0.5})
+
+	req := callTool("memvra_update_memory", map[string]interface{}{
+		"id":         id,
+		"content":    "Use PostgreSQL",
+		"type":       "decision",
+		"importance": 0.9,
+		"files":      []interface{}{"db/schema.sql"},
+	})
+	result, err := srv.handleUpdateMemory(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("tool returned error: %v", result.Content)
+	}
+
+	got, _ := srv.store.GetMemoryByID(id)
+	if got.Content != "Use PostgreSQL" || got.MemoryType != memory.TypeDecision || got.Importance != 0.9 {
+		t.Errorf("unexpected memory: %+v", got)
+	}
+	if len(got.RelatedFiles) != 1 || got.RelatedFiles[0] != "db/schema.sql" {
+		t.Errorf("related files: got %v", got.RelatedFiles)
+	}
+}
+
+func TestUpdateMemory_NotFound(t *testing.T) {
+	srv := setupTestServer(t)
+
+	req := callTool("memvra_update_memory", map[string]interface{}{
+		"id":      "nope",
+		"content": "x",
+	})
+	result, _ := srv.handleUpdateMemory(context.Background(), req)
+	if !result.IsError {
+		t.Error("expected error for unknown memory")
+	}
+}
+
 func TestRemember_InvalidType(t *testing.T) {
 	srv := setupTestServer(t)
 
@@ -231,6 +445,49 @@ func TestForget_DeletesMemory(t *testing.T) {
 	}
 }
 
+func TestSharedMemories_WritesReachSharedFile(t *testing.T) {
+	srv := setupTestServer(t)
+	os.WriteFile(srv.store.SharedPath(), []byte(`
+[[memory]]
+id = "s1"
+type = "decision"
+content = "Use pgx for Postgres"
+
+[[memory]]
+id = "s2"
+type = "constraint"
+content = "Never log access tokens"
+`), 0o644)
+	srv.syncShared()
+
+	res, _ := srv.handleUpdateMemory(context.Background(), callTool("memvra_update_memory", map[string]interface{}{
+		"id": "s1", "content": "Use pgx v5 for Postgres",
+	}))
+	if res.IsError {
+		t.Fatalf("update: %v", res.Content)
+	}
+	res, _ = srv.handleForget(context.Background(), callTool("memvra_forget", map[string]interface{}{"id": "s2"}))
+	if res.IsError {
+		t.Fatalf("forget: %v", res.Content)
+	}
+
+	// The next request's sync must not undo either change.
+	srv.syncShared()
+	if m, err := srv.store.GetMemoryByID("s1"); err != nil || m.Content != "Use pgx v5 for Postgres" {
+		t.Errorf("edit reverted: %+v, %v", m, err)
+	}
+	if _, err := srv.store.GetMemoryByID("s2"); err == nil {
+		t.Error("forgotten shared memory came back")
+	}
+
+	// Changes to the file made since are picked up on the next request.
+	os.WriteFile(srv.store.SharedPath(), []byte("[[memory]]\nid = \"s3\"\ntype = \"note\"\ncontent = \"Deploys happen on Tuesdays\"\n"), 0o644)
+	srv.syncShared()
+	if _, err := srv.store.GetMemoryByID("s3"); err != nil {
+		t.Errorf("new shared memory not loaded: %v", err)
+	}
+}
+
 func TestProjectStatus_ReturnsStats(t *testing.T) {
 	srv := setupTestServer(t)
 
@@ -347,6 +604,43 @@ func TestListSessions_Empty(t *testing.T) {
 	}
 }
 
+func TestFindSymbol_ExactAndFallback(t *testing.T) {
+	srv := setupTestServer(t)
+
+	fileID, _ := srv.store.UpsertFile(memory.File{Path: "internal/auth/jwt.go", Language: "go", ContentHash: "h"})
+	srv.store.ReplaceFileSymbols(fileID, []memory.Symbol{
+		{Name: "ValidateToken", Kind: "func", StartLine: 10, EndLine: 30, Signature: "func ValidateToken(raw string) (*Claims, error)"},
+	})
+
+	result, err := srv.handleFindSymbol(context.Background(), callTool("memvra_find_symbol", map[string]interface{}{
+		"name": "ValidateToken",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(mcplib.TextContent).Text
+	if !strings.Contains(text, "internal/auth/jwt.go:10-30") || !strings.Contains(text, "func ValidateToken(raw string)") {
+		t.Errorf("expected location and signature, got: %s", text)
+	}
+
+	// No exact match for a partial name, so the lookup falls back to fuzzy.
+	result, _ = srv.handleFindSymbol(context.Background(), callTool("memvra_find_symbol", map[string]interface{}{
+		"name": "validate",
+	}))
+	text = result.Content[0].(mcplib.TextContent).Text
+	if !strings.Contains(text, "ValidateToken") {
+		t.Errorf("expected fuzzy fallback to find ValidateToken, got: %s", text)
+	}
+
+	result, _ = srv.handleFindSymbol(context.Background(), callTool("memvra_find_symbol", map[string]interface{}{
+		"name": "Nope",
+	}))
+	text = result.Content[0].(mcplib.TextContent).Text
+	if !strings.Contains(text, "No symbols") {
+		t.Errorf("expected no-match message, got: %s", text)
+	}
+}
+
 func TestGetContext_ReturnsProjectInfo(t *testing.T) {
 	srv := setupTestServer(t)
 
@@ -0,0 +1,286 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/memvra/memvra/internal/adapter"
+)
+
+// Default similarity thresholds (cosine similarity of memory embeddings).
+const (
+	DefaultDuplicateThreshold = 0.92
+	DefaultConflictThreshold  = 0.75
+)
+
+// similarCandidates is how many nearest memories are compared against a new
+// one before it is stored.
+const similarCandidates = 10
+
+// mergeImportanceStep is how much a merged duplicate raises the importance
+// of the memory it was merged into.
+const mergeImportanceStep = 0.05
+
+// SaveOutcome describes what happened to a memory passed to Orchestrator.Save.
+type SaveOutcome string
+
+const (
+	OutcomeStored   SaveOutcome = "stored"   // inserted as a new memory
+	OutcomeMerged   SaveOutcome = "merged"   // near-duplicate of Existing; nothing inserted
+	OutcomeConflict SaveOutcome = "conflict" // inserted, but likely contradicts Existing
+)
+
+// SaveResult is the result of Orchestrator.Save.
+type SaveResult struct {
+	Memory     Memory // the stored memory, or the one it was merged into
+	Outcome    SaveOutcome
+	Existing   *Memory // the similar memory behind a merge or conflict
+	Similarity float64 // cosine similarity to Existing
+}
+
+// DedupeOptions controls duplicate and contradiction detection on save.
+type DedupeOptions struct {
+	// DuplicateThreshold is the similarity at or above which a new memory
+	// is merged into an existing one of the same type.
+	DuplicateThreshold float64
+	// ConflictThreshold is the similarity at or above which a new memory is
+	// checked for contradicting an existing one.
+	ConflictThreshold float64
+	// Checker, if set, is asked to confirm likely contradictions. Without
+	// it a negation heuristic is used.
+	Checker adapter.LLMAdapter
+}
+
+// DefaultDedupeOptions returns the default thresholds with no LLM checker.
+func DefaultDedupeOptions() DedupeOptions {
+	return DedupeOptions{
+		DuplicateThreshold: DefaultDuplicateThreshold,
+		ConflictThreshold:  DefaultConflictThreshold,
+	}
+}
+
+// SetDedupe replaces the duplicate/contradiction detection options. Zero
+// thresholds keep their defaults.
+func (o *Orchestrator) SetDedupe(opts DedupeOptions) {
+	if opts.DuplicateThreshold <= 0 {
+		opts.DuplicateThreshold = DefaultDuplicateThreshold
+	}
+	if opts.ConflictThreshold <= 0 {
+		opts.ConflictThreshold = DefaultConflictThreshold
+	}
+	o.dedupe = opts
+}
+
+// Save stores m with its embedding after comparing it with the existing
+// active memories of the same type. A near-duplicate is merged into the
+// existing memory (raising its importance and adding its related files and
+// tags) instead of being inserted; a likely contradiction is inserted and
+// reported as a conflict. Memories that explicitly supersede another skip
+// the comparison.
+func (o *Orchestrator) Save(ctx context.Context, m Memory) (SaveResult, error) {
+	if !ValidMemoryType(m.MemoryType) {
+		return SaveResult{}, fmt.Errorf("orchestrator: invalid memory type %q", m.MemoryType)
+	}
+	if m.Importance == 0 {
+		m.Importance = defaultImportance(m.MemoryType)
+	}
+	m.Tags = NormalizeTags(m.Tags)
+
+	var vec []float32
+	if o.embedder != nil {
+		if vecs, err := o.embedder.Embed(ctx, []string{m.Content}); err == nil && len(vecs) > 0 {
+			vec = vecs[0]
+		}
+	}
+
+	var similar *Memory
+	var similarity float64
+	if m.Supersedes == "" {
+		similar, similarity = o.mostSimilar(m, vec)
+	}
+
+	if similar != nil && similarity >= o.dedupe.DuplicateThreshold && negated(m.Content) == negated(similar.Content) {
+		importance := math.Min(1, math.Max(similar.Importance, m.Importance)+mergeImportanceStep)
+		files := mergeFiles(similar.RelatedFiles, m.RelatedFiles)
+		if err := o.store.MergeMemory(similar.ID, importance, files); err != nil {
+			return SaveResult{}, fmt.Errorf("orchestrator: %w", err)
+		}
+		if err := o.store.AddMemoryTags(similar.ID, m.Tags); err != nil {
+			return SaveResult{}, fmt.Errorf("orchestrator: %w", err)
+		}
+		merged, err := o.store.GetMemoryByID(similar.ID)
+		if err != nil {
+			return SaveResult{}, fmt.Errorf("orchestrator: %w", err)
+		}
+		return SaveResult{Memory: merged, Outcome: OutcomeMerged, Existing: similar, Similarity: similarity}, nil
+	}
+
+	id, err := o.store.InsertMemory(m)
+	if err != nil {
+		return SaveResult{}, fmt.Errorf("orchestrator: insert memory: %w", err)
+	}
+	m.ID = id
+	if m.Status == "" {
+		m.Status = StatusActive
+	}
+	if vec != nil {
+		_ = o.vectors.UpsertMemoryEmbedding(id, vec)
+	}
+
+	result := SaveResult{Memory: m, Outcome: OutcomeStored}
+	if similar != nil && similarity >= o.dedupe.ConflictThreshold && o.contradicts(ctx, m.Content, similar.Content) {
+		result.Outcome = OutcomeConflict
+		result.Existing = similar
+		result.Similarity = similarity
+	}
+	return result, nil
+}
+
+// mergeFiles returns the union of a and b, keeping a's order.
+func mergeFiles(a, b []string) []string {
+	out := append([]string(nil), a...)
+	for _, f := range b {
+		if !slices.Contains(out, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// mostSimilar returns the active memory of m's type and scope that is
+// closest to m, with its similarity. Identical text always counts as similarity 1; other
+// comparisons need the embedding vec.
+func (o *Orchestrator) mostSimilar(m Memory, vec []float32) (*Memory, float64) {
+	existing, _ := o.store.ListMemories(m.MemoryType)
+	key := normalizeContent(m.Content)
+	for i := range existing {
+		if existing[i].Scope == m.Scope && normalizeContent(existing[i].Content) == key {
+			return &existing[i], 1
+		}
+	}
+	if vec == nil {
+		return nil, 0
+	}
+
+	matches, _ := o.vectors.SearchMemories(vec, similarCandidates, 0)
+	var best *Memory
+	var bestSim float64
+	for _, match := range matches {
+		mem, err := o.store.GetMemoryByID(match.ID)
+		if err != nil || mem.Status != StatusActive || mem.MemoryType != m.MemoryType || mem.Scope != m.Scope {
+			continue
+		}
+		stored, err := o.vectors.GetMemoryEmbedding(mem.ID)
+		if err != nil || stored == nil {
+			continue
+		}
+		if sim := cosineSimilarity(vec, stored); best == nil || sim > bestSim {
+			best, bestSim = &mem, sim
+		}
+	}
+	return best, bestSim
+}
+
+// contradicts reports whether statements a and b likely contradict each
+// other. The LLM checker decides when configured and reachable; otherwise
+// two similar statements where only one is negated count as contradicting.
+func (o *Orchestrator) contradicts(ctx context.Context, a, b string) bool {
+	if o.dedupe.Checker != nil {
+		if ok, err := CheckContradiction(ctx, o.dedupe.Checker, a, b); err == nil {
+			return ok
+		}
+	}
+	return negated(a) != negated(b)
+}
+
+// CheckContradiction asks the LLM whether two project memories contradict
+// each other.
+func CheckContradiction(ctx context.Context, llm adapter.LLMAdapter, a, b string) (bool, error) {
+	prompt := fmt.Sprintf(`Two statements were recorded about the same software project. Do they contradict each other, so that both cannot be true at the same time? A statement that refines or adds detail to the other is not a contradiction.
+
+Answer with only YES or NO.
+
+--- STATEMENT A ---
+%s
+
+--- STATEMENT B ---
+%s
+--- END ---`, trimResponse(a, 1000), trimResponse(b, 1000))
+
+	stream, err := llm.Complete(ctx, adapter.CompletionRequest{
+		UserMessage: prompt,
+		MaxTokens:   8,
+		Temperature: 0,
+		Stream:      false,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var sb strings.Builder
+	for chunk := range stream {
+		if chunk.Error != nil {
+			return false, chunk.Error
+		}
+		sb.WriteString(chunk.Text)
+	}
+
+	answer := strings.ToUpper(strings.TrimSpace(sb.String()))
+	return strings.HasPrefix(answer, "YES"), nil
+}
+
+// negations are words that flip the meaning of a statement.
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "cannot": true, "can't": true,
+	"don't": true, "doesn't": true, "didn't": true, "isn't": true, "aren't": true,
+	"won't": true, "shouldn't": true, "mustn't": true, "avoid": true, "without": true,
+}
+
+// negated reports whether s contains a negation word.
+func negated(s string) bool {
+	s = strings.ReplaceAll(strings.ToLower(s), "’", "'")
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, w := range words {
+		if negations[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentKey returns the form of s used to tell whether two memories say
+// the same thing; see normalizeContent.
+func ContentKey(s string) string {
+	return normalizeContent(s)
+}
+
+// normalizeContent lower-cases s, collapses whitespace and drops trailing
+// punctuation so trivially different restatements compare equal.
+func normalizeContent(s string) string {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	return strings.TrimRight(s, ".!;")
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 if they
+// differ in length or either is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// textEmbedder returns a fixed embedding per input text.
+type textEmbedder map[string][]float32
+
+func (e textEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = e[t]
+	}
+	return out, nil
+}
+
+// angleVec returns a 768-dim unit vector at angle radians in the first plane,
+// so cosine similarity between two angleVecs is cos(a-b).
+func angleVec(angle float64) []float32 {
+	v := make([]float32, 768)
+	v[0] = float32(math.Cos(angle))
+	v[1] = float32(math.Sin(angle))
+	return v
+}
+
+func TestOrchestrator_Save_MergesDuplicate(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	emb := textEmbedder{
+		"Use PostgreSQL for the main database":   angleVec(0),
+		"We use PostgreSQL as the main database": angleVec(0.1), // cos 0.995
+	}
+	orch := NewOrchestrator(store, vectors, NewRanker(), emb)
+
+	first, err := orch.Save(context.Background(), Memory{Content: "Use PostgreSQL for the main database", MemoryType: TypeDecision, Importance: 0.8})
+	if err != nil || first.Outcome != OutcomeStored {
+		t.Fatalf("first save: %v, outcome %q", err, first.Outcome)
+	}
+
+	second, err := orch.Save(context.Background(), Memory{Content: "We use PostgreSQL as the main database", MemoryType: TypeDecision, Importance: 0.8})
+	if err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+	if second.Outcome != OutcomeMerged {
+		t.Fatalf("outcome: got %q, want merged", second.Outcome)
+	}
+	if second.Memory.ID != first.Memory.ID {
+		t.Errorf("merged into %s, want %s", second.Memory.ID, first.Memory.ID)
+	}
+	if second.Memory.Importance <= 0.8 {
+		t.Errorf("importance not raised: %v", second.Memory.Importance)
+	}
+
+	all, _ := store.ListMemories("")
+	if len(all) != 1 {
+		t.Errorf("expected 1 memory after merge, got %d", len(all))
+	}
+}
+
+func TestOrchestrator_Save_DifferentTypeNotMerged(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	emb := textEmbedder{
+		"validate input": angleVec(0),
+		"Validate input": angleVec(0),
+	}
+	orch := NewOrchestrator(store, vectors, NewRanker(), emb)
+
+	_, _ = orch.Save(context.Background(), Memory{Content: "validate input", MemoryType: TypeConstraint})
+	res, _ := orch.Save(context.Background(), Memory{Content: "Validate input", MemoryType: TypeTodo})
+	if res.Outcome != OutcomeStored {
+		t.Errorf("outcome: got %q, want stored", res.Outcome)
+	}
+}
+
+func TestOrchestrator_Save_ExactTextWithoutEmbedder(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+	orch := NewOrchestrator(store, vectors, NewRanker(), nil)
+
+	_, _ = orch.Save(context.Background(), Memory{Content: "All jobs must be idempotent", MemoryType: TypeConstraint})
+	res, _ := orch.Save(context.Background(), Memory{Content: "all jobs must be  idempotent.", MemoryType: TypeConstraint})
+	if res.Outcome != OutcomeMerged {
+		t.Errorf("outcome: got %q, want merged", res.Outcome)
+	}
+}
+
+func TestOrchestrator_Save_FlagsConflict(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	emb := textEmbedder{
+		"Use tabs for indentation":       angleVec(0),
+		"Never use tabs for indentation": angleVec(0.3), // cos 0.955
+	}
+	orch := NewOrchestrator(store, vectors, NewRanker(), emb)
+
+	first, _ := orch.Save(context.Background(), Memory{Content: "Use tabs for indentation", MemoryType: TypeConvention})
+	res, err := orch.Save(context.Background(), Memory{Content: "Never use tabs for indentation", MemoryType: TypeConvention})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if res.Outcome != OutcomeConflict {
+		t.Fatalf("outcome: got %q, want conflict", res.Outcome)
+	}
+	if res.Existing == nil || res.Existing.ID != first.Memory.ID {
+		t.Errorf("conflict should point at %s, got %+v", first.Memory.ID, res.Existing)
+	}
+	if all, _ := store.ListMemories(""); len(all) != 2 {
+		t.Errorf("conflicting memory should still be stored, got %d memories", len(all))
+	}
+}
+
+func TestOrchestrator_Save_LLMCheckerDecides(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	emb := textEmbedder{
+		"Deploy with Docker":     angleVec(0),
+		"Deploy with Kubernetes": angleVec(0.5), // cos 0.878
+	}
+	orch := NewOrchestrator(store, vectors, NewRanker(), emb)
+	orch.SetDedupe(DedupeOptions{Checker: &stubLLM{response: "YES"}})
+
+	_, _ = orch.Save(context.Background(), Memory{Content: "Deploy with Docker", MemoryType: TypeDecision})
+	res, _ := orch.Save(context.Background(), Memory{Content: "Deploy with Kubernetes", MemoryType: TypeDecision})
+	if res.Outcome != OutcomeConflict {
+		t.Errorf("outcome: got %q, want conflict", res.Outcome)
+	}
+}
+
+func TestOrchestrator_Save_SupersedesSkipsChecks(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+	orch := NewOrchestrator(store, vectors, NewRanker(), nil)
+
+	first, _ := orch.Save(context.Background(), Memory{Content: "Use REST", MemoryType: TypeDecision})
+	res, err := orch.Save(context.Background(), Memory{Content: "Use REST", MemoryType: TypeDecision, Supersedes: first.Memory.ID})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if res.Outcome != OutcomeStored || res.Memory.ID == first.Memory.ID {
+		t.Errorf("expected a new stored memory, got %q %s", res.Outcome, res.Memory.ID)
+	}
+}
+
+func TestNegated(t *testing.T) {
+	cases := map[string]bool{
+		"Use tabs":                     false,
+		"Never use tabs":               true,
+		"We don't use an ORM":          true,
+		"We don’t use an ORM":          true,
+		"Notifications go through SNS": false,
+	}
+	for in, want := range cases {
+		if got := negated(in); got != want {
+			t.Errorf("negated(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	if got := cosineSimilarity(angleVec(0), angleVec(0)); math.Abs(got-1) > 1e-6 {
+		t.Errorf("identical vectors: got %v", got)
+	}
+	if got := cosineSimilarity(angleVec(0), angleVec(math.Pi/2)); math.Abs(got) > 1e-6 {
+		t.Errorf("orthogonal vectors: got %v", got)
+	}
+	if got := cosineSimilarity([]float32{1}, []float32{1, 2}); got != 0 {
+		t.Errorf("length mismatch: got %v", got)
+	}
+}
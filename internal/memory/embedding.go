@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/memvra/memvra/internal/adapter"
+)
+
+// EmbeddingMismatchError reports that the stored vectors were produced by a
+// different embedder than the one currently configured.
+type EmbeddingMismatchError struct {
+	StoredModel     string
+	StoredDimension int
+	Model           string
+	Dimension       int
+}
+
+func (e *EmbeddingMismatchError) Error() string {
+	stored := e.StoredModel
+	if stored == "" {
+		stored = "an unrecorded model"
+	}
+	return fmt.Sprintf("stored embeddings come from %s (%d dims) but the configured embedder is %s (%d dims) — run `memvra reembed` to rebuild them",
+		stored, e.StoredDimension, e.Model, e.Dimension)
+}
+
+// CheckEmbeddingModel verifies that vectors from model, with the given
+// dimension, can be stored alongside the existing ones. While nothing has
+// been embedded yet the vector tables are resized to fit and the model is
+// recorded on the project. Returns an *EmbeddingMismatchError when existing
+// vectors came from a different model or dimension.
+func CheckEmbeddingModel(store *Store, vectors *VectorStore, model string, dimension int) error {
+	proj, err := store.GetProject()
+	if err != nil {
+		return err
+	}
+	tableDim := vectors.Dimension()
+	if proj.EmbeddingModel == model && proj.EmbeddingDimension == dimension && tableDim == dimension {
+		return nil
+	}
+
+	count, err := vectors.CountEmbeddings()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		if tableDim != dimension {
+			if err := vectors.Reset(dimension); err != nil {
+				return err
+			}
+		}
+		return store.SetEmbeddingModel(model, dimension)
+	}
+
+	// Databases created before the model was tracked: adopt the configured
+	// model as long as its vectors fit the existing tables.
+	if proj.EmbeddingModel == "" && tableDim == dimension {
+		return store.SetEmbeddingModel(model, dimension)
+	}
+
+	return &EmbeddingMismatchError{
+		StoredModel:     proj.EmbeddingModel,
+		StoredDimension: tableDim,
+		Model:           model,
+		Dimension:       dimension,
+	}
+}
+
+// CompatibleEmbedder returns embedder if its vectors are compatible with the
+// stored ones (see CheckEmbeddingModel). On a mismatch it returns nil and
+// the *EmbeddingMismatchError, so callers fall back to lexical retrieval
+// until the vectors are rebuilt. Other failures, such as an unreachable
+// embedder, leave embedder in place; callers already treat embedding as
+// best-effort.
+func CompatibleEmbedder(ctx context.Context, store *Store, vectors *VectorStore, embedder adapter.Embedder) (adapter.Embedder, error) {
+	if embedder == nil {
+		return nil, nil
+	}
+	model, dimension, err := adapter.EmbeddingInfo(ctx, embedder)
+	if err != nil {
+		return embedder, nil
+	}
+	if err := CheckEmbeddingModel(store, vectors, model, dimension); err != nil {
+		var mismatch *EmbeddingMismatchError
+		if errors.As(err, &mismatch) {
+			return nil, err
+		}
+	}
+	return embedder, nil
+}
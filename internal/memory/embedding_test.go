@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckEmbeddingModel_EmptyTablesAdoptModel(t *testing.T) {
+	database, store := setupTestDB(t)
+	vectors := NewVectorStore(database)
+	if vectors.Dimension() == 0 {
+		t.Skip("sqlite-vec not available")
+	}
+	store.UpsertProject(Project{Name: "p", RootPath: "/p", TechStack: "{}"})
+
+	if err := CheckEmbeddingModel(store, vectors, "openai/text-embedding-3-small", 1536); err != nil {
+		t.Fatalf("CheckEmbeddingModel: %v", err)
+	}
+	if got := vectors.Dimension(); got != 1536 {
+		t.Errorf("vector tables should be resized to 1536, got %d", got)
+	}
+	proj, _ := store.GetProject()
+	if proj.EmbeddingModel != "openai/text-embedding-3-small" || proj.EmbeddingDimension != 1536 {
+		t.Errorf("model not recorded: %+v", proj)
+	}
+
+	// UpsertProject must not clobber the recorded model.
+	store.UpsertProject(Project{Name: "p2", RootPath: "/p", TechStack: "{}"})
+	proj, _ = store.GetProject()
+	if proj.EmbeddingModel != "openai/text-embedding-3-small" {
+		t.Errorf("UpsertProject cleared the embedding model: %+v", proj)
+	}
+}
+
+func TestCheckEmbeddingModel_Mismatch(t *testing.T) {
+	database, store := setupTestDB(t)
+	vectors := NewVectorStore(database)
+	if vectors.Dimension() == 0 {
+		t.Skip("sqlite-vec not available")
+	}
+	store.UpsertProject(Project{Name: "p", RootPath: "/p", TechStack: "{}"})
+
+	if err := CheckEmbeddingModel(store, vectors, "ollama/nomic-embed-text", 768); err != nil {
+		t.Fatalf("CheckEmbeddingModel: %v", err)
+	}
+	id, _ := store.InsertMemory(Memory{Content: "x", MemoryType: TypeNote})
+	if err := vectors.UpsertMemoryEmbedding(id, makeVec(1)); err != nil {
+		t.Fatalf("UpsertMemoryEmbedding: %v", err)
+	}
+
+	// Same model is fine.
+	if err := CheckEmbeddingModel(store, vectors, "ollama/nomic-embed-text", 768); err != nil {
+		t.Errorf("same model should pass: %v", err)
+	}
+
+	// A different model — even with the same dimension — is a mismatch.
+	err := CheckEmbeddingModel(store, vectors, "gemini/text-embedding-004", 768)
+	var mismatch *EmbeddingMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected EmbeddingMismatchError, got %v", err)
+	}
+	if mismatch.StoredModel != "ollama/nomic-embed-text" || mismatch.Dimension != 768 {
+		t.Errorf("unexpected mismatch details: %+v", mismatch)
+	}
+
+	// The embedder is withheld so callers don't write incompatible vectors.
+	emb, err := CompatibleEmbedder(context.Background(), store, vectors, &stubEmbedder{embeddings: [][]float32{make([]float32, 1536)}})
+	if emb != nil || err == nil {
+		t.Errorf("expected nil embedder and an error on dimension mismatch, got %v, %v", emb, err)
+	}
+}
+
+func TestCheckEmbeddingModel_LegacyVectorsAdopted(t *testing.T) {
+	database, store := setupTestDB(t)
+	vectors := NewVectorStore(database)
+	if vectors.Dimension() == 0 {
+		t.Skip("sqlite-vec not available")
+	}
+	store.UpsertProject(Project{Name: "p", RootPath: "/p", TechStack: "{}"})
+
+	// Vectors written before the model was tracked.
+	id, _ := store.InsertMemory(Memory{Content: "x", MemoryType: TypeNote})
+	vectors.UpsertMemoryEmbedding(id, makeVec(1))
+
+	if err := CheckEmbeddingModel(store, vectors, "ollama/nomic-embed-text", 768); err != nil {
+		t.Fatalf("matching dimension should be adopted: %v", err)
+	}
+	proj, _ := store.GetProject()
+	if proj.EmbeddingModel != "ollama/nomic-embed-text" {
+		t.Errorf("model not recorded: %+v", proj)
+	}
+}
+
+func TestStore_ReplaceEmbeddings(t *testing.T) {
+	database, store := setupTestDB(t)
+	vectors := NewVectorStore(database)
+	if vectors.Dimension() == 0 {
+		t.Skip("sqlite-vec not available")
+	}
+	store.UpsertProject(Project{Name: "p", RootPath: "/p", TechStack: "{}"})
+	if err := CheckEmbeddingModel(store, vectors, "ollama/nomic-embed-text", 768); err != nil {
+		t.Fatalf("CheckEmbeddingModel: %v", err)
+	}
+	id, _ := store.InsertMemory(Memory{Content: "x", MemoryType: TypeNote})
+	if err := vectors.UpsertMemoryEmbedding(id, makeVec(1)); err != nil {
+		t.Fatalf("UpsertMemoryEmbedding: %v", err)
+	}
+
+	// A vector of the wrong size fails the swap, which must change nothing.
+	bad := map[string][]float32{id: make([]float32, 3)}
+	if err := store.ReplaceEmbeddings("openai/text-embedding-3-small", 1536, nil, bad); err == nil {
+		t.Fatal("expected an error for a vector of the wrong dimension")
+	}
+	if proj, _ := store.GetProject(); proj.EmbeddingModel != "ollama/nomic-embed-text" || vectors.Dimension() != 768 {
+		t.Errorf("failed swap changed the model: %s (%d dims)", proj.EmbeddingModel, vectors.Dimension())
+	}
+	if got, _ := vectors.GetMemoryEmbedding(id); got == nil {
+		t.Error("failed swap dropped the old embedding")
+	}
+
+	vec := make([]float32, 1536)
+	vec[0] = 1
+	if err := store.ReplaceEmbeddings("openai/text-embedding-3-small", 1536, nil, map[string][]float32{id: vec}); err != nil {
+		t.Fatalf("ReplaceEmbeddings: %v", err)
+	}
+	if proj, _ := store.GetProject(); proj.EmbeddingModel != "openai/text-embedding-3-small" || vectors.Dimension() != 1536 {
+		t.Errorf("model not switched: %s (%d dims)", proj.EmbeddingModel, vectors.Dimension())
+	}
+	if got, _ := vectors.GetMemoryEmbedding(id); len(got) != 1536 {
+		t.Errorf("new embedding has %d dims, want 1536", len(got))
+	}
+}
@@ -11,8 +11,9 @@ import (
 
 // extractCandidate is the JSON shape returned by the extraction prompt.
 type extractCandidate struct {
-	Content string `json:"content"`
-	Type    string `json:"type"`
+	Content string   `json:"content"`
+	Type    string   `json:"type"`
+	Files   []string `json:"files"`
 }
 
 // ExtractMemories sends the LLM response to the LLM and asks it to identify
@@ -27,12 +28,13 @@ func ExtractMemories(ctx context.Context, llm adapter.LLMAdapter, responseText s
 
 	prompt := fmt.Sprintf(`From the assistant response below, extract any decisions, constraints, or conventions that were explicitly stated or recommended. These are things the team should remember for future sessions.
 
-Return ONLY a compact JSON array. Each element: {"content": "...", "type": "decision|constraint|convention|todo|note"}.
+Return ONLY a compact JSON array. Each element: {"content": "...", "type": "decision|constraint|convention|todo|note", "files": ["path/to/file", ...]}.
 - decision: something chosen ("we will use X", "we switched to Y")
 - constraint: a hard rule ("must", "never", "always", "only")
 - convention: a style or pattern guideline
 - todo: a future task or follow-up
 - note: anything else worth remembering
+"files" lists the project file paths the item is about, as mentioned in the response; use [] if none.
 
 If nothing qualifies, return []. No prose, no markdown — only the JSON array.
 Maximum %d items.
@@ -100,15 +102,32 @@ func parseExtractionJSON(raw string, max int) ([]Memory, error) {
 			mt = ClassifyMemoryType(content)
 		}
 		out = append(out, Memory{
-			Content:    content,
-			MemoryType: mt,
-			Importance: defaultImportance(mt),
-			Source:     "extracted",
+			Content:      content,
+			MemoryType:   mt,
+			Importance:   defaultImportance(mt),
+			Source:       "extracted",
+			RelatedFiles: candidateFiles(c.Files, content),
 		})
 	}
 	return out, nil
 }
 
+// candidateFiles merges the files the LLM attributed to an extracted item
+// with any paths mentioned in its content.
+func candidateFiles(files []string, content string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, f := range append(files, FilePathsIn(content)...) {
+		f = NormalizeFilePath("", f)
+		if f == "" || f == "." || seen[f] {
+			continue
+		}
+		seen[f] = true
+		out = append(out, f)
+	}
+	return out
+}
+
 // SummarizeSession asks the LLM to produce a 2-3 sentence summary of a
 // question/answer exchange. Returns empty string on failure (non-fatal).
 func SummarizeSession(ctx context.Context, llm adapter.LLMAdapter, question, responseText string, maxTokens int) (string, error) {
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/memvra/memvra/internal/adapter"
@@ -239,3 +240,15 @@ func TestSummarizeSession_TrimsWhitespace(t *testing.T) {
 		t.Errorf("expected trimmed summary, got %q", summary)
 	}
 }
+
+func TestParseExtractionJSON_RelatedFiles(t *testing.T) {
+	raw := `[{"content": "Tokens are validated in internal/auth/jwt.go", "type": "note", "files": ["./internal/auth/middleware.go"]}]`
+	memories, err := parseExtractionJSON(raw, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"internal/auth/middleware.go", "internal/auth/jwt.go"}
+	if len(memories) != 1 || !slices.Equal(memories[0].RelatedFiles, want) {
+		t.Fatalf("related files: got %+v, want %v", memories, want)
+	}
+}
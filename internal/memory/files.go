@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// NormalizeFilePath returns p as a clean, slash-separated path relative to
+// the project root. Absolute paths outside root are returned cleaned but
+// otherwise unchanged.
+func NormalizeFilePath(root, p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	if filepath.IsAbs(p) && root != "" {
+		if rel, err := filepath.Rel(root, p); err == nil && !strings.HasPrefix(rel, "..") {
+			p = rel
+		}
+	}
+	p = filepath.ToSlash(filepath.Clean(p))
+	return strings.TrimPrefix(p, "./")
+}
+
+// NormalizeScope returns dir as a scope for Memory.Scope: a clean,
+// slash-separated directory relative to root, or "" for the project root.
+// Directories outside the project are rejected.
+func NormalizeScope(root, dir string) (string, error) {
+	dir = NormalizeFilePath(root, dir)
+	if dir == "." {
+		return "", nil
+	}
+	scope := strings.TrimSuffix(dir, "/")
+	if scope != "" && !filepath.IsLocal(filepath.FromSlash(scope)) {
+		return "", fmt.Errorf("scope %s is outside the project", scope)
+	}
+	return scope, nil
+}
+
+// CheckScope returns an error unless scope is "" or an existing directory
+// inside the project at root. Scopes name where nested context files are
+// written, so one that leaves the project, directly or through a symlink,
+// is refused.
+func CheckScope(root, scope string) error {
+	if scope == "" {
+		return nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(scope)) {
+		return fmt.Errorf("scope %s is outside the project", scope)
+	}
+	dir := filepath.Join(root, filepath.FromSlash(scope))
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("scope %s is not a directory in the project", scope)
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(realRoot, realDir); err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("scope %s is outside the project", scope)
+	}
+	return nil
+}
+
+// RelatesTo reports whether any of m's related files is one of paths, or a
+// directory containing one of them. A scoped memory also relates to every
+// path inside its scope. Paths must be normalized.
+func (m Memory) RelatesTo(paths []string) bool {
+	related := m.RelatedFiles
+	if m.Scope != "" {
+		related = append([]string{m.Scope}, related...)
+	}
+	for _, rf := range related {
+		dir := strings.TrimSuffix(rf, "/") + "/"
+		for _, p := range paths {
+			if p == rf || strings.HasPrefix(p, dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filePathPattern matches path-like tokens such as internal/db/db.go,
+// ./src/app.ts or README.md.
+var filePathPattern = regexp.MustCompile(`(?:\.{0,2}/)?(?:[A-Za-z0-9_\-.]+/)*[A-Za-z0-9_\-]+\.[A-Za-z][A-Za-z0-9]{0,5}\b`)
+
+// sourceExtensions are file extensions recognised on bare file names; paths
+// with a directory component are accepted with any extension.
+var sourceExtensions = map[string]bool{
+	"go": true, "py": true, "rb": true, "js": true, "jsx": true, "ts": true, "tsx": true,
+	"java": true, "kt": true, "rs": true, "c": true, "h": true, "cc": true, "cpp": true,
+	"hpp": true, "cs": true, "php": true, "swift": true, "scala": true, "ex": true,
+	"exs": true, "vue": true, "svelte": true, "sql": true, "sh": true, "yml": true,
+	"yaml": true, "toml": true, "json": true, "md": true, "proto": true, "tf": true,
+	"gradle": true, "xml": true, "html": true, "css": true, "scss": true, "lock": true,
+}
+
+// FilePathsIn returns the distinct file paths mentioned in text, in order
+// of first appearance. URLs are ignored.
+func FilePathsIn(text string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, loc := range filePathPattern.FindAllStringIndex(text, -1) {
+		if isURLContext(text, loc[0]) {
+			continue
+		}
+		p := text[loc[0]:loc[1]]
+		ext := strings.ToLower(p[strings.LastIndex(p, ".")+1:])
+		if !strings.Contains(p, "/") && !sourceExtensions[ext] {
+			continue
+		}
+		if first, _, ok := strings.Cut(strings.TrimLeft(p, "./"), "/"); ok && strings.Contains(first, ".") {
+			continue // looks like a domain: example.com/path
+		}
+		p = NormalizeFilePath("", p)
+		if p == "." || strings.HasPrefix(p, "../") || seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out
+}
+
+// isURLContext reports whether the token starting at i is part of a URL or
+// an e-mail address.
+func isURLContext(text string, i int) bool {
+	start := strings.LastIndexAny(text[:i], " \t\n\"'`([<") + 1
+	prefix := text[start:i]
+	return strings.Contains(prefix, ":/") || strings.HasSuffix(prefix, "@")
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeFilePath(t *testing.T) {
+	cases := []struct{ root, in, want string }{
+		{"/repo", "internal/db/db.go", "internal/db/db.go"},
+		{"/repo", "./cmd/main.go", "cmd/main.go"},
+		{"/repo", "/repo/internal/auth/", "internal/auth"},
+		{"/repo", "/elsewhere/x.go", "/elsewhere/x.go"},
+		{"/repo", "  ", ""},
+	}
+	for _, c := range cases {
+		if got := NormalizeFilePath(c.root, c.in); got != c.want {
+			t.Errorf("NormalizeFilePath(%q, %q) = %q, want %q", c.root, c.in, got, c.want)
+		}
+	}
+}
+
+func TestMemory_RelatesTo(t *testing.T) {
+	m := Memory{RelatedFiles: []string{"internal/auth", "cmd/main.go"}}
+	if !m.RelatesTo([]string{"internal/auth/jwt.go"}) {
+		t.Error("file inside related directory should match")
+	}
+	if !m.RelatesTo([]string{"README.md", "cmd/main.go"}) {
+		t.Error("exact path should match")
+	}
+	if m.RelatesTo([]string{"internal/authz/policy.go"}) {
+		t.Error("sibling directory with a shared prefix should not match")
+	}
+	if (Memory{}).RelatesTo([]string{"cmd/main.go"}) {
+		t.Error("memory without files should not match")
+	}
+}
+
+func TestNormalizeScope(t *testing.T) {
+	cases := map[string]string{
+		".":                  "",
+		"":                   "",
+		"services/billing/":  "services/billing",
+		"/repo/web":          "web",
+		"./services/billing": "services/billing",
+	}
+	for in, want := range cases {
+		if got, err := NormalizeScope("/repo", in); err != nil || got != want {
+			t.Errorf("NormalizeScope(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"../other-repo", "/elsewhere/web", "services/../../x"} {
+		if got, err := NormalizeScope("/repo", in); err == nil {
+			t.Errorf("NormalizeScope(%q) = %q, want an error", in, got)
+		}
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "services", "billing"), 0o755)
+	os.Symlink(outside, filepath.Join(root, "linked"))
+
+	if err := CheckScope(root, "services/billing"); err != nil {
+		t.Errorf("directory in the project: %v", err)
+	}
+	for _, scope := range []string{"services/missing", "../x", "linked"} {
+		if err := CheckScope(root, scope); err == nil {
+			t.Errorf("CheckScope(%q) should fail", scope)
+		}
+	}
+}
+
+func TestMemory_RelatesTo_Scope(t *testing.T) {
+	m := Memory{Scope: "services/billing"}
+	if !m.RelatesTo([]string{"services/billing/invoice.go"}) {
+		t.Error("path inside the scope should match")
+	}
+	if m.RelatesTo([]string{"services/auth/login.go"}) {
+		t.Error("path outside the scope should not match")
+	}
+}
+
+func TestFilePathsIn(t *testing.T) {
+	text := "Moved token parsing into internal/auth/jwt.go and updated ./cmd/main.go. " +
+		"See https://example.com/docs/setup.html or mail dev@example.com. " +
+		"Config lives in config.toml; jwt.go is covered by internal/auth/jwt.go tests, e.g. 1.5 vs v2.0."
+	got := FilePathsIn(text)
+	want := []string{"internal/auth/jwt.go", "cmd/main.go", "config.toml", "jwt.go"}
+	if !slices.Equal(got, want) {
+		t.Errorf("FilePathsIn = %v, want %v", got, want)
+	}
+}
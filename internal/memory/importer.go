@@ -0,0 +1,253 @@
+package memory
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// minImportLength is the shortest line imported as a memory; shorter
+// bullets are usually labels or fragments.
+const minImportLength = 8
+
+var (
+	// bulletPrefix matches markdown list markers: "-", "*", "+" or "1.".
+	bulletPrefix = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+	// adrNumber matches ADR title numbering such as "ADR-012:", "12." or
+	// "0012 -". A bare number needs a separator and a space after it, so
+	// titles like "2FA rollout" and "12-factor config" keep their digits.
+	adrNumber = regexp.MustCompile(`(?i)^(?:adr[-\s]?\d+\s*[:.\-]?\s*|\d+\s*[:.\-]\s+)`)
+)
+
+// ParseMarkdownMemories turns the bullets of a hand-written context file
+// (CLAUDE.md, .cursorrules, ...) into memories. A bullet's type comes from
+// the heading it is under, e.g. "## Conventions", or else from its text.
+// Files that are not markdown (.cursorrules, .windsurfrules) are often a
+// plain list of rules, so every line of text is taken as an item there.
+// origin is the file's path relative to the project root; it is recorded
+// in RelatedFiles.
+func ParseMarkdownMemories(content, origin string) []Memory {
+	ext := strings.ToLower(path.Ext(origin))
+	plain := ext != ".md" && ext != ".mdc" && ext != ".markdown"
+
+	var out []Memory
+	var headingType MemoryType
+	var current *Memory
+	inFence := false
+
+	flush := func() {
+		if current != nil && len(current.Content) >= minImportLength {
+			out = append(out, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(stripFrontmatter(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			headingType = typeFromHeading(strings.TrimLeft(trimmed, "# "))
+		case strings.HasPrefix(trimmed, "<!--") || strings.HasPrefix(trimmed, ">"):
+			flush()
+		case bulletPrefix.MatchString(trimmed) || plain:
+			flush()
+			text := bulletPrefix.ReplaceAllString(trimmed, "")
+			mt := headingType
+			switch {
+			case strings.HasPrefix(text, "[ ] "):
+				text, mt = text[4:], TypeTodo
+			case strings.HasPrefix(strings.ToLower(text), "[x] "):
+				continue // done
+			}
+			if mt == "" {
+				mt = ClassifyMemoryType(text)
+			}
+			current = &Memory{
+				Content:      text,
+				MemoryType:   mt,
+				Importance:   defaultImportance(mt),
+				Source:       "imported",
+				RelatedFiles: []string{origin},
+			}
+		case current != nil && line != trimmed:
+			// An indented line continues the bullet above it.
+			current.Content += " " + trimmed
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out
+}
+
+// IsADR reports whether content looks like an Architecture Decision Record:
+// a document with a Decision section.
+func IsADR(content string) bool {
+	sections := markdownSections(content)
+	_, ok := sections["decision"]
+	if !ok {
+		_, ok = sections["decision outcome"]
+	}
+	return ok
+}
+
+// ParseADR turns an Architecture Decision Record into a decision memory
+// made of its title and the text of its Decision section. ok is false for
+// records that are not in force (rejected, superseded or deprecated).
+// Proposed decisions are imported with lower importance.
+func ParseADR(content, origin string) (m Memory, ok bool) {
+	sections := markdownSections(content)
+
+	status := strings.ToLower(firstLine(sections["status"]))
+	for _, line := range strings.Split(content, "\n") {
+		if s, found := strings.CutPrefix(strings.TrimSpace(line), "Status:"); found && status == "" {
+			status = strings.ToLower(strings.TrimSpace(s))
+		}
+	}
+	for _, gone := range []string{"rejected", "superseded", "deprecated"} {
+		if strings.Contains(status, gone) {
+			return Memory{}, false
+		}
+	}
+
+	title := adrNumber.ReplaceAllString(strings.TrimSpace(sections[""]), "")
+	decision, found := sections["decision"]
+	if !found {
+		decision = sections["decision outcome"]
+	}
+	decision = trimResponse(joinLines(decision), 600)
+
+	switch {
+	case title != "" && decision != "":
+		m.Content = title + ": " + decision
+	case decision != "":
+		m.Content = decision
+	case title != "":
+		m.Content = title
+	default:
+		return Memory{}, false
+	}
+
+	m.MemoryType = TypeDecision
+	m.Importance = defaultImportance(TypeDecision)
+	if strings.Contains(status, "proposed") || strings.Contains(status, "draft") {
+		m.Content += " (proposed)"
+		m.Importance = 0.6
+	}
+	m.Source = "imported"
+	m.RelatedFiles = []string{origin}
+	return m, true
+}
+
+// DropKnown removes candidates whose text matches an existing memory or an
+// earlier candidate, so that importing the same file twice adds nothing.
+func DropKnown(candidates, existing []Memory) (fresh []Memory, skipped int) {
+	seen := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		seen[normalizeContent(m.Content)] = true
+	}
+	for _, m := range candidates {
+		key := normalizeContent(m.Content)
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+		fresh = append(fresh, m)
+	}
+	return fresh, skipped
+}
+
+// typeFromHeading returns the memory type implied by a section heading, or
+// "" if the heading doesn't name one.
+func typeFromHeading(heading string) MemoryType {
+	lower := strings.ToLower(heading)
+	switch {
+	case strings.Contains(lower, "todo") || strings.Contains(lower, "task"):
+		return TypeTodo
+	case strings.Contains(lower, "decision") || strings.Contains(lower, "architecture"):
+		return TypeDecision
+	case strings.Contains(lower, "constraint") || strings.Contains(lower, "don't") ||
+		strings.Contains(lower, "never") || strings.Contains(lower, "security"):
+		return TypeConstraint
+	case strings.Contains(lower, "convention") || strings.Contains(lower, "style") ||
+		strings.Contains(lower, "guideline") || strings.Contains(lower, "standard"):
+		return TypeConvention
+	case strings.Contains(lower, "note"):
+		return TypeNote
+	default:
+		return ""
+	}
+}
+
+// markdownSections splits content by its "##" headings, keyed by the
+// lowercased heading text. The "" key holds the "#" title.
+func markdownSections(content string) map[string]string {
+	sections := make(map[string]string)
+	key := "\x00" // text before the first "##" heading is ignored
+	for _, line := range strings.Split(stripFrontmatter(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "# "):
+			if _, ok := sections[""]; !ok {
+				sections[""] = strings.TrimSpace(trimmed[2:])
+			}
+		case strings.HasPrefix(trimmed, "## "):
+			key = strings.ToLower(strings.TrimSpace(trimmed[3:]))
+			sections[key] = ""
+		default:
+			if key != "\x00" {
+				sections[key] += line + "\n"
+			}
+		}
+	}
+	return sections
+}
+
+// joinLines collapses a markdown section into one line of text, dropping
+// list markers.
+func joinLines(s string) string {
+	var parts []string
+	for _, line := range strings.Split(s, "\n") {
+		line = bulletPrefix.ReplaceAllString(strings.TrimSpace(line), "")
+		if line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// firstLine returns the first non-empty line of s.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// stripFrontmatter removes a leading YAML frontmatter block, as found in
+// Cursor .mdc rules.
+func stripFrontmatter(content string) string {
+	if !strings.HasPrefix(content, "---\n") {
+		return content
+	}
+	if end := strings.Index(content[4:], "\n---"); end >= 0 {
+		rest := content[4+end+4:]
+		return strings.TrimPrefix(rest, "\n")
+	}
+	return content
+}
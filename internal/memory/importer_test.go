@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMarkdownMemories(t *testing.T) {
+	content := "# House rules\n\n" +
+		"## Coding Conventions\n" +
+		"- Use table-driven tests\n" +
+		"- Wrap errors with the package name,\n  e.g. \"store: insert: %w\"\n" +
+		"- [x] Migrate to Go 1.22\n\n" +
+		"## Constraints\n" +
+		"* Never log API keys\n" +
+		"* Short\n\n" +
+		"A paragraph that is not a rule.\n\n" +
+		"```\n- not a bullet, code\n```\n\n" +
+		"## Misc\n" +
+		"1. We decided to use PostgreSQL\n" +
+		"- [ ] Add rate limiting\n"
+
+	got := ParseMarkdownMemories(content, "CLAUDE.md")
+	want := []struct {
+		content string
+		mt      MemoryType
+	}{
+		{"Use table-driven tests", TypeConvention},
+		{`Wrap errors with the package name, e.g. "store: insert: %w"`, TypeConvention},
+		{"Never log API keys", TypeConstraint},
+		{"We decided to use PostgreSQL", TypeDecision},
+		{"Add rate limiting", TypeTodo},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d memories, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Content != w.content || got[i].MemoryType != w.mt {
+			t.Errorf("memory %d = %q (%s), want %q (%s)", i, got[i].Content, got[i].MemoryType, w.content, w.mt)
+		}
+		if got[i].Source != "imported" || len(got[i].RelatedFiles) != 1 || got[i].RelatedFiles[0] != "CLAUDE.md" {
+			t.Errorf("memory %d should be imported from CLAUDE.md, got source %q files %v", i, got[i].Source, got[i].RelatedFiles)
+		}
+	}
+}
+
+func TestParseMarkdownMemories_PlainRules(t *testing.T) {
+	got := ParseMarkdownMemories("# Frontend\nAlways use pnpm, never npm\nPrefer function components\n", ".cursorrules")
+	if len(got) != 2 || got[0].Content != "Always use pnpm, never npm" || got[0].MemoryType != TypeConstraint {
+		t.Errorf("each line of a plain rules file should be a memory, got %+v", got)
+	}
+}
+
+func TestParseADR(t *testing.T) {
+	adr := "# 3. Use PostgreSQL\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n\n## Context\n\nWe need a database.\n\n" +
+		"## Decision\n\nWe will use PostgreSQL,\nfor JSONB support.\n\n## Consequences\n\nOps runs Postgres.\n"
+	if !IsADR(adr) {
+		t.Fatal("expected the document to be recognised as an ADR")
+	}
+	m, ok := ParseADR(adr, "docs/adr/0003-use-postgres.md")
+	if !ok {
+		t.Fatal("accepted ADR should be imported")
+	}
+	if m.Content != "Use PostgreSQL: We will use PostgreSQL, for JSONB support." {
+		t.Errorf("Content = %q", m.Content)
+	}
+	if m.MemoryType != TypeDecision || m.Source != "imported" || m.RelatedFiles[0] != "docs/adr/0003-use-postgres.md" {
+		t.Errorf("unexpected memory: %+v", m)
+	}
+
+	if _, ok := ParseADR("# Use Mongo\n\n## Status\n\nSuperseded by ADR-3\n\n## Decision\n\nUse Mongo.\n", "x.md"); ok {
+		t.Error("superseded ADR should be skipped")
+	}
+	if m, ok := ParseADR("# ADR-7: Use gRPC\n\nStatus: Proposed\n\n## Decision\n\nUse gRPC internally.\n", "x.md"); !ok ||
+		m.Content != "Use gRPC: Use gRPC internally. (proposed)" || m.Importance >= 0.8 {
+		t.Errorf("proposed ADR should be imported with lower importance, got %+v", m)
+	}
+	for title, want := range map[string]string{
+		"ADR-012: Use gRPC":  "Use gRPC",
+		"adr 4 Use gRPC":     "Use gRPC",
+		"12. Use gRPC":       "Use gRPC",
+		"0012 - Use gRPC":    "Use gRPC",
+		"2FA rollout":        "2FA rollout",
+		"12-factor config":   "12-factor config",
+		"3.5x faster builds": "3.5x faster builds",
+	} {
+		m, ok := ParseADR("# "+title+"\n\n## Decision\n\nDo it.\n", "x.md")
+		if !ok || !strings.HasPrefix(m.Content, want+":") {
+			t.Errorf("title %q: got %q, want it to start with %q", title, m.Content, want+":")
+		}
+	}
+	if IsADR("# Notes\n\n## Ideas\n\n- something\n") {
+		t.Error("document without a Decision section is not an ADR")
+	}
+}
+
+func TestDropKnown(t *testing.T) {
+	existing := []Memory{{Content: "Use table-driven tests."}}
+	candidates := []Memory{
+		{Content: "use  table-driven tests"},
+		{Content: "Never log API keys"},
+		{Content: "never log api keys"},
+	}
+	fresh, skipped := DropKnown(candidates, existing)
+	if len(fresh) != 1 || fresh[0].Content != "Never log API keys" || skipped != 2 {
+		t.Errorf("DropKnown = %+v, skipped %d", fresh, skipped)
+	}
+}
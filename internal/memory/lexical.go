@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TextMatch is a single lexical search result. Higher scores are better.
+type TextMatch struct {
+	ID    string
+	Score float64
+}
+
+// maxSearchTerms caps how many query terms are sent to the full-text index.
+const maxSearchTerms = 32
+
+// stopwords are dropped from lexical queries; they match nearly everything.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "can": true, "do": true, "does": true, "for": true,
+	"from": true, "how": true, "i": true, "in": true, "is": true, "it": true,
+	"of": true, "on": true, "or": true, "should": true, "that": true, "the": true,
+	"this": true, "to": true, "we": true, "what": true, "when": true, "where": true,
+	"which": true, "who": true, "why": true, "with": true, "you": true,
+}
+
+// SearchChunksText returns up to k chunks whose content matches the terms
+// of query, best first. It uses the FTS5 index (BM25) when available and a
+// term-count LIKE scan otherwise.
+func (s *Store) SearchChunksText(query string, k int) ([]TextMatch, error) {
+	matches, err := s.searchText("chunks", query, k, s.db.HasFTS())
+	if err != nil {
+		return nil, fmt.Errorf("store: search chunks: %w", err)
+	}
+	return matches, nil
+}
+
+// SearchMemoriesText returns up to k active memories whose content matches
+// the terms of query, best first.
+func (s *Store) SearchMemoriesText(query string, k int) ([]TextMatch, error) {
+	matches, err := s.searchText("memories", query, k, s.db.HasFTS())
+	if err != nil {
+		return nil, fmt.Errorf("store: search memories: %w", err)
+	}
+	return matches, nil
+}
+
+// searchText runs a lexical search over the content column of table, which
+// must be "chunks" or "memories", using the FTS5 index if fts is set.
+// Memories that are no longer active are skipped.
+func (s *Store) searchText(table, query string, k int, fts bool) ([]TextMatch, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 || k <= 0 {
+		return nil, nil
+	}
+	filter := "1 = 1"
+	if table == "memories" {
+		filter = "t.status = 'active'"
+	}
+
+	var sqlQuery string
+	var args []any
+	if fts {
+		quoted := make([]string, len(terms))
+		for i, t := range terms {
+			quoted[i] = `"` + t + `"`
+		}
+		sqlQuery = fmt.Sprintf(`
+			SELECT t.id, -bm25(fts_%[1]s) AS score
+			FROM fts_%[1]s JOIN %[1]s t ON t.rowid = fts_%[1]s.rowid
+			WHERE fts_%[1]s MATCH ? AND %[2]s
+			ORDER BY score DESC
+			LIMIT ?`, table, filter)
+		args = append(args, strings.Join(quoted, " OR "), k)
+	} else {
+		// No FTS5: score each row by how many distinct terms it contains.
+		parts := make([]string, len(terms))
+		for i, t := range terms {
+			parts[i] = "(instr(lower(content), ?) > 0)"
+			args = append(args, t)
+		}
+		sqlQuery = fmt.Sprintf(`
+			SELECT id, score FROM (
+				SELECT id, rowid, %s AS score FROM %s t WHERE %s
+			) WHERE score > 0
+			ORDER BY score DESC, rowid
+			LIMIT ?`, strings.Join(parts, " + "), table, filter)
+		args = append(args, k)
+	}
+
+	rows, err := s.db.Conn().Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []TextMatch
+	for rows.Next() {
+		var m TextMatch
+		if err := rows.Scan(&m.ID, &m.Score); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// searchTerms splits query into lower-cased identifier-like terms, dropping
+// stopwords, single characters and duplicates.
+func searchTerms(query string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	seen := make(map[string]bool, len(fields))
+	var terms []string
+	for _, f := range fields {
+		if len(f) < 2 || stopwords[f] || seen[f] {
+			continue
+		}
+		seen[f] = true
+		terms = append(terms, f)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms("How does the UpsertFile function handle max_size? upsertfile")
+	want := []string{"upsertfile", "function", "handle", "max_size"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("term %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSearchChunksText_RanksMatches(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	fileID, _ := store.UpsertFile(File{Path: "store.go", Language: "go", LastModified: time.Now(), ContentHash: "h"})
+	upsertID, _ := store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func (s *Store) UpsertFile(f File) (string, error) { /* insert or update a file */ }", ChunkType: "code"})
+	store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func (s *Store) DeleteFile(id string) error", ChunkType: "code"})
+	store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func parseTime(s string) time.Time", ChunkType: "code"})
+
+	matches, err := store.SearchChunksText("where is UpsertFile defined", 10)
+	if err != nil {
+		t.Fatalf("SearchChunksText: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != upsertID {
+		t.Fatalf("expected only the UpsertFile chunk, got %+v", matches)
+	}
+
+	matches, _ = store.SearchChunksText("upsertfile error", 10)
+	if len(matches) != 2 || matches[0].ID != upsertID {
+		t.Errorf("expected UpsertFile chunk first of 2, got %+v", matches)
+	}
+
+	if matches, _ := store.SearchChunksText("the of and", 10); matches != nil {
+		t.Errorf("stopword-only query should not search, got %+v", matches)
+	}
+}
+
+func TestSearchMemoriesText_FollowsStore(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	id, _ := store.InsertMemory(Memory{Content: "Use PostgreSQL for persistence", MemoryType: TypeDecision})
+
+	matches, _ := store.SearchMemoriesText("postgresql", 5)
+	if len(matches) != 1 || matches[0].ID != id {
+		t.Fatalf("expected inserted memory to be searchable, got %+v", matches)
+	}
+
+	store.DeleteMemory(id)
+	if matches, _ := store.SearchMemoriesText("postgresql", 5); len(matches) != 0 {
+		t.Errorf("deleted memory should not be searchable, got %+v", matches)
+	}
+}
+
+// The LIKE fallback is what builds without the sqlite_fts5 tag use; it is
+// run directly so that it stays covered when FTS5 is compiled in.
+func TestSearchText_Fallback(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	fileID, _ := store.UpsertFile(File{Path: "store.go", Language: "go", LastModified: time.Now(), ContentHash: "h"})
+	upsertID, _ := store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func (s *Store) UpsertFile(f File) (string, error)", ChunkType: "code"})
+	deleteID, _ := store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func (s *Store) DeleteFile(id string) error", ChunkType: "code"})
+	store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func parseTime(s string) time.Time", ChunkType: "code"})
+
+	matches, err := store.searchText("chunks", "upsertfile error", 10, false)
+	if err != nil {
+		t.Fatalf("searchText: %v", err)
+	}
+	if len(matches) != 2 || matches[0].ID != upsertID || matches[1].ID != deleteID {
+		t.Errorf("expected the UpsertFile chunk, then DeleteFile, got %+v", matches)
+	}
+	if matches, _ := store.searchText("chunks", "upsertfile error", 1, false); len(matches) != 1 {
+		t.Errorf("expected k to limit the matches, got %+v", matches)
+	}
+
+	active, _ := store.InsertMemory(Memory{Content: "Use PostgreSQL for persistence", MemoryType: TypeDecision})
+	archived, _ := store.InsertMemory(Memory{Content: "Use MySQL with PostgreSQL later", MemoryType: TypeDecision})
+	store.SetMemoryStatus(archived, StatusArchived)
+	matches, _ = store.searchText("memories", "postgresql", 10, false)
+	if len(matches) != 1 || matches[0].ID != active {
+		t.Errorf("expected only the active memory, got %+v", matches)
+	}
+}
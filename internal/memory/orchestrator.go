@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/memvra/memvra/internal/adapter"
@@ -14,6 +15,7 @@ type Orchestrator struct {
 	vectors  *VectorStore
 	ranker   *Ranker
 	embedder adapter.Embedder
+	dedupe   DedupeOptions
 }
 
 // NewOrchestrator creates an Orchestrator.
@@ -23,6 +25,7 @@ func NewOrchestrator(store *Store, vectors *VectorStore, ranker *Ranker, embedde
 		vectors:  vectors,
 		ranker:   ranker,
 		embedder: embedder,
+		dedupe:   DefaultDedupeOptions(),
 	}
 }
 
@@ -31,6 +34,9 @@ type RetrieveOptions struct {
 	TopKChunks          int
 	TopKMemories        int
 	SimilarityThreshold float64
+	// Tags, when set, restricts memories to those carrying at least one
+	// of them.
+	Tags []string
 }
 
 // RetrievalResult holds ranked results for context building.
@@ -39,103 +45,173 @@ type RetrievalResult struct {
 	Memories []Memory
 }
 
-// Retrieve embeds the query and returns ranked chunks and memories.
+// Retrieve returns ranked chunks and memories for query. Lexical (full-text)
+// matches are merged with vector matches by reciprocal rank fusion before
+// ranking, so retrieval still works when no embedder is configured or the
+// embedder fails.
 func (o *Orchestrator) Retrieve(ctx context.Context, query string, opts RetrieveOptions) (*RetrievalResult, error) {
-	// No embedder configured — fall back to listing all memories without ranking.
-	if o.embedder == nil {
-		mems, _ := o.store.ListMemories("")
-		return &RetrievalResult{Memories: mems}, nil
-	}
-
-	// Embed the query.
-	vecs, err := o.embedder.Embed(ctx, []string{query})
-	if err != nil || len(vecs) == 0 {
-		// Graceful degradation: no embeddings available — fall back to all memories.
-		mems, _ := o.store.ListMemories("")
-		return &RetrievalResult{Memories: mems}, nil
-	}
-	queryVec := vecs[0]
-
-	// Vector search for chunks.
-	chunkMatches, _ := o.vectors.SearchChunks(queryVec, opts.TopKChunks, opts.SimilarityThreshold)
-
-	// Vector search for memories.
-	memMatches, _ := o.vectors.SearchMemories(queryVec, opts.TopKMemories, opts.SimilarityThreshold)
-
-	// Fetch full chunk records and build similarity map.
-	chunkSimMap := make(map[string]float64, len(chunkMatches))
-	chunks := make([]Chunk, 0, len(chunkMatches))
-	for _, m := range chunkMatches {
-		sim := 1.0 / (1.0 + m.Distance)
-		chunkSimMap[m.ID] = sim
-		c, err := o.store.GetChunkByID(m.ID)
+	// Lexical search for chunks and memories. Over-fetch so fusion has
+	// candidates to choose from.
+	chunkText, _ := o.store.SearchChunksText(query, opts.TopKChunks*2)
+	memText, _ := o.store.SearchMemoriesText(query, opts.TopKMemories*2)
+
+	// Vector search, when an embedder is available.
+	var chunkVec, memVec []VectorMatch
+	embedded := false
+	if o.embedder != nil {
+		vecs, err := o.embedder.Embed(ctx, []string{query})
+		if err == nil && len(vecs) > 0 {
+			embedded = true
+			chunkVec, _ = o.vectors.SearchChunks(vecs[0], opts.TopKChunks*2, opts.SimilarityThreshold)
+			memVec, _ = o.vectors.SearchMemories(vecs[0], opts.TopKMemories*2, opts.SimilarityThreshold)
+		}
+	}
+
+	chunkScores := FuseRanks(textMatchIDs(chunkText), vectorMatchIDs(chunkVec))
+	memScores := FuseRanks(textMatchIDs(memText), vectorMatchIDs(memVec))
+
+	// Fetch full chunk records for the best fused candidates.
+	chunks := make([]Chunk, 0, len(chunkScores))
+	for _, id := range topIDs(chunkScores, opts.TopKChunks) {
+		c, err := o.store.GetChunkByID(id)
 		if err != nil {
 			continue
 		}
 		chunks = append(chunks, c)
 	}
 
-	// Fetch full memory records and build similarity map.
-	memSimMap := make(map[string]float64, len(memMatches))
-	memories := make([]Memory, 0, len(memMatches))
-	for _, m := range memMatches {
-		sim := 1.0 / (1.0 + m.Distance)
-		memSimMap[m.ID] = sim
-		mem, err := o.store.GetMemoryByID(m.ID)
-		if err != nil {
+	// Fetch full memory records for the best fused candidates. Search
+	// doesn't know about status or tags, so superseded and archived
+	// memories and those without the wanted tags are dropped here, before
+	// the top k are taken.
+	memories := make([]Memory, 0, len(memScores))
+	for _, id := range topIDs(memScores, 0) {
+		if opts.TopKMemories > 0 && len(memories) == opts.TopKMemories {
+			break
+		}
+		mem, err := o.store.GetMemoryByID(id)
+		if err != nil || mem.Status != StatusActive || (len(opts.Tags) > 0 && !mem.HasAnyTag(opts.Tags)) {
 			continue
 		}
 		memories = append(memories, mem)
 	}
 
-	// Rank results.
-	rankedChunks := o.ranker.RankChunks(chunks, chunkSimMap)
-	rankedMems := o.ranker.RankMemories(memories, memSimMap)
+	result := &RetrievalResult{
+		Chunks:   rankedChunkSlice(o.ranker.RankChunks(chunks, chunkScores)),
+		Memories: rankedMemorySlice(o.ranker.RankMemories(memories, memScores)),
+	}
 
-	// Convert back to plain slices for the caller.
-	outChunks := make([]Chunk, len(rankedChunks))
-	for i, rc := range rankedChunks {
-		outChunks[i] = rc.Chunk
+	// Graceful degradation: without embeddings and without a lexical hit,
+	// fall back to the most important memories.
+	if !embedded && len(result.Memories) == 0 {
+		all, _ := o.store.ListMemories("", opts.Tags...)
+		if opts.TopKMemories > 0 && len(all) > opts.TopKMemories {
+			all = all[:opts.TopKMemories]
+		}
+		result.Memories = all
 	}
-	outMems := make([]Memory, len(rankedMems))
-	for i, rm := range rankedMems {
-		outMems[i] = rm.Memory
+
+	return result, nil
+}
+
+func textMatchIDs(matches []TextMatch) []string {
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
 	}
+	return ids
+}
 
-	return &RetrievalResult{
-		Chunks:   outChunks,
-		Memories: outMems,
-	}, nil
+func vectorMatchIDs(matches []VectorMatch) []string {
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return ids
 }
 
-// Remember stores a memory with its embedding.
-func (o *Orchestrator) Remember(ctx context.Context, content string, memType MemoryType, source string) (Memory, error) {
-	if !ValidMemoryType(memType) {
-		return Memory{}, fmt.Errorf("orchestrator: invalid memory type %q", memType)
+// topIDs returns up to k IDs from scores, highest score first.
+func topIDs(scores map[string]float64, k int) []string {
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
 	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if k > 0 && len(ids) > k {
+		ids = ids[:k]
+	}
+	return ids
+}
+
+func rankedChunkSlice(ranked []RankedChunk) []Chunk {
+	out := make([]Chunk, len(ranked))
+	for i, rc := range ranked {
+		out[i] = rc.Chunk
+	}
+	return out
+}
 
-	m := Memory{
+func rankedMemorySlice(ranked []RankedMemory) []Memory {
+	out := make([]Memory, len(ranked))
+	for i, rm := range ranked {
+		out[i] = rm.Memory
+	}
+	return out
+}
+
+// Remember stores a memory with its embedding. It is a shorthand for Save;
+// when the statement duplicates an existing memory, that memory is returned.
+func (o *Orchestrator) Remember(ctx context.Context, content string, memType MemoryType, source string) (Memory, error) {
+	res, err := o.Save(ctx, Memory{
 		Content:    content,
 		MemoryType: memType,
 		Importance: defaultImportance(memType),
 		Source:     source,
+	})
+	if err != nil {
+		return Memory{}, err
 	}
+	return res.Memory, nil
+}
 
-	id, err := o.store.InsertMemory(m)
+// UpdateMemory saves changes to an existing memory and, when its content
+// changed, regenerates its embedding. Without a working embedder the stale
+// embedding is dropped so vector search can't match the old text. Returns
+// the updated record.
+func (o *Orchestrator) UpdateMemory(ctx context.Context, m Memory) (Memory, error) {
+	if !ValidMemoryType(m.MemoryType) {
+		return Memory{}, fmt.Errorf("orchestrator: invalid memory type %q", m.MemoryType)
+	}
+	if m.Importance < 0 || m.Importance > 1 {
+		return Memory{}, fmt.Errorf("orchestrator: importance %.2f out of range 0-1", m.Importance)
+	}
+	old, err := o.store.GetMemoryByID(m.ID)
 	if err != nil {
-		return Memory{}, fmt.Errorf("orchestrator: insert memory: %w", err)
+		return Memory{}, err
+	}
+	if err := o.store.UpdateMemory(m); err != nil {
+		return Memory{}, err
 	}
-	m.ID = id
 
-	// Generate and store embedding (best-effort — non-fatal on failure).
-	if o.embedder != nil {
-		vecs, err := o.embedder.Embed(ctx, []string{content})
-		if err == nil && len(vecs) > 0 {
-			_ = o.vectors.UpsertMemoryEmbedding(id, vecs[0])
+	if m.Content != old.Content {
+		reembedded := false
+		if o.embedder != nil {
+			vecs, err := o.embedder.Embed(ctx, []string{m.Content})
+			if err == nil && len(vecs) > 0 {
+				reembedded = o.vectors.UpsertMemoryEmbedding(m.ID, vecs[0]) == nil
+			}
+		}
+		if !reembedded {
+			_ = o.vectors.DeleteMemoryEmbedding(m.ID)
 		}
 	}
 
-	return m, nil
+	return o.store.GetMemoryByID(m.ID)
 }
 
 // Forget removes a memory by ID (and its vector embedding).
@@ -51,7 +51,8 @@ func TestOrchestrator_Retrieve_NoEmbedder(t *testing.T) {
 	store.InsertMemory(Memory{Content: "use PostgreSQL", MemoryType: TypeDecision, Importance: 0.8})
 	store.InsertMemory(Memory{Content: "always validate input", MemoryType: TypeConstraint, Importance: 0.8})
 
-	// nil embedder — should fall back to listing all memories.
+	// nil embedder and no lexical match — should fall back to the most
+	// important memories.
 	orch := NewOrchestrator(store, vectors, NewRanker(), nil)
 
 	result, err := orch.Retrieve(context.Background(), "database choice", RetrieveOptions{
@@ -69,6 +70,65 @@ func TestOrchestrator_Retrieve_NoEmbedder(t *testing.T) {
 	}
 }
 
+func TestOrchestrator_Retrieve_NoEmbedderLexical(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	fileID, _ := store.UpsertFile(File{Path: "auth.go", Language: "go", LastModified: time.Now(), ContentHash: "h1"})
+	chunkID, _ := store.InsertChunkReturningID(Chunk{
+		FileID: fileID, Content: "func ValidateToken(raw string) error { return nil }", ChunkType: "code",
+	})
+	store.InsertChunkReturningID(Chunk{
+		FileID: fileID, Content: "func unrelated() {}", ChunkType: "code",
+	})
+	memID, _ := store.InsertMemory(Memory{Content: "ValidateToken must reject expired tokens", MemoryType: TypeConstraint, Importance: 0.8})
+	store.InsertMemory(Memory{Content: "use PostgreSQL", MemoryType: TypeDecision, Importance: 0.9})
+
+	orch := NewOrchestrator(store, vectors, NewRanker(), nil)
+
+	result, err := orch.Retrieve(context.Background(), "how does ValidateToken work?", RetrieveOptions{
+		TopKChunks:   10,
+		TopKMemories: 5,
+	})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(result.Chunks) != 1 || result.Chunks[0].ID != chunkID {
+		t.Errorf("expected the ValidateToken chunk via lexical search, got %+v", result.Chunks)
+	}
+	if len(result.Memories) != 1 || result.Memories[0].ID != memID {
+		t.Errorf("expected only the matching memory, got %+v", result.Memories)
+	}
+}
+
+func TestOrchestrator_Retrieve_FusesLexicalAndVector(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	fileID, _ := store.UpsertFile(File{Path: "main.go", Language: "go", LastModified: time.Now(), ContentHash: "h1"})
+	vecID, _ := store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func main() {}", ChunkType: "code"})
+	lexID, _ := store.InsertChunkReturningID(Chunk{FileID: fileID, Content: "func ParseConfig() {}", ChunkType: "code"})
+
+	// Only the first chunk has an embedding; the second is found by name.
+	vectors.UpsertChunkEmbedding(vecID, makeVec(1.0))
+
+	emb := &stubEmbedder{embeddings: [][]float32{makeVec(1.1)}}
+	orch := NewOrchestrator(store, vectors, NewRanker(), emb)
+
+	result, err := orch.Retrieve(context.Background(), "ParseConfig", RetrieveOptions{
+		TopKChunks:   10,
+		TopKMemories: 5,
+	})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	got := map[string]bool{}
+	for _, c := range result.Chunks {
+		got[c.ID] = true
+	}
+	if !got[vecID] || !got[lexID] {
+		t.Errorf("expected both vector and lexical matches, got %+v", result.Chunks)
+	}
+}
+
 func TestOrchestrator_Retrieve_EmbedError(t *testing.T) {
 	_, store, vectors := setupOrchestratorDB(t)
 
@@ -258,3 +318,56 @@ func TestOrchestrator_ForgetByType_InvalidType(t *testing.T) {
 		t.Error("expected error for invalid memory type")
 	}
 }
+
+// --- UpdateMemory tests ---
+
+func TestOrchestrator_UpdateMemory_Reembeds(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	emb := &stubEmbedder{embeddings: [][]float32{makeVec(1.0)}}
+	orch := NewOrchestrator(store, vectors, NewRanker(), emb)
+	mem, _ := orch.Remember(context.Background(), "use REST", TypeDecision, "user")
+
+	emb.embeddings = [][]float32{makeVec(3.0)}
+	mem.Content = "use gRPC"
+	updated, err := orch.UpdateMemory(context.Background(), mem)
+	if err != nil {
+		t.Fatalf("UpdateMemory: %v", err)
+	}
+	if updated.Content != "use gRPC" || updated.ID != mem.ID {
+		t.Errorf("unexpected updated memory: %+v", updated)
+	}
+
+	stored, _ := vectors.GetMemoryEmbedding(mem.ID)
+	if len(stored) == 0 || stored[0] != 3.0 {
+		t.Error("embedding not regenerated")
+	}
+}
+
+func TestOrchestrator_UpdateMemory_DropsStaleEmbedding(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+
+	orch := NewOrchestrator(store, vectors, NewRanker(), &stubEmbedder{embeddings: [][]float32{makeVec(1.0)}})
+	mem, _ := orch.Remember(context.Background(), "use REST", TypeDecision, "user")
+
+	// Without an embedder the old vector can't be replaced, so it's removed.
+	noEmbed := NewOrchestrator(store, vectors, NewRanker(), nil)
+	mem.Content = "use gRPC"
+	if _, err := noEmbed.UpdateMemory(context.Background(), mem); err != nil {
+		t.Fatalf("UpdateMemory: %v", err)
+	}
+	if stored, _ := vectors.GetMemoryEmbedding(mem.ID); stored != nil {
+		t.Error("expected stale embedding to be deleted")
+	}
+}
+
+func TestOrchestrator_UpdateMemory_InvalidImportance(t *testing.T) {
+	_, store, vectors := setupOrchestratorDB(t)
+	orch := NewOrchestrator(store, vectors, NewRanker(), nil)
+	mem, _ := orch.Remember(context.Background(), "note", TypeNote, "user")
+
+	mem.Importance = 1.5
+	if _, err := orch.UpdateMemory(context.Background(), mem); err == nil {
+		t.Error("expected error for importance > 1")
+	}
+}
@@ -62,3 +62,30 @@ func (r *Ranker) RankMemories(memories []Memory, similarityByID map[string]float
 	})
 	return ranked
 }
+
+// rrfK dampens the influence of top ranks in reciprocal rank fusion; 60 is
+// the value from the original RRF paper.
+const rrfK = 60
+
+// FuseRanks merges ranked ID lists (best first) with reciprocal rank fusion:
+// each ID scores Σ 1/(rrfK + rank) across the lists it appears in. Scores
+// are normalised so the best ID scores 1.0, making them usable as the
+// similarity input to RankChunks and RankMemories.
+func FuseRanks(lists ...[]string) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, list := range lists {
+		for rank, id := range list {
+			scores[id] += 1.0 / float64(rrfK+rank+1)
+		}
+	}
+	best := 0.0
+	for _, s := range scores {
+		if s > best {
+			best = s
+		}
+	}
+	for id := range scores {
+		scores[id] /= best
+	}
+	return scores
+}
@@ -85,3 +85,30 @@ func TestRankMemories_ZeroImportanceUsesDefault(t *testing.T) {
 		t.Errorf("expected score %f, got %f", expected, ranked[0].FinalScore)
 	}
 }
+
+func TestFuseRanks_RewardsAgreement(t *testing.T) {
+	lexical := []string{"a", "b", "c"}
+	vector := []string{"b", "d"}
+
+	scores := FuseRanks(lexical, vector)
+
+	if len(scores) != 4 {
+		t.Fatalf("expected 4 fused IDs, got %d", len(scores))
+	}
+	// "b" appears near the top of both lists, so it should win.
+	if scores["b"] != 1.0 {
+		t.Errorf("expected 'b' to be normalised to 1.0, got %f", scores["b"])
+	}
+	if scores["a"] <= scores["c"] {
+		t.Errorf("higher rank should score higher: a=%f c=%f", scores["a"], scores["c"])
+	}
+	if scores["a"] <= scores["d"] {
+		t.Errorf("rank 1 should beat rank 2: a=%f d=%f", scores["a"], scores["d"])
+	}
+}
+
+func TestFuseRanks_Empty(t *testing.T) {
+	if scores := FuseRanks(nil, nil); len(scores) != 0 {
+		t.Errorf("expected no scores, got %v", scores)
+	}
+}
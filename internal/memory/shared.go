@@ -0,0 +1,299 @@
+package memory
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SharedFileName is the team memory file kept next to the database in
+// .memvra/. It is meant to be committed, so decisions and constraints go
+// through code review while sessions and the database stay local.
+const SharedFileName = "shared.toml"
+
+// SourceShared is the Source of memories that come from the shared file.
+const SourceShared = "shared"
+
+const sharedHeader = `# Team memories shared through git. Memvra loads this file on every run
+# and keeps the local database in step; see ` + "`memvra sync`" + `.
+# The id may be left out of new entries; it is then derived from the content.
+
+`
+
+// sharedFile is the layout of shared.toml.
+type sharedFile struct {
+	Memories []sharedEntry `toml:"memory"`
+}
+
+type sharedEntry struct {
+	ID         string   `toml:"id,omitempty"`
+	Type       string   `toml:"type"`
+	Content    string   `toml:"content"`
+	Importance float64  `toml:"importance,omitempty"`
+	Tags       []string `toml:"tags,omitempty"`
+	Files      []string `toml:"files,omitempty"`
+	Scope      string   `toml:"scope,omitempty"`
+	Status     string   `toml:"status,omitempty"`
+	Supersedes string   `toml:"supersedes,omitempty"`
+}
+
+// SharedSync reports how reconciling the shared file changed the database.
+type SharedSync struct {
+	Loaded  bool // the shared file exists
+	Added   int
+	Updated int
+	Removed int
+}
+
+// SharedPath returns the path of the shared memory file for this store's
+// database.
+func (s *Store) SharedPath() string {
+	return filepath.Join(filepath.Dir(s.db.Path()), SharedFileName)
+}
+
+// LastSharedSync returns the result of the last LoadShared.
+func (s *Store) LastSharedSync() (SharedSync, error) {
+	return s.sharedSync, s.sharedErr
+}
+
+// LoadShared reads the shared file, if there is one, and reconciles the
+// database with it, so that a `git pull` takes effect. The result is also
+// kept for LastSharedSync.
+func (s *Store) LoadShared() (SharedSync, error) {
+	s.sharedSync, s.sharedErr = s.loadShared()
+	return s.sharedSync, s.sharedErr
+}
+
+func (s *Store) loadShared() (SharedSync, error) {
+	if s.db.Path() == "" {
+		return SharedSync{}, nil
+	}
+	shared, err := ReadSharedFile(s.SharedPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return SharedSync{}, nil
+	}
+	if err != nil {
+		return SharedSync{}, err
+	}
+	res, err := s.SyncShared(shared)
+	res.Loaded = true
+	return res, err
+}
+
+// SaveSharedIfPresent is SaveShared for projects that share memories: it
+// does nothing when there is no shared file.
+func (s *Store) SaveSharedIfPresent() error {
+	if _, err := os.Stat(s.SharedPath()); err != nil {
+		return nil
+	}
+	return s.SaveShared()
+}
+
+// SyncShared makes the shared memories in the database match shared: new
+// entries are inserted under their IDs, changed ones updated, and shared
+// memories no longer listed are deleted. Embeddings of memories whose text
+// changed are dropped, to be regenerated by `memvra sync`.
+func (s *Store) SyncShared(shared []Memory) (SharedSync, error) {
+	var res SharedSync
+
+	existing, err := s.ListSharedMemories()
+	if err != nil {
+		return res, err
+	}
+	byID := make(map[string]Memory, len(existing))
+	for _, m := range existing {
+		byID[m.ID] = m
+	}
+
+	vectors := NewVectorStore(s.db)
+	listed := make(map[string]bool, len(shared))
+	for _, m := range shared {
+		listed[m.ID] = true
+		cur, err := s.GetMemoryByID(m.ID)
+		switch {
+		case err != nil:
+			res.Added++
+		case sameShared(cur, m):
+			continue
+		default:
+			res.Updated++
+			m.CreatedAt = cur.CreatedAt
+			if cur.Content != m.Content {
+				_ = vectors.DeleteMemoryEmbedding(m.ID)
+			}
+		}
+		if err := s.PutMemory(m); err != nil {
+			return res, err
+		}
+	}
+
+	for id := range byID {
+		if listed[id] {
+			continue
+		}
+		if err := s.DeleteMemory(id); err != nil {
+			return res, err
+		}
+		_ = vectors.DeleteMemoryEmbedding(id)
+		res.Removed++
+	}
+	return res, nil
+}
+
+// ListSharedMemories returns the memories that come from the shared file,
+// whatever their status, oldest first.
+func (s *Store) ListSharedMemories() ([]Memory, error) {
+	rows, err := s.db.Conn().Query(
+		`SELECT `+memoryColumns+` FROM memories WHERE source = ? ORDER BY created_at, id`, SourceShared,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list shared memories: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	return scanMemories(rows)
+}
+
+// SaveShared writes the database's shared memories to the shared file, so
+// that changes made through Memvra (remember, edit, forget) reach git. The
+// file is left untouched if it already says the same, and is never
+// overwritten while it doesn't parse, to keep hand edits.
+func (s *Store) SaveShared() error {
+	shared, err := s.ListSharedMemories()
+	if err != nil {
+		return err
+	}
+	path := s.SharedPath()
+	current, err := ReadSharedFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("%w — fix it before changing shared memories", err)
+	case slices.EqualFunc(current, shared, func(a, b Memory) bool { return a.ID == b.ID && sameShared(b, a) }):
+		return nil
+	}
+	return WriteSharedFile(path, shared)
+}
+
+// ReadSharedFile parses a shared memory file. Entries without an ID get one
+// derived from their content, so that every clone agrees on it.
+func ReadSharedFile(path string) ([]Memory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f sharedFile
+	if _, err := toml.Decode(string(data), &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	out := make([]Memory, 0, len(f.Memories))
+	seen := make(map[string]bool, len(f.Memories))
+	for i, e := range f.Memories {
+		content := strings.TrimSpace(e.Content)
+		if content == "" {
+			return nil, fmt.Errorf("%s: memory %d has no content", filepath.Base(path), i+1)
+		}
+		mt := MemoryType(strings.ToLower(e.Type))
+		if e.Type == "" {
+			mt = ClassifyMemoryType(content)
+		}
+		if !ValidMemoryType(mt) {
+			return nil, fmt.Errorf("%s: memory %d has unknown type %q", filepath.Base(path), i+1, e.Type)
+		}
+		status := MemoryStatus(strings.ToLower(e.Status))
+		switch status {
+		case "":
+			status = StatusActive
+		case StatusActive, StatusSuperseded, StatusArchived:
+		default:
+			return nil, fmt.Errorf("%s: memory %d has unknown status %q", filepath.Base(path), i+1, e.Status)
+		}
+
+		m := Memory{
+			ID:           e.ID,
+			Content:      content,
+			MemoryType:   mt,
+			Importance:   e.Importance,
+			Source:       SourceShared,
+			RelatedFiles: e.Files,
+			Tags:         NormalizeTags(e.Tags),
+			Scope:        strings.Trim(e.Scope, "/"),
+			Status:       status,
+			Supersedes:   e.Supersedes,
+		}
+		if m.ID == "" {
+			m.ID = sharedID(content)
+		}
+		if m.Importance == 0 {
+			m.Importance = defaultImportance(mt)
+		}
+		if seen[m.ID] {
+			return nil, fmt.Errorf("%s: memory %d repeats id %s", filepath.Base(path), i+1, m.ID)
+		}
+		seen[m.ID] = true
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// WriteSharedFile writes memories to a shared memory file, creating its
+// directory if needed.
+func WriteSharedFile(path string, memories []Memory) error {
+	var f sharedFile
+	for _, m := range memories {
+		e := sharedEntry{
+			ID:         m.ID,
+			Type:       string(m.MemoryType),
+			Content:    m.Content,
+			Importance: m.Importance,
+			Tags:       m.Tags,
+			Files:      m.RelatedFiles,
+			Scope:      m.Scope,
+			Supersedes: m.Supersedes,
+		}
+		if m.Status != StatusActive {
+			e.Status = string(m.Status)
+		}
+		f.Memories = append(f.Memories, e)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(sharedHeader)
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("encode %s: %w", filepath.Base(path), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// sharedID derives the ID of a shared entry written without one.
+func sharedID(content string) string {
+	sum := sha256.Sum256([]byte(normalizeContent(content)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// sameShared reports whether the stored memory cur already matches the
+// shared entry m.
+func sameShared(cur, m Memory) bool {
+	return cur.Source == SourceShared &&
+		cur.Content == m.Content &&
+		cur.MemoryType == m.MemoryType &&
+		cur.Importance == m.Importance &&
+		cur.Scope == m.Scope &&
+		cur.Status == m.Status &&
+		cur.Supersedes == m.Supersedes &&
+		slices.Equal(cur.Tags, m.Tags) &&
+		slices.Equal(cur.RelatedFiles, m.RelatedFiles)
+}
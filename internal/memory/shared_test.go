@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeShared(t *testing.T, store *Store, content string) {
+	t.Helper()
+	if err := os.WriteFile(store.SharedPath(), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SharedFileName)
+	os.WriteFile(path, []byte(`
+[[memory]]
+type = "constraint"
+content = "Never log access tokens"
+tags = ["Security"]
+
+[[memory]]
+id = "abc"
+type = "decision"
+content = "Use pgx for Postgres"
+importance = 0.9
+status = "superseded"
+`), 0o644)
+
+	got, err := ReadSharedFile(path)
+	if err != nil {
+		t.Fatalf("ReadSharedFile: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 memories, got %d", len(got))
+	}
+	if got[0].ID != sharedID("Never log access tokens") || got[0].Source != SourceShared {
+		t.Errorf("first memory: %+v", got[0])
+	}
+	if got[0].Importance != defaultImportance(TypeConstraint) || got[0].Tags[0] != "security" {
+		t.Errorf("defaults not applied: %+v", got[0])
+	}
+	if got[1].ID != "abc" || got[1].Status != StatusSuperseded || got[1].Importance != 0.9 {
+		t.Errorf("second memory: %+v", got[1])
+	}
+}
+
+func TestReadSharedFile_RejectsUnknownType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SharedFileName)
+	os.WriteFile(path, []byte("[[memory]]\ntype = \"rule\"\ncontent = \"x\"\n"), 0o644)
+	if _, err := ReadSharedFile(path); err == nil {
+		t.Fatal("expected an error for an unknown type")
+	}
+}
+
+func TestStore_LoadShared_ReconcilesDatabase(t *testing.T) {
+	database, store := setupTestDB(t)
+	local, _ := store.InsertMemory(Memory{Content: "Local note", MemoryType: TypeNote, Source: "user"})
+
+	writeShared(t, store, `
+[[memory]]
+id = "s1"
+type = "decision"
+content = "Use pgx for Postgres"
+
+[[memory]]
+id = "s2"
+type = "constraint"
+content = "Never log access tokens"
+`)
+	// A new store leaves the file alone until it is loaded.
+	store = NewStore(database)
+	if all, _ := store.ListMemories(""); len(all) != 1 {
+		t.Fatalf("NewStore should not load the shared file, got %d memories", len(all))
+	}
+	store.LoadShared()
+	res, err := store.LastSharedSync()
+	if err != nil || !res.Loaded || res.Added != 2 {
+		t.Fatalf("first load: %+v, %v", res, err)
+	}
+	all, _ := store.ListMemories("")
+	if len(all) != 3 {
+		t.Fatalf("expected shared and local memories together, got %d", len(all))
+	}
+
+	// Loading again changes nothing.
+	if res, _ := store.LoadShared(); res.Added+res.Updated+res.Removed != 0 {
+		t.Errorf("second load should be a no-op: %+v", res)
+	}
+
+	// An edited entry is updated and a removed one deleted.
+	writeShared(t, store, `
+[[memory]]
+id = "s1"
+type = "decision"
+content = "Use pgx v5 for Postgres"
+`)
+	res, err = store.LoadShared()
+	if err != nil || res.Updated != 1 || res.Removed != 1 {
+		t.Fatalf("reload: %+v, %v", res, err)
+	}
+	m, _ := store.GetMemoryByID("s1")
+	if m.Content != "Use pgx v5 for Postgres" {
+		t.Errorf("s1 not updated: %q", m.Content)
+	}
+	if _, err := store.GetMemoryByID(local); err != nil {
+		t.Errorf("local memory should be kept: %v", err)
+	}
+}
+
+func TestStore_SaveShared(t *testing.T) {
+	_, store := setupTestDB(t)
+	id, _ := store.InsertMemory(Memory{Content: "Prices are in cents", MemoryType: TypeConvention, Source: SourceShared, Tags: []string{"billing"}})
+
+	if err := store.SaveShared(); err != nil {
+		t.Fatalf("SaveShared: %v", err)
+	}
+	data, _ := os.ReadFile(store.SharedPath())
+	if !strings.Contains(string(data), id) || !strings.Contains(string(data), "Prices are in cents") {
+		t.Errorf("shared file missing the memory:\n%s", data)
+	}
+
+	got, err := ReadSharedFile(store.SharedPath())
+	if err != nil || len(got) != 1 || got[0].ID != id || got[0].Tags[0] != "billing" {
+		t.Errorf("round trip: %+v, %v", got, err)
+	}
+
+	// A file that doesn't parse is not overwritten.
+	writeShared(t, store, "[[memory]\n")
+	if err := store.SaveShared(); err == nil {
+		t.Error("expected an error for a malformed shared file")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/memvra/memvra/internal/db"
@@ -12,13 +13,23 @@ import (
 // Store provides read/write access to the Memvra SQLite database.
 type Store struct {
 	db *db.DB
+
+	sharedSync SharedSync // result of the last LoadShared
+	sharedErr  error
 }
 
-// NewStore creates a Store backed by the given DB.
+// NewStore creates a Store backed by the given DB. It doesn't read the
+// shared memory file; callers reconcile it with LoadShared before each
+// command or request.
 func NewStore(database *db.DB) *Store {
 	return &Store{db: database}
 }
 
+// execer runs statements on the database or within a transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
 // Conn exposes the underlying *sql.DB for low-level queries.
 func (s *Store) Conn() *sql.DB {
 	return s.db.Conn()
@@ -28,7 +39,11 @@ func (s *Store) Conn() *sql.DB {
 
 // UpsertProject inserts or replaces the project record.
 func (s *Store) UpsertProject(p Project) error {
-	_, err := s.db.Conn().Exec(`
+	return upsertProject(s.db.Conn(), p)
+}
+
+func upsertProject(e execer, p Project) error {
+	_, err := e.Exec(`
 		INSERT INTO project (id, name, root_path, tech_stack, architecture, conventions, file_count, chunk_count, updated_at)
 		VALUES (COALESCE((SELECT id FROM project LIMIT 1), lower(hex(randomblob(16)))),
 		        ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
@@ -50,10 +65,10 @@ func (s *Store) UpsertProject(p Project) error {
 // GetProject returns the single project record, or an error if not found.
 func (s *Store) GetProject() (Project, error) {
 	var p Project
-	row := s.db.Conn().QueryRow(`SELECT id, name, root_path, tech_stack, COALESCE(architecture,''), COALESCE(conventions,''), file_count, chunk_count, created_at, updated_at FROM project LIMIT 1`)
+	row := s.db.Conn().QueryRow(`SELECT id, name, root_path, tech_stack, COALESCE(architecture,''), COALESCE(conventions,''), file_count, chunk_count, COALESCE(embedding_model,''), COALESCE(embedding_dimension,0), created_at, updated_at FROM project LIMIT 1`)
 	var createdAt, updatedAt string
 	err := row.Scan(&p.ID, &p.Name, &p.RootPath, &p.TechStack, &p.Architecture, &p.Conventions,
-		&p.FileCount, &p.ChunkCount, &createdAt, &updatedAt)
+		&p.FileCount, &p.ChunkCount, &p.EmbeddingModel, &p.EmbeddingDimension, &createdAt, &updatedAt)
 	if err == sql.ErrNoRows {
 		return p, fmt.Errorf("store: project not initialised — run `memvra init` first")
 	}
@@ -65,6 +80,54 @@ func (s *Store) GetProject() (Project, error) {
 	return p, nil
 }
 
+// SetEmbeddingModel records the embedder that produced the stored vectors.
+func (s *Store) SetEmbeddingModel(model string, dimension int) error {
+	return setEmbeddingModel(s.db.Conn(), model, dimension)
+}
+
+func setEmbeddingModel(e execer, model string, dimension int) error {
+	res, err := e.Exec(
+		`UPDATE project SET embedding_model = ?, embedding_dimension = ?`, model, dimension,
+	)
+	if err != nil {
+		return fmt.Errorf("store: set embedding model: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("store: project not initialised — run `memvra init` first")
+	}
+	return nil
+}
+
+// ReplaceEmbeddings recreates the vector tables for dimension with only the
+// given chunk and memory vectors, keyed by ID, and records model as their
+// embedder. It runs in one transaction: if any step fails, the previous
+// vectors and model are kept.
+func (s *Store) ReplaceEmbeddings(model string, dimension int, chunks, memories map[string][]float32) error {
+	tx, err := s.db.Conn().Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := s.db.ResetVectorTablesTx(tx, dimension); err != nil {
+		return fmt.Errorf("store: reset vector tables: %w", err)
+	}
+	for id, vec := range chunks {
+		if err := upsertChunkEmbedding(tx, id, vec); err != nil {
+			return err
+		}
+	}
+	for id, vec := range memories {
+		if err := upsertMemoryEmbedding(tx, id, vec); err != nil {
+			return err
+		}
+	}
+	if err := setEmbeddingModel(tx, model, dimension); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // ---- Files ----
 
 // UpsertFile inserts or updates a file record. Returns the file ID.
@@ -102,9 +165,9 @@ func (s *Store) GetFileByPath(path string) (File, error) {
 // InsertChunk stores a new chunk. fileID must be a valid files.id.
 func (s *Store) InsertChunk(c Chunk) error {
 	_, err := s.db.Conn().Exec(`
-		INSERT INTO chunks (id, file_id, content, start_line, end_line, chunk_type)
-		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?)`,
-		c.FileID, c.Content, c.StartLine, c.EndLine, c.ChunkType,
+		INSERT INTO chunks (id, file_id, content, start_line, end_line, chunk_type, symbol, symbol_kind)
+		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?)`,
+		c.FileID, c.Content, c.StartLine, c.EndLine, c.ChunkType, c.Symbol, c.SymbolKind,
 	)
 	return err
 }
@@ -113,10 +176,10 @@ func (s *Store) InsertChunk(c Chunk) error {
 func (s *Store) InsertChunkReturningID(c Chunk) (string, error) {
 	var id string
 	err := s.db.Conn().QueryRow(`
-		INSERT INTO chunks (id, file_id, content, start_line, end_line, chunk_type)
-		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?)
+		INSERT INTO chunks (id, file_id, content, start_line, end_line, chunk_type, symbol, symbol_kind)
+		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?)
 		RETURNING id`,
-		c.FileID, c.Content, c.StartLine, c.EndLine, c.ChunkType,
+		c.FileID, c.Content, c.StartLine, c.EndLine, c.ChunkType, c.Symbol, c.SymbolKind,
 	).Scan(&id)
 	return id, err
 }
@@ -124,7 +187,7 @@ func (s *Store) InsertChunkReturningID(c Chunk) (string, error) {
 // ListAllChunks returns every chunk in the database (used for bulk embedding).
 func (s *Store) ListAllChunks() ([]Chunk, error) {
 	rows, err := s.db.Conn().Query(
-		`SELECT id, file_id, content, start_line, end_line, COALESCE(chunk_type,'code') FROM chunks`,
+		`SELECT id, file_id, content, start_line, end_line, COALESCE(chunk_type,'code'), COALESCE(symbol,''), COALESCE(symbol_kind,'') FROM chunks`,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("store: list all chunks: %w", err)
@@ -134,7 +197,7 @@ func (s *Store) ListAllChunks() ([]Chunk, error) {
 	var chunks []Chunk
 	for rows.Next() {
 		var c Chunk
-		if err := rows.Scan(&c.ID, &c.FileID, &c.Content, &c.StartLine, &c.EndLine, &c.ChunkType); err != nil {
+		if err := rows.Scan(&c.ID, &c.FileID, &c.Content, &c.StartLine, &c.EndLine, &c.ChunkType, &c.Symbol, &c.SymbolKind); err != nil {
 			return nil, err
 		}
 		chunks = append(chunks, c)
@@ -162,9 +225,129 @@ func (s *Store) CountFiles() (int, error) {
 	return n, err
 }
 
+// ---- Symbols ----
+
+// ReplaceFileSymbols swaps the symbols recorded for a file with syms
+// (used on (re-)index).
+func (s *Store) ReplaceFileSymbols(fileID string, syms []Symbol) error {
+	tx, err := s.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("store: replace symbols: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM symbols WHERE file_id = ?`, fileID); err != nil {
+		return fmt.Errorf("store: replace symbols: %w", err)
+	}
+	for _, sym := range syms {
+		if _, err := tx.Exec(`
+			INSERT INTO symbols (id, file_id, name, kind, start_line, end_line, signature)
+			VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?)`,
+			fileID, sym.Name, sym.Kind, sym.StartLine, sym.EndLine, sym.Signature,
+		); err != nil {
+			return fmt.Errorf("store: insert symbol %s: %w", sym.Name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// FindSymbols looks up symbol definitions by name. An exact lookup matches
+// the name itself or, for methods, the part after the receiver ("Start"
+// finds "Server.Start"). A fuzzy lookup matches case-insensitive substrings,
+// ranking exact and prefix matches first. kind optionally restricts the
+// results; limit <= 0 means no limit.
+func (s *Store) FindSymbols(name, kind string, fuzzy bool, limit int) ([]Symbol, error) {
+	query := `
+		SELECT s.id, s.file_id, f.path, s.name, s.kind,
+		       COALESCE(s.start_line,0), COALESCE(s.end_line,0), COALESCE(s.signature,'')
+		FROM symbols s JOIN files f ON f.id = s.file_id`
+	var args []any
+	if fuzzy {
+		query += ` WHERE s.name LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLike(name)+"%")
+	} else {
+		query += ` WHERE (s.name = ? OR substr(s.name, -length(?) - 1) = '.' || ?)`
+		args = append(args, name, name, name)
+	}
+	if kind != "" {
+		query += ` AND s.kind = ?`
+		args = append(args, kind)
+	}
+	query += ` ORDER BY CASE WHEN s.name = ? THEN 0 WHEN s.name LIKE ? ESCAPE '\' THEN 1 ELSE 2 END,
+		length(s.name), f.path, s.start_line`
+	args = append(args, name, escapeLike(name)+"%")
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: find symbols: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var syms []Symbol
+	for rows.Next() {
+		var sym Symbol
+		if err := rows.Scan(&sym.ID, &sym.FileID, &sym.FilePath, &sym.Name, &sym.Kind,
+			&sym.StartLine, &sym.EndLine, &sym.Signature); err != nil {
+			return nil, err
+		}
+		syms = append(syms, sym)
+	}
+	return syms, rows.Err()
+}
+
+// ListFileSymbols returns the symbols defined in the file at path, in the
+// order they appear.
+func (s *Store) ListFileSymbols(path string) ([]Symbol, error) {
+	rows, err := s.db.Conn().Query(`
+		SELECT s.id, s.file_id, f.path, s.name, s.kind,
+		       COALESCE(s.start_line,0), COALESCE(s.end_line,0), COALESCE(s.signature,'')
+		FROM symbols s JOIN files f ON f.id = s.file_id
+		WHERE f.path = ?
+		ORDER BY s.start_line, s.name`, path,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list file symbols: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var syms []Symbol
+	for rows.Next() {
+		var sym Symbol
+		if err := rows.Scan(&sym.ID, &sym.FileID, &sym.FilePath, &sym.Name, &sym.Kind,
+			&sym.StartLine, &sym.EndLine, &sym.Signature); err != nil {
+			return nil, err
+		}
+		syms = append(syms, sym)
+	}
+	return syms, rows.Err()
+}
+
+// CountSymbols returns the total number of indexed symbols.
+func (s *Store) CountSymbols() (int, error) {
+	var n int
+	err := s.db.Conn().QueryRow(`SELECT COUNT(*) FROM symbols`).Scan(&n)
+	return n, err
+}
+
+// escapeLike escapes the LIKE wildcards in s using backslash.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 // ---- Memories ----
 
-// InsertMemory persists a new memory and returns its generated ID.
+// memoryColumns is the column list read by scanMemory. It must be selected
+// from the memories table without an alias.
+const memoryColumns = `id, content, memory_type, importance, source, related_files, status, COALESCE(supersedes,''), COALESCE(scope,''), created_at, updated_at,
+	COALESCE((SELECT group_concat(tag, ',') FROM memory_tags WHERE memory_id = memories.id), '')`
+
+// InsertMemory persists a new memory and returns its generated ID. When
+// m.Supersedes names an existing active memory, that memory is marked
+// superseded in the same transaction.
 func (s *Store) InsertMemory(m Memory) (string, error) {
 	relatedJSON := "[]"
 	if len(m.RelatedFiles) > 0 {
@@ -175,15 +358,311 @@ func (s *Store) InsertMemory(m Memory) (string, error) {
 	if source == "" {
 		source = "user"
 	}
+	status := m.Status
+	if status == "" {
+		status = StatusActive
+	}
+
+	tx, err := s.db.Conn().Begin()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var supersedes any
+	if m.Supersedes != "" {
+		var oldStatus string
+		err := tx.QueryRow(`SELECT status FROM memories WHERE id = ?`, m.Supersedes).Scan(&oldStatus)
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("store: memory %q not found", m.Supersedes)
+		}
+		if err != nil {
+			return "", err
+		}
+		if MemoryStatus(oldStatus) != StatusActive {
+			return "", fmt.Errorf("store: memory %q is %s and cannot be superseded", m.Supersedes, oldStatus)
+		}
+		supersedes = m.Supersedes
+	}
+	var scope any
+	if m.Scope != "" {
+		scope = m.Scope
+	}
 
 	var id string
-	err := s.db.Conn().QueryRow(`
-		INSERT INTO memories (id, content, memory_type, importance, source, related_files)
-		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?)
+	err = tx.QueryRow(`
+		INSERT INTO memories (id, content, memory_type, importance, source, related_files, status, supersedes, scope)
+		VALUES (lower(hex(randomblob(16))), ?, ?, ?, ?, ?, ?, ?, ?)
 		RETURNING id`,
-		m.Content, string(m.MemoryType), m.Importance, source, relatedJSON,
+		m.Content, string(m.MemoryType), m.Importance, source, relatedJSON, string(status), supersedes, scope,
 	).Scan(&id)
-	return id, err
+	if err != nil {
+		return "", err
+	}
+	if err := setMemoryTags(tx, id, m.Tags); err != nil {
+		return "", err
+	}
+
+	if m.Supersedes != "" {
+		if _, err := tx.Exec(
+			`UPDATE memories SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+			string(StatusSuperseded), m.Supersedes,
+		); err != nil {
+			return "", fmt.Errorf("store: supersede memory: %w", err)
+		}
+	}
+	return id, tx.Commit()
+}
+
+// UpdateMemory replaces the content, type, importance, related files, scope
+// and tags of an existing memory, keeping its ID, status and created_at.
+// Callers that change the content should re-embed it (see
+// Orchestrator.UpdateMemory).
+func (s *Store) UpdateMemory(m Memory) error {
+	relatedJSON := "[]"
+	if len(m.RelatedFiles) > 0 {
+		b, _ := json.Marshal(m.RelatedFiles)
+		relatedJSON = string(b)
+	}
+
+	tx, err := s.db.Conn().Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		UPDATE memories
+		SET content = ?, memory_type = ?, importance = ?, related_files = ?, scope = NULLIF(?, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`,
+		m.Content, string(m.MemoryType), m.Importance, relatedJSON, m.Scope, m.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("store: update memory: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("store: memory %q not found", m.ID)
+	}
+	if err := setMemoryTags(tx, m.ID, m.Tags); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PutMemory writes m under its own ID, inserting it or replacing every
+// field of the stored memory, including status and timestamps. It is used
+// to merge memories from another database, where IDs must stay stable.
+func (s *Store) PutMemory(m Memory) error {
+	tx, err := s.db.Conn().Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := putMemory(tx, m); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// putMemory is PutMemory within tx.
+func putMemory(tx *sql.Tx, m Memory) error {
+	relatedJSON := "[]"
+	if len(m.RelatedFiles) > 0 {
+		b, _ := json.Marshal(m.RelatedFiles)
+		relatedJSON = string(b)
+	}
+	status := m.Status
+	if status == "" {
+		status = StatusActive
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO memories (id, content, memory_type, importance, source, related_files, status, supersedes, scope, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+		    content       = excluded.content,
+		    memory_type   = excluded.memory_type,
+		    importance    = excluded.importance,
+		    source        = excluded.source,
+		    related_files = excluded.related_files,
+		    status        = excluded.status,
+		    supersedes    = excluded.supersedes,
+		    scope         = excluded.scope,
+		    created_at    = excluded.created_at,
+		    updated_at    = excluded.updated_at`,
+		m.ID, m.Content, string(m.MemoryType), m.Importance, m.Source, relatedJSON, string(status),
+		m.Supersedes, m.Scope, formatTime(m.CreatedAt), formatTime(m.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("store: put memory: %w", err)
+	}
+	return setMemoryTags(tx, m.ID, m.Tags)
+}
+
+// Merge is a set of writes from another database, such as an unpacked
+// bundle, for ApplyMerge.
+type Merge struct {
+	Memories     []Memory
+	Sessions     []Session
+	SessionTurns []SessionTurn
+	Project      *Project // written when set
+	// Embeddings holds vectors for Memories by ID. A memory without one
+	// loses its stored vector, which no longer matches its content.
+	Embeddings map[string][]float32
+}
+
+// ApplyMerge writes m in a single transaction, so that a failure leaves
+// the database as it was.
+func (s *Store) ApplyMerge(m Merge) error {
+	tx, err := s.db.Conn().Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, mem := range m.Memories {
+		if err := putMemory(tx, mem); err != nil {
+			return err
+		}
+		if vec, ok := m.Embeddings[mem.ID]; ok {
+			err = upsertMemoryEmbedding(tx, mem.ID, vec)
+		} else {
+			err = deleteMemoryEmbedding(tx, mem.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, sess := range m.Sessions {
+		if _, err := putSession(tx, sess); err != nil {
+			return err
+		}
+	}
+	for _, t := range m.SessionTurns {
+		if _, err := putSessionTurn(tx, t); err != nil {
+			return err
+		}
+	}
+	if m.Project != nil {
+		if err := upsertProject(tx, *m.Project); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// AddMemoryTags attaches tags to a memory, keeping the tags it already has.
+func (s *Store) AddMemoryTags(id string, tags []string) error {
+	for _, tag := range NormalizeTags(tags) {
+		if _, err := s.db.Conn().Exec(
+			`INSERT OR IGNORE INTO memory_tags (memory_id, tag) VALUES (?, ?)`, id, tag,
+		); err != nil {
+			return fmt.Errorf("store: add memory tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// setMemoryTags replaces the tags of memory id within tx.
+func setMemoryTags(tx *sql.Tx, id string, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM memory_tags WHERE memory_id = ?`, id); err != nil {
+		return fmt.Errorf("store: set memory tags: %w", err)
+	}
+	for _, tag := range NormalizeTags(tags) {
+		if _, err := tx.Exec(`INSERT INTO memory_tags (memory_id, tag) VALUES (?, ?)`, id, tag); err != nil {
+			return fmt.Errorf("store: set memory tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// MergeMemory records that a duplicate of memory id was stored again: its
+// importance is raised to importance, its related files are replaced with
+// relatedFiles and updated_at is bumped.
+func (s *Store) MergeMemory(id string, importance float64, relatedFiles []string) error {
+	relatedJSON := "[]"
+	if len(relatedFiles) > 0 {
+		b, _ := json.Marshal(relatedFiles)
+		relatedJSON = string(b)
+	}
+	res, err := s.db.Conn().Exec(
+		`UPDATE memories SET importance = ?, related_files = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		importance, relatedJSON, id,
+	)
+	if err != nil {
+		return fmt.Errorf("store: merge memory: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("store: memory %q not found", id)
+	}
+	return nil
+}
+
+// SetMemoryStatus changes the lifecycle status of a memory, e.g. to archive
+// it without deleting it.
+func (s *Store) SetMemoryStatus(id string, status MemoryStatus) error {
+	res, err := s.db.Conn().Exec(
+		`UPDATE memories SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		string(status), id,
+	)
+	if err != nil {
+		return fmt.Errorf("store: set memory status: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("store: memory %q not found", id)
+	}
+	return nil
+}
+
+// MemoryHistory returns the supersession chain that id belongs to, oldest
+// first: the memories it replaced, the memory itself, and the memories that
+// replaced it. Chain links pointing at deleted memories are skipped.
+func (s *Store) MemoryHistory(id string) ([]Memory, error) {
+	m, err := s.GetMemoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{m.ID: true}
+	var older []Memory
+	for prev := m.Supersedes; prev != "" && !seen[prev]; {
+		seen[prev] = true
+		pm, err := s.GetMemoryByID(prev)
+		if err != nil {
+			break
+		}
+		older = append(older, pm)
+		prev = pm.Supersedes
+	}
+
+	history := make([]Memory, 0, len(older)+1)
+	for i := len(older) - 1; i >= 0; i-- {
+		history = append(history, older[i])
+	}
+	history = append(history, m)
+
+	for next := m.ID; ; {
+		nm, err := scanMemory(s.db.Conn().QueryRow(
+			`SELECT `+memoryColumns+` FROM memories WHERE supersedes = ? ORDER BY created_at LIMIT 1`, next,
+		))
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("store: memory history: %w", err)
+		}
+		if seen[nm.ID] {
+			break
+		}
+		seen[nm.ID] = true
+		history = append(history, nm)
+		next = nm.ID
+	}
+	return history, nil
 }
 
 // DeleteMemory removes a memory by ID.
@@ -209,6 +688,34 @@ func (s *Store) DeleteMemoriesByType(t MemoryType) (int, error) {
 	return int(n), nil
 }
 
+// DeleteMemoriesByTag removes all memories carrying tag, along with their
+// embeddings.
+func (s *Store) DeleteMemoriesByTag(tag string) (int, error) {
+	hasVectors := s.db.VectorDimension() > 0 // before Begin takes the only connection
+	tx, err := s.db.Conn().Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	tag = normalizeTag(tag)
+	if hasVectors {
+		if _, err := tx.Exec(
+			`DELETE FROM vec_memories WHERE id IN (SELECT memory_id FROM memory_tags WHERE tag = ?)`, tag,
+		); err != nil {
+			return 0, fmt.Errorf("store: delete memory embeddings: %w", err)
+		}
+	}
+	res, err := tx.Exec(
+		`DELETE FROM memories WHERE id IN (SELECT memory_id FROM memory_tags WHERE tag = ?)`, tag,
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), tx.Commit()
+}
+
 // DeleteAllMemories removes every memory record.
 func (s *Store) DeleteAllMemories() (int, error) {
 	res, err := s.db.Conn().Exec(`DELETE FROM memories`)
@@ -219,22 +726,28 @@ func (s *Store) DeleteAllMemories() (int, error) {
 	return int(n), nil
 }
 
-// ListMemories returns all memories, optionally filtered by type.
-// Pass empty string to get all types.
-func (s *Store) ListMemories(filterType MemoryType) ([]Memory, error) {
-	var rows *sql.Rows
-	var err error
-
-	if filterType == "" {
-		rows, err = s.db.Conn().Query(
-			`SELECT id, content, memory_type, importance, source, related_files, created_at, updated_at FROM memories ORDER BY importance DESC, created_at DESC`,
-		)
-	} else {
-		rows, err = s.db.Conn().Query(
-			`SELECT id, content, memory_type, importance, source, related_files, created_at, updated_at FROM memories WHERE memory_type = ? ORDER BY importance DESC, created_at DESC`,
-			string(filterType),
-		)
+// ListMemories returns all active memories, optionally filtered by type and
+// by tag. Pass empty string to get all types; with tags, only memories
+// carrying at least one of them are returned. Superseded and archived
+// memories are only reachable through GetMemoryByID and MemoryHistory.
+func (s *Store) ListMemories(filterType MemoryType, tags ...string) ([]Memory, error) {
+	where := []string{"status = 'active'"}
+	var args []any
+	if filterType != "" {
+		where = append(where, "memory_type = ?")
+		args = append(args, string(filterType))
 	}
+	if tags = NormalizeTags(tags); len(tags) > 0 {
+		where = append(where, "id IN (SELECT memory_id FROM memory_tags WHERE tag IN (?"+strings.Repeat(", ?", len(tags)-1)+"))")
+		for _, t := range tags {
+			args = append(args, t)
+		}
+	}
+
+	rows, err := s.db.Conn().Query(
+		`SELECT `+memoryColumns+` FROM memories WHERE `+strings.Join(where, " AND ")+` ORDER BY importance DESC, created_at DESC`,
+		args...,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -243,10 +756,20 @@ func (s *Store) ListMemories(filterType MemoryType) ([]Memory, error) {
 	return scanMemories(rows)
 }
 
-// CountMemoriesByType returns a count per memory type.
+// ListAllMemories returns every memory whatever its status, oldest first.
+func (s *Store) ListAllMemories() ([]Memory, error) {
+	rows, err := s.db.Conn().Query(`SELECT ` + memoryColumns + ` FROM memories ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("store: list all memories: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	return scanMemories(rows)
+}
+
+// CountMemoriesByType returns a count of active memories per memory type.
 func (s *Store) CountMemoriesByType() (map[MemoryType]int, error) {
 	rows, err := s.db.Conn().Query(
-		`SELECT memory_type, COUNT(*) FROM memories GROUP BY memory_type`,
+		`SELECT memory_type, COUNT(*) FROM memories WHERE status = 'active' GROUP BY memory_type`,
 	)
 	if err != nil {
 		return nil, err
@@ -294,6 +817,26 @@ func (s *Store) InsertSessionReturningID(sess Session) (string, error) {
 	return id, err
 }
 
+// PutSession inserts sess under its own ID and creation time, unless a
+// session with that ID exists. It reports whether the session was added.
+func (s *Store) PutSession(sess Session) (bool, error) {
+	return putSession(s.db.Conn(), sess)
+}
+
+func putSession(e execer, sess Session) (bool, error) {
+	res, err := e.Exec(`
+		INSERT OR IGNORE INTO sessions (id, question, context_used, response_summary, model_used, tokens_used, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		sess.ID, sess.Question, sess.ContextUsed, sess.ResponseSummary, sess.ModelUsed, sess.TokensUsed,
+		formatTime(sess.CreatedAt),
+	)
+	if err != nil {
+		return false, fmt.Errorf("store: put session: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
 // UpdateSessionSummary replaces the response_summary for an existing session.
 func (s *Store) UpdateSessionSummary(id, summary string) error {
 	_, err := s.db.Conn().Exec(
@@ -303,6 +846,20 @@ func (s *Store) UpdateSessionSummary(id, summary string) error {
 	return err
 }
 
+// UpdateSession replaces the question, context, summary and token count of
+// an existing session, for sessions recorded while they are still going on.
+func (s *Store) UpdateSession(sess Session) error {
+	_, err := s.db.Conn().Exec(`
+		UPDATE sessions SET question = ?, context_used = ?, response_summary = ?, tokens_used = ?
+		WHERE id = ?`,
+		sess.Question, sess.ContextUsed, sess.ResponseSummary, sess.TokensUsed, sess.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("store: update session: %w", err)
+	}
+	return nil
+}
+
 // PruneSessions deletes sessions older than the given number of days.
 // Returns the number of deleted rows.
 func (s *Store) PruneSessions(olderThanDays int) (int, error) {
@@ -365,13 +922,143 @@ func (s *Store) GetLastNSessions(n int) ([]Session, error) {
 	return out, rows.Err()
 }
 
-// ListMemoriesSince returns all memories created or updated since the given time.
+// GetSession returns the session whose ID is id or, failing that, the only
+// session whose ID starts with id.
+func (s *Store) GetSession(id string) (Session, error) {
+	rows, err := s.db.Conn().Query(`
+		SELECT id, question, context_used, response_summary, model_used, tokens_used, created_at
+		FROM sessions
+		WHERE id LIKE ? ESCAPE '\'
+		ORDER BY id = ? DESC
+		LIMIT 2`, escapeLike(id)+"%", id,
+	)
+	if err != nil {
+		return Session{}, fmt.Errorf("store: get session: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []Session
+	for rows.Next() {
+		var sess Session
+		var createdAt string
+		if err := rows.Scan(
+			&sess.ID, &sess.Question, &sess.ContextUsed,
+			&sess.ResponseSummary, &sess.ModelUsed, &sess.TokensUsed,
+			&createdAt,
+		); err != nil {
+			return Session{}, err
+		}
+		sess.CreatedAt = parseTime(createdAt)
+		matches = append(matches, sess)
+	}
+	if err := rows.Err(); err != nil {
+		return Session{}, err
+	}
+
+	switch {
+	case id == "" || len(matches) == 0:
+		return Session{}, fmt.Errorf("store: session %q not found", id)
+	case len(matches) > 1 && matches[0].ID != id:
+		return Session{}, fmt.Errorf("store: session ID %q is ambiguous; give more characters", id)
+	}
+	return matches[0], nil
+}
+
+// AddSessionTurn appends t to the transcript of session t.SessionID and
+// returns the turn's position in it.
+func (s *Store) AddSessionTurn(t SessionTurn) (int, error) {
+	sources, err := json.Marshal(t.Sources)
+	if err != nil {
+		return 0, fmt.Errorf("store: add session turn: %w", err)
+	}
+	var turn int
+	err = s.db.Conn().QueryRow(`
+		INSERT INTO session_turns (session_id, turn, question, response, summary, sources)
+		SELECT ?, COALESCE(MAX(turn), 0) + 1, ?, ?, ?, ?
+		FROM session_turns WHERE session_id = ?
+		RETURNING turn`,
+		t.SessionID, t.Question, t.Response, t.Summary, string(sources), t.SessionID,
+	).Scan(&turn)
+	if err != nil {
+		return 0, fmt.Errorf("store: add session turn: %w", err)
+	}
+	return turn, nil
+}
+
+// PutSessionTurn stores a turn under its own session and turn number, as
+// when unpacking a bundle. It reports whether the turn was new; an existing
+// turn with that number is left as it is.
+func (s *Store) PutSessionTurn(t SessionTurn) (bool, error) {
+	return putSessionTurn(s.db.Conn(), t)
+}
+
+func putSessionTurn(e execer, t SessionTurn) (bool, error) {
+	sources, err := json.Marshal(t.Sources)
+	if err != nil {
+		return false, fmt.Errorf("store: put session turn: %w", err)
+	}
+	res, err := e.Exec(`
+		INSERT OR IGNORE INTO session_turns (session_id, turn, question, response, summary, sources, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		t.SessionID, t.Turn, t.Question, t.Response, t.Summary, string(sources), formatTime(t.CreatedAt),
+	)
+	if err != nil {
+		return false, fmt.Errorf("store: put session turn: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// UpdateSessionTurnSummary replaces the summary of one turn of a session.
+func (s *Store) UpdateSessionTurnSummary(sessionID string, turn int, summary string) error {
+	_, err := s.db.Conn().Exec(
+		`UPDATE session_turns SET summary = ? WHERE session_id = ? AND turn = ?`,
+		summary, sessionID, turn,
+	)
+	if err != nil {
+		return fmt.Errorf("store: update session turn summary: %w", err)
+	}
+	return nil
+}
+
+// ListSessionTurns returns the transcript of a session, oldest turn first.
+// Sessions recorded before transcripts were kept have no turns.
+func (s *Store) ListSessionTurns(sessionID string) ([]SessionTurn, error) {
+	rows, err := s.db.Conn().Query(`
+		SELECT session_id, turn, question, response, COALESCE(summary,''), COALESCE(sources,''), created_at
+		FROM session_turns
+		WHERE session_id = ?
+		ORDER BY turn`, sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list session turns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []SessionTurn
+	for rows.Next() {
+		var t SessionTurn
+		var sources, createdAt string
+		if err := rows.Scan(&t.SessionID, &t.Turn, &t.Question, &t.Response, &t.Summary, &sources, &createdAt); err != nil {
+			return nil, err
+		}
+		if sources != "" {
+			_ = json.Unmarshal([]byte(sources), &t.Sources)
+		}
+		t.CreatedAt = parseTime(createdAt)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// ListMemoriesSince returns all active memories created or updated since the
+// given time.
 func (s *Store) ListMemoriesSince(since time.Time) ([]Memory, error) {
 	ts := since.UTC().Format("2006-01-02 15:04:05")
 	rows, err := s.db.Conn().Query(
-		`SELECT id, content, memory_type, importance, source, related_files, created_at, updated_at
+		`SELECT `+memoryColumns+`
 		 FROM memories
-		 WHERE created_at >= ? OR updated_at >= ?
+		 WHERE status = 'active' AND (created_at >= ? OR updated_at >= ?)
 		 ORDER BY memory_type, created_at DESC`,
 		ts, ts,
 	)
@@ -434,58 +1121,70 @@ func parseTime(s string) time.Time {
 	return time.Time{}
 }
 
+// formatTime renders t in the layout SQLite's CURRENT_TIMESTAMP uses, so
+// that explicitly written timestamps sort with generated ones. The zero
+// time becomes now.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
 func scanMemories(rows *sql.Rows) ([]Memory, error) {
 	var out []Memory
 	for rows.Next() {
-		var m Memory
-		var mt, createdAt, updatedAt, relatedFiles string
-		if err := rows.Scan(&m.ID, &m.Content, &mt, &m.Importance, &m.Source, &relatedFiles, &createdAt, &updatedAt); err != nil {
+		m, err := scanMemory(rows)
+		if err != nil {
 			return nil, err
 		}
-		m.MemoryType = MemoryType(mt)
-		m.CreatedAt = parseTime(createdAt)
-		m.UpdatedAt = parseTime(updatedAt)
-		if relatedFiles != "" && relatedFiles != "[]" {
-			_ = json.Unmarshal([]byte(relatedFiles), &m.RelatedFiles)
-		}
 		out = append(out, m)
 	}
 	return out, rows.Err()
 }
 
+// scanMemory reads one row selected with memoryColumns.
+func scanMemory(row interface{ Scan(...any) error }) (Memory, error) {
+	var m Memory
+	var mt, status, createdAt, updatedAt, relatedFiles, tags string
+	if err := row.Scan(&m.ID, &m.Content, &mt, &m.Importance, &m.Source, &relatedFiles, &status, &m.Supersedes, &m.Scope, &createdAt, &updatedAt, &tags); err != nil {
+		return m, err
+	}
+	m.MemoryType = MemoryType(mt)
+	m.Status = MemoryStatus(status)
+	m.CreatedAt = parseTime(createdAt)
+	m.UpdatedAt = parseTime(updatedAt)
+	if relatedFiles != "" && relatedFiles != "[]" {
+		_ = json.Unmarshal([]byte(relatedFiles), &m.RelatedFiles)
+	}
+	if tags != "" {
+		m.Tags = NormalizeTags([]string{tags})
+	}
+	return m, nil
+}
+
 // GetChunkByID returns a single chunk by its ID.
 func (s *Store) GetChunkByID(id string) (Chunk, error) {
 	var c Chunk
 	var createdAt string
 	err := s.db.Conn().QueryRow(
-		`SELECT id, file_id, content, start_line, end_line, chunk_type, created_at FROM chunks WHERE id = ?`, id,
-	).Scan(&c.ID, &c.FileID, &c.Content, &c.StartLine, &c.EndLine, &c.ChunkType, &createdAt)
+		`SELECT id, file_id, content, start_line, end_line, chunk_type, COALESCE(symbol,''), COALESCE(symbol_kind,''), created_at FROM chunks WHERE id = ?`, id,
+	).Scan(&c.ID, &c.FileID, &c.Content, &c.StartLine, &c.EndLine, &c.ChunkType, &c.Symbol, &c.SymbolKind, &createdAt)
 	if err == sql.ErrNoRows {
 		return c, fmt.Errorf("store: chunk %q not found", id)
 	}
 	return c, err
 }
 
-// GetMemoryByID returns a single memory by its ID.
+// GetMemoryByID returns a single memory by its ID, whatever its status.
 func (s *Store) GetMemoryByID(id string) (Memory, error) {
-	var m Memory
-	var mt, createdAt, updatedAt, relatedFiles string
-	err := s.db.Conn().QueryRow(
-		`SELECT id, content, memory_type, importance, source, related_files, created_at, updated_at FROM memories WHERE id = ?`, id,
-	).Scan(&m.ID, &m.Content, &mt, &m.Importance, &m.Source, &relatedFiles, &createdAt, &updatedAt)
+	m, err := scanMemory(s.db.Conn().QueryRow(
+		`SELECT `+memoryColumns+` FROM memories WHERE id = ?`, id,
+	))
 	if err == sql.ErrNoRows {
 		return m, fmt.Errorf("store: memory %q not found", id)
 	}
-	if err != nil {
-		return m, err
-	}
-	m.MemoryType = MemoryType(mt)
-	m.CreatedAt = parseTime(createdAt)
-	m.UpdatedAt = parseTime(updatedAt)
-	if relatedFiles != "" && relatedFiles != "[]" {
-		_ = json.Unmarshal([]byte(relatedFiles), &m.RelatedFiles)
-	}
-	return m, nil
+	return m, err
 }
 
 // ListFiles returns every indexed file.
@@ -513,7 +1212,7 @@ func (s *Store) ListFiles() ([]File, error) {
 // ListChunksByFileID returns all chunks belonging to a file.
 func (s *Store) ListChunksByFileID(fileID string) ([]Chunk, error) {
 	rows, err := s.db.Conn().Query(
-		`SELECT id, file_id, content, start_line, end_line, COALESCE(chunk_type,'code') FROM chunks WHERE file_id = ?`,
+		`SELECT id, file_id, content, start_line, end_line, COALESCE(chunk_type,'code'), COALESCE(symbol,''), COALESCE(symbol_kind,'') FROM chunks WHERE file_id = ?`,
 		fileID,
 	)
 	if err != nil {
@@ -524,7 +1223,7 @@ func (s *Store) ListChunksByFileID(fileID string) ([]Chunk, error) {
 	var chunks []Chunk
 	for rows.Next() {
 		var c Chunk
-		if err := rows.Scan(&c.ID, &c.FileID, &c.Content, &c.StartLine, &c.EndLine, &c.ChunkType); err != nil {
+		if err := rows.Scan(&c.ID, &c.FileID, &c.Content, &c.StartLine, &c.EndLine, &c.ChunkType, &c.Symbol, &c.SymbolKind); err != nil {
 			return nil, err
 		}
 		chunks = append(chunks, c)
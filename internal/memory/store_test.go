@@ -3,6 +3,7 @@ package memory
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -334,6 +335,182 @@ func TestStore_GetMemoryByID(t *testing.T) {
 	}
 }
 
+func TestStore_InsertMemory_Supersedes(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	oldID, _ := store.InsertMemory(Memory{Content: "Use JWT auth", MemoryType: TypeDecision, Importance: 0.8})
+	newID, err := store.InsertMemory(Memory{Content: "Use session auth", MemoryType: TypeDecision, Importance: 0.8, Supersedes: oldID})
+	if err != nil {
+		t.Fatalf("InsertMemory: %v", err)
+	}
+
+	old, _ := store.GetMemoryByID(oldID)
+	if old.Status != StatusSuperseded {
+		t.Errorf("old status: got %q, want superseded", old.Status)
+	}
+	cur, _ := store.GetMemoryByID(newID)
+	if cur.Status != StatusActive || cur.Supersedes != oldID {
+		t.Errorf("new memory: status %q supersedes %q", cur.Status, cur.Supersedes)
+	}
+
+	mems, _ := store.ListMemories("")
+	if len(mems) != 1 || mems[0].ID != newID {
+		t.Errorf("ListMemories should only return the active memory, got %+v", mems)
+	}
+	counts, _ := store.CountMemoriesByType()
+	if counts[TypeDecision] != 1 {
+		t.Errorf("count: got %d, want 1", counts[TypeDecision])
+	}
+
+	// A superseded memory can't be superseded again.
+	if _, err := store.InsertMemory(Memory{Content: "x", MemoryType: TypeDecision, Supersedes: oldID}); err == nil {
+		t.Error("expected error superseding a superseded memory")
+	}
+	if _, err := store.InsertMemory(Memory{Content: "x", MemoryType: TypeDecision, Supersedes: "missing"}); err == nil {
+		t.Error("expected error superseding a missing memory")
+	}
+}
+
+func TestStore_MemoryHistory(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	a, _ := store.InsertMemory(Memory{Content: "v1", MemoryType: TypeNote})
+	b, _ := store.InsertMemory(Memory{Content: "v2", MemoryType: TypeNote, Supersedes: a})
+	c, _ := store.InsertMemory(Memory{Content: "v3", MemoryType: TypeNote, Supersedes: b})
+
+	for _, id := range []string{a, b, c} {
+		history, err := store.MemoryHistory(id)
+		if err != nil {
+			t.Fatalf("MemoryHistory(%s): %v", id, err)
+		}
+		if len(history) != 3 {
+			t.Fatalf("history length: got %d, want 3", len(history))
+		}
+		for i, want := range []string{"v1", "v2", "v3"} {
+			if history[i].Content != want {
+				t.Errorf("history[%d]: got %q, want %q", i, history[i].Content, want)
+			}
+		}
+	}
+
+	if _, err := store.MemoryHistory("missing"); err == nil {
+		t.Error("expected error for unknown memory")
+	}
+}
+
+func TestStore_SetMemoryStatus(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	id, _ := store.InsertMemory(Memory{Content: "old note", MemoryType: TypeNote})
+	if err := store.SetMemoryStatus(id, StatusArchived); err != nil {
+		t.Fatalf("SetMemoryStatus: %v", err)
+	}
+	if mems, _ := store.ListMemories(""); len(mems) != 0 {
+		t.Errorf("archived memory still listed: %+v", mems)
+	}
+	if matches, _ := store.SearchMemoriesText("old note", 5); len(matches) != 0 {
+		t.Errorf("archived memory still searchable: %+v", matches)
+	}
+	if err := store.SetMemoryStatus("missing", StatusArchived); err == nil {
+		t.Error("expected error for unknown memory")
+	}
+}
+
+func TestStore_UpdateMemory(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	id, _ := store.InsertMemory(Memory{Content: "use JWT", MemoryType: TypeNote, Importance: 0.5})
+	before, _ := store.GetMemoryByID(id)
+
+	err := store.UpdateMemory(Memory{
+		ID:           id,
+		Content:      "use JWT with RS256",
+		MemoryType:   TypeDecision,
+		Importance:   0.9,
+		RelatedFiles: []string{"auth/jwt.go"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMemory: %v", err)
+	}
+
+	got, _ := store.GetMemoryByID(id)
+	if got.Content != "use JWT with RS256" || got.MemoryType != TypeDecision || got.Importance != 0.9 {
+		t.Errorf("unexpected memory after update: %+v", got)
+	}
+	if len(got.RelatedFiles) != 1 || got.RelatedFiles[0] != "auth/jwt.go" {
+		t.Errorf("related files: got %v", got.RelatedFiles)
+	}
+	if !got.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("created_at changed: %v -> %v", before.CreatedAt, got.CreatedAt)
+	}
+
+	if err := store.UpdateMemory(Memory{ID: "missing", Content: "x", MemoryType: TypeNote}); err == nil {
+		t.Error("expected error for unknown memory")
+	}
+}
+
+func TestStore_MemoryTags(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	billing, _ := store.InsertMemory(Memory{Content: "Prices are in cents", MemoryType: TypeConvention, Tags: []string{"Billing", "#backend"}})
+	_, _ = store.InsertMemory(Memory{Content: "Use CSS modules", MemoryType: TypeConvention, Tags: []string{"frontend"}})
+	_, _ = store.InsertMemory(Memory{Content: "Write tests first", MemoryType: TypeConvention})
+
+	m, err := store.GetMemoryByID(billing)
+	if err != nil {
+		t.Fatalf("GetMemoryByID: %v", err)
+	}
+	if fmt.Sprint(m.Tags) != "[backend billing]" {
+		t.Errorf("tags: got %v", m.Tags)
+	}
+
+	tagged, _ := store.ListMemories("", "billing", "frontend")
+	if len(tagged) != 2 {
+		t.Errorf("ListMemories by tag: got %d, want 2", len(tagged))
+	}
+	if got, _ := store.ListMemories(TypeDecision, "billing"); len(got) != 0 {
+		t.Errorf("type and tag filters should combine, got %d", len(got))
+	}
+
+	m.Tags = []string{"payments"}
+	if err := store.UpdateMemory(m); err != nil {
+		t.Fatalf("UpdateMemory: %v", err)
+	}
+	if err := store.AddMemoryTags(billing, []string{"payments", "api"}); err != nil {
+		t.Fatalf("AddMemoryTags: %v", err)
+	}
+	m, _ = store.GetMemoryByID(billing)
+	if fmt.Sprint(m.Tags) != "[api payments]" {
+		t.Errorf("tags after update: got %v", m.Tags)
+	}
+
+	n, err := store.DeleteMemoriesByTag("Frontend")
+	if err != nil || n != 1 {
+		t.Errorf("DeleteMemoriesByTag: n=%d err=%v", n, err)
+	}
+	if all, _ := store.ListMemories(""); len(all) != 2 {
+		t.Errorf("expected 2 memories left, got %d", len(all))
+	}
+}
+
+func TestStore_MemoryScope(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	id, _ := store.InsertMemory(Memory{Content: "amounts are in cents", MemoryType: TypeConvention, Scope: "services/billing"})
+	m, _ := store.GetMemoryByID(id)
+	if m.Scope != "services/billing" {
+		t.Fatalf("scope: got %q", m.Scope)
+	}
+
+	m.Scope = ""
+	if err := store.UpdateMemory(m); err != nil {
+		t.Fatalf("UpdateMemory: %v", err)
+	}
+	if m, _ = store.GetMemoryByID(id); m.Scope != "" {
+		t.Errorf("scope after clearing: got %q", m.Scope)
+	}
+}
+
 func TestStore_GetChunkByID(t *testing.T) {
 	_, store := setupTestDB(t)
 
@@ -349,6 +526,111 @@ func TestStore_GetChunkByID(t *testing.T) {
 	}
 }
 
+func TestStore_ChunkSymbolRoundTrip(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	fileID, _ := store.UpsertFile(File{Path: "main.go", Language: "go", LastModified: time.Now(), ContentHash: "h"})
+	chunkID, err := store.InsertChunkReturningID(Chunk{
+		FileID: fileID, Content: "func main() {}", StartLine: 3, EndLine: 3,
+		ChunkType: "code", Symbol: "main", SymbolKind: "func",
+	})
+	if err != nil {
+		t.Fatalf("InsertChunkReturningID: %v", err)
+	}
+
+	got, _ := store.GetChunkByID(chunkID)
+	if got.Symbol != "main" || got.SymbolKind != "func" {
+		t.Errorf("GetChunkByID symbol: got %s %q", got.SymbolKind, got.Symbol)
+	}
+
+	chunks, _ := store.ListChunksByFileID(fileID)
+	if len(chunks) != 1 || chunks[0].Symbol != "main" {
+		t.Errorf("ListChunksByFileID symbol: got %+v", chunks)
+	}
+}
+
+func TestStore_FindSymbols(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	fileID, _ := store.UpsertFile(File{Path: "server.go", Language: "go", LastModified: time.Now(), ContentHash: "h"})
+	err := store.ReplaceFileSymbols(fileID, []Symbol{
+		{Name: "Server", Kind: "type", StartLine: 1, EndLine: 3, Signature: "type Server struct"},
+		{Name: "Server.Start", Kind: "method", StartLine: 5, EndLine: 9, Signature: "func (s *Server) Start() error"},
+		{Name: "NewServer", Kind: "func", StartLine: 11, EndLine: 13},
+		{Name: "max_size", Kind: "const", StartLine: 15, EndLine: 15},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceFileSymbols: %v", err)
+	}
+
+	exact, err := store.FindSymbols("Server", "", false, 0)
+	if err != nil {
+		t.Fatalf("FindSymbols: %v", err)
+	}
+	if len(exact) != 1 || exact[0].Name != "Server" || exact[0].FilePath != "server.go" {
+		t.Errorf("exact lookup: got %+v", exact)
+	}
+
+	method, _ := store.FindSymbols("Start", "", false, 0)
+	if len(method) != 1 || method[0].Name != "Server.Start" || method[0].Signature == "" {
+		t.Errorf("bare method name should match receiver-qualified symbol, got %+v", method)
+	}
+
+	fuzzy, _ := store.FindSymbols("server", "", true, 0)
+	if len(fuzzy) != 3 {
+		t.Fatalf("fuzzy lookup: expected 3 results, got %d", len(fuzzy))
+	}
+	if fuzzy[0].Name != "Server" {
+		t.Errorf("fuzzy lookup should rank the exact match first, got %q", fuzzy[0].Name)
+	}
+
+	byKind, _ := store.FindSymbols("server", "func", true, 0)
+	if len(byKind) != 1 || byKind[0].Name != "NewServer" {
+		t.Errorf("kind filter: got %+v", byKind)
+	}
+
+	// LIKE wildcards in the query are matched literally.
+	if got, _ := store.FindSymbols("x_s", "", true, 0); len(got) != 1 {
+		t.Errorf("underscore should match literally, got %+v", got)
+	}
+	if got, _ := store.FindSymbols("%", "", true, 0); len(got) != 0 {
+		t.Errorf("percent should match literally, got %+v", got)
+	}
+
+	// Re-indexing replaces the file's symbols.
+	_ = store.ReplaceFileSymbols(fileID, []Symbol{{Name: "Other", Kind: "func"}})
+	if n, _ := store.CountSymbols(); n != 1 {
+		t.Errorf("after replace: expected 1 symbol, got %d", n)
+	}
+
+	// Deleting the file cascades to its symbols.
+	_ = store.DeleteFile(fileID)
+	if n, _ := store.CountSymbols(); n != 0 {
+		t.Errorf("after delete: expected 0 symbols, got %d", n)
+	}
+}
+
+func TestStore_ListFileSymbols(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	fileID, _ := store.UpsertFile(File{Path: "server.go", Language: "go", LastModified: time.Now(), ContentHash: "h"})
+	store.ReplaceFileSymbols(fileID, []Symbol{
+		{Name: "NewServer", Kind: "func", StartLine: 11, EndLine: 13},
+		{Name: "Server", Kind: "type", StartLine: 1, EndLine: 3},
+	})
+
+	syms, err := store.ListFileSymbols("server.go")
+	if err != nil {
+		t.Fatalf("ListFileSymbols: %v", err)
+	}
+	if len(syms) != 2 || syms[0].Name != "Server" || syms[1].FilePath != "server.go" {
+		t.Errorf("expected symbols in line order, got %+v", syms)
+	}
+	if none, _ := store.ListFileSymbols("other.go"); len(none) != 0 {
+		t.Errorf("expected no symbols for an unindexed file, got %+v", none)
+	}
+}
+
 func TestStore_ListFiles(t *testing.T) {
 	_, store := setupTestDB(t)
 
@@ -494,6 +776,76 @@ func TestStore_UpdateSessionSummary(t *testing.T) {
 	}
 }
 
+func TestStore_UpdateSession(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	id, _ := store.InsertSessionReturningID(Session{Question: "q", ModelUsed: "claude", TokensUsed: 10})
+	err := store.UpdateSession(Session{ID: id, Question: "q", ContextUsed: `["a.go"]`, ResponseSummary: "1. q — a", TokensUsed: 25})
+	if err != nil {
+		t.Fatalf("UpdateSession: %v", err)
+	}
+	sessions, _ := store.GetLastNSessions(1)
+	got := sessions[0]
+	if got.ResponseSummary != "1. q — a" || got.TokensUsed != 25 || got.ModelUsed != "claude" {
+		t.Errorf("unexpected session after update: %+v", got)
+	}
+}
+
+func TestStore_SessionTurns(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	id, _ := store.InsertSessionReturningID(Session{Question: "q1", ModelUsed: "claude"})
+	for i, q := range []string{"q1", "q2"} {
+		n, err := store.AddSessionTurn(SessionTurn{SessionID: id, Question: q, Response: "full answer " + q, Sources: []string{"a.go"}})
+		if err != nil || n != i+1 {
+			t.Fatalf("AddSessionTurn: %d %v", n, err)
+		}
+	}
+	if err := store.UpdateSessionTurnSummary(id, 2, "short"); err != nil {
+		t.Fatalf("UpdateSessionTurnSummary: %v", err)
+	}
+
+	turns, err := store.ListSessionTurns(id)
+	if err != nil || len(turns) != 2 {
+		t.Fatalf("ListSessionTurns: %+v %v", turns, err)
+	}
+	if turns[0].Question != "q1" || turns[1].Response != "full answer q2" || turns[1].Summary != "short" || len(turns[1].Sources) != 1 {
+		t.Errorf("unexpected turns: %+v", turns)
+	}
+
+	// Pruning a session drops its transcript.
+	if _, err := store.PruneSessionsKeepLatest(0); err != nil {
+		t.Fatal(err)
+	}
+	if turns, _ := store.ListSessionTurns(id); len(turns) != 0 {
+		t.Errorf("expected turns to be deleted with the session, got %d", len(turns))
+	}
+}
+
+func TestStore_GetSession_Prefix(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	id, _ := store.InsertSessionReturningID(Session{Question: "q", ModelUsed: "claude"})
+	for _, arg := range []string{id, id[:6]} {
+		got, err := store.GetSession(arg)
+		if err != nil || got.ID != id || got.Question != "q" {
+			t.Errorf("GetSession(%q): %+v %v", arg, got, err)
+		}
+	}
+	if _, err := store.GetSession("zz"); err == nil {
+		t.Error("expected an error for an unknown ID")
+	}
+	if _, err := store.GetSession(""); err == nil {
+		t.Error("expected an error for an empty ID")
+	}
+
+	store.PutSession(Session{ID: "abc1", Question: "x"})
+	store.PutSession(Session{ID: "abc2", Question: "y"})
+	if _, err := store.GetSession("abc"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+}
+
 func TestStore_GetLastNSessions(t *testing.T) {
 	_, store := setupTestDB(t)
 
@@ -588,3 +940,81 @@ func TestStore_PruneSessionsKeepLatest_KeepAll(t *testing.T) {
 		t.Errorf("expected 0 pruned when keeping more than exist, got %d", pruned)
 	}
 }
+
+func TestStore_PutMemory_KeepsIDAndTimestamps(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := Memory{
+		ID: "abc123", Content: "Use pgx", MemoryType: TypeDecision, Importance: 0.8,
+		Source: "user", Tags: []string{"db"}, Status: StatusArchived,
+		CreatedAt: created, UpdatedAt: created,
+	}
+	if err := store.PutMemory(m); err != nil {
+		t.Fatalf("PutMemory: %v", err)
+	}
+	m.Content = "Use pgx v5"
+	m.Tags = []string{"postgres"}
+	if err := store.PutMemory(m); err != nil {
+		t.Fatalf("PutMemory again: %v", err)
+	}
+
+	all, err := store.ListAllMemories()
+	if err != nil {
+		t.Fatalf("ListAllMemories: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("expected 1 memory, got %d", len(all))
+	}
+	got := all[0]
+	if got.ID != "abc123" || got.Content != "Use pgx v5" || got.Status != StatusArchived {
+		t.Errorf("unexpected memory: %+v", got)
+	}
+	if !got.CreatedAt.Equal(created) {
+		t.Errorf("created_at: got %v, want %v", got.CreatedAt, created)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "postgres" {
+		t.Errorf("tags: got %v", got.Tags)
+	}
+}
+
+func TestStore_PutSession_IgnoresExisting(t *testing.T) {
+	_, store := setupTestDB(t)
+
+	sess := Session{ID: "s1", Question: "q", CreatedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+	if added, err := store.PutSession(sess); err != nil || !added {
+		t.Fatalf("PutSession: added=%v err=%v", added, err)
+	}
+	sess.Question = "changed"
+	if added, err := store.PutSession(sess); err != nil || added {
+		t.Fatalf("PutSession again: added=%v err=%v", added, err)
+	}
+	if n, _ := store.CountSessions(); n != 1 {
+		t.Errorf("expected 1 session, got %d", n)
+	}
+}
+
+func TestStore_DeleteMemoriesByTag_RemovesEmbeddings(t *testing.T) {
+	database, store := setupTestDB(t)
+	vectors := NewVectorStore(database)
+
+	tagged, _ := store.InsertMemory(Memory{Content: "use React", MemoryType: TypeDecision, Tags: []string{"frontend"}})
+	kept, _ := store.InsertMemory(Memory{Content: "use Go", MemoryType: TypeDecision})
+	vec := make([]float32, vectors.Dimension())
+	vec[0] = 1
+	for _, id := range []string{tagged, kept} {
+		if err := vectors.UpsertMemoryEmbedding(id, vec); err != nil {
+			t.Fatalf("UpsertMemoryEmbedding: %v", err)
+		}
+	}
+
+	if n, err := store.DeleteMemoriesByTag("frontend"); err != nil || n != 1 {
+		t.Fatalf("DeleteMemoriesByTag: n=%d err=%v", n, err)
+	}
+	if got, _ := vectors.GetMemoryEmbedding(tagged); got != nil {
+		t.Error("embedding of the deleted memory was kept")
+	}
+	if got, _ := vectors.GetMemoryEmbedding(kept); got == nil {
+		t.Error("embedding of an untagged memory was deleted")
+	}
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeTags cleans user-supplied tags: comma-separated values are split,
+// tags are lower-cased with a leading '#' dropped and inner whitespace
+// replaced by '-', and the result is de-duplicated and sorted.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	for _, t := range tags {
+		for _, part := range strings.Split(t, ",") {
+			part = normalizeTag(part)
+			if part != "" && !slices.Contains(out, part) {
+				out = append(out, part)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func normalizeTag(t string) string {
+	t = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(t)), "#")
+	return strings.Join(strings.Fields(t), "-")
+}
+
+// HasAnyTag reports whether m carries at least one of tags.
+func (m Memory) HasAnyTag(tags []string) bool {
+	for _, t := range m.Tags {
+		if slices.Contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// InTagScope reports whether m belongs in a session scoped to tags: untagged
+// memories apply everywhere, tagged ones only when a tag matches.
+func (m Memory) InTagScope(tags []string) bool {
+	return len(m.Tags) == 0 || m.HasAnyTag(tags)
+}
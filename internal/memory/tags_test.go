@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{"Frontend", "billing, #infra", " design system ", "frontend", ""})
+	want := []string{"billing", "design-system", "frontend", "infra"}
+	if !slices.Equal(got, want) {
+		t.Errorf("NormalizeTags = %v, want %v", got, want)
+	}
+}
+
+func TestMemory_InTagScope(t *testing.T) {
+	scope := []string{"frontend"}
+	if !(Memory{}).InTagScope(scope) {
+		t.Error("untagged memory should be in every scope")
+	}
+	if !(Memory{Tags: []string{"frontend", "api"}}).InTagScope(scope) {
+		t.Error("memory sharing a tag should be in scope")
+	}
+	if (Memory{Tags: []string{"backend"}}).InTagScope(scope) {
+		t.Error("memory with only other tags should be out of scope")
+	}
+}
@@ -23,30 +23,46 @@ func ValidMemoryType(t MemoryType) bool {
 	return false
 }
 
+// MemoryStatus is the lifecycle state of a stored memory. Only active
+// memories are injected into context and exported.
+type MemoryStatus string
+
+const (
+	StatusActive     MemoryStatus = "active"
+	StatusSuperseded MemoryStatus = "superseded"
+	StatusArchived   MemoryStatus = "archived"
+)
+
 // Memory is a single stored memory record.
 type Memory struct {
-	ID           string     `json:"id"`
-	Content      string     `json:"content"`
-	MemoryType   MemoryType `json:"memory_type"`
-	Importance   float64    `json:"importance"`
-	Source       string     `json:"source"` // "user" or "extracted"
-	RelatedFiles []string   `json:"related_files,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID           string       `json:"id"`
+	Content      string       `json:"content"`
+	MemoryType   MemoryType   `json:"memory_type"`
+	Importance   float64      `json:"importance"`
+	Source       string       `json:"source"` // "user", "extracted" or "imported"
+	RelatedFiles []string     `json:"related_files,omitempty"`
+	Tags         []string     `json:"tags,omitempty"`       // free-form areas such as "billing" or "frontend"
+	Scope        string       `json:"scope,omitempty"`      // directory the memory applies to; "" = whole project
+	Status       MemoryStatus `json:"status,omitempty"`     // active, superseded, archived
+	Supersedes   string       `json:"supersedes,omitempty"` // ID of the memory this one replaces
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
 }
 
 // Project holds the top-level project record stored in SQLite.
 type Project struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	RootPath     string    `json:"root_path"`
-	TechStack    string    `json:"tech_stack"`    // JSON blob
-	Architecture string    `json:"architecture"`  // JSON blob
-	Conventions  string    `json:"conventions"`   // JSON blob
-	FileCount    int       `json:"file_count"`
-	ChunkCount   int       `json:"chunk_count"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	RootPath           string    `json:"root_path"`
+	TechStack          string    `json:"tech_stack"`   // JSON blob
+	Architecture       string    `json:"architecture"` // JSON blob
+	Conventions        string    `json:"conventions"`  // JSON blob
+	FileCount          int       `json:"file_count"`
+	ChunkCount         int       `json:"chunk_count"`
+	EmbeddingModel     string    `json:"embedding_model,omitempty"`     // embedder behind stored vectors, e.g. "ollama/nomic-embed-text"
+	EmbeddingDimension int       `json:"embedding_dimension,omitempty"` // vector size of EmbeddingModel
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 // File represents an indexed source file.
@@ -61,13 +77,28 @@ type File struct {
 
 // Chunk is a content slice of a File.
 type Chunk struct {
-	ID        string    `json:"id"`
-	FileID    string    `json:"file_id"`
-	Content   string    `json:"content"`
-	StartLine int       `json:"start_line"`
-	EndLine   int       `json:"end_line"`
-	ChunkType string    `json:"chunk_type"` // code, config, test, docs
-	CreatedAt time.Time `json:"created_at"`
+	ID         string    `json:"id"`
+	FileID     string    `json:"file_id"`
+	Content    string    `json:"content"`
+	StartLine  int       `json:"start_line"`
+	EndLine    int       `json:"end_line"`
+	ChunkType  string    `json:"chunk_type"`            // code, config, test, docs
+	Symbol     string    `json:"symbol,omitempty"`      // top-level declaration this chunk covers
+	SymbolKind string    `json:"symbol_kind,omitempty"` // func, method, type, class, ...
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Symbol is a top-level declaration (function, type, class, ...) defined in
+// an indexed file.
+type Symbol struct {
+	ID        string `json:"id"`
+	FileID    string `json:"file_id"`
+	FilePath  string `json:"file_path,omitempty"` // populated by lookups
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Signature string `json:"signature,omitempty"`
 }
 
 // Session records a single memvra ask interaction.
@@ -81,6 +112,17 @@ type Session struct {
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// SessionTurn is one question and full response within a session.
+type SessionTurn struct {
+	SessionID string    `json:"session_id"`
+	Turn      int       `json:"turn"` // 1-based position in the session
+	Question  string    `json:"question"`
+	Response  string    `json:"response"`
+	Summary   string    `json:"summary,omitempty"`
+	Sources   []string  `json:"sources,omitempty"` // context labels injected for this turn
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // Stats summarises what's stored for a project.
 type Stats struct {
 	ProjectName string
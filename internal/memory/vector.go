@@ -11,26 +11,31 @@ import (
 
 // VectorStore provides vector similarity search via sqlite-vec.
 type VectorStore struct {
+	db   *db.DB
 	conn *sql.DB
 }
 
 // NewVectorStore creates a VectorStore backed by the given DB.
 func NewVectorStore(database *db.DB) *VectorStore {
-	return &VectorStore{conn: database.Conn()}
+	return &VectorStore{db: database, conn: database.Conn()}
 }
 
 // UpsertChunkEmbedding inserts or replaces a chunk embedding in vec_chunks.
 // sqlite-vec virtual tables don't support ON CONFLICT upsert, so we
 // delete the existing row first then insert.
 func (v *VectorStore) UpsertChunkEmbedding(id string, embedding []float32) error {
+	return upsertChunkEmbedding(v.conn, id, embedding)
+}
+
+func upsertChunkEmbedding(e execer, id string, embedding []float32) error {
 	if len(embedding) == 0 {
 		return nil
 	}
 	blob := float32SliceToBlob(embedding)
-	if _, err := v.conn.Exec(`DELETE FROM vec_chunks WHERE id = ?`, id); err != nil {
+	if _, err := e.Exec(`DELETE FROM vec_chunks WHERE id = ?`, id); err != nil {
 		return fmt.Errorf("vector: delete old chunk embedding: %w", err)
 	}
-	if _, err := v.conn.Exec(`INSERT INTO vec_chunks (id, embedding) VALUES (?, ?)`, id, blob); err != nil {
+	if _, err := e.Exec(`INSERT INTO vec_chunks (id, embedding) VALUES (?, ?)`, id, blob); err != nil {
 		return fmt.Errorf("vector: insert chunk embedding: %w", err)
 	}
 	return nil
@@ -38,19 +43,37 @@ func (v *VectorStore) UpsertChunkEmbedding(id string, embedding []float32) error
 
 // UpsertMemoryEmbedding inserts or replaces a memory embedding in vec_memories.
 func (v *VectorStore) UpsertMemoryEmbedding(id string, embedding []float32) error {
+	return upsertMemoryEmbedding(v.conn, id, embedding)
+}
+
+func upsertMemoryEmbedding(e execer, id string, embedding []float32) error {
 	if len(embedding) == 0 {
 		return nil
 	}
 	blob := float32SliceToBlob(embedding)
-	if _, err := v.conn.Exec(`DELETE FROM vec_memories WHERE id = ?`, id); err != nil {
+	if _, err := e.Exec(`DELETE FROM vec_memories WHERE id = ?`, id); err != nil {
 		return fmt.Errorf("vector: delete old memory embedding: %w", err)
 	}
-	if _, err := v.conn.Exec(`INSERT INTO vec_memories (id, embedding) VALUES (?, ?)`, id, blob); err != nil {
+	if _, err := e.Exec(`INSERT INTO vec_memories (id, embedding) VALUES (?, ?)`, id, blob); err != nil {
 		return fmt.Errorf("vector: insert memory embedding: %w", err)
 	}
 	return nil
 }
 
+// GetMemoryEmbedding returns the stored embedding for a memory, or nil if it
+// has none.
+func (v *VectorStore) GetMemoryEmbedding(id string) ([]float32, error) {
+	var blob []byte
+	err := v.conn.QueryRow(`SELECT embedding FROM vec_memories WHERE id = ?`, id).Scan(&blob)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("vector: get memory embedding: %w", err)
+	}
+	return BlobToFloat32Slice(blob), nil
+}
+
 // VectorMatch represents a single similarity search result.
 type VectorMatch struct {
 	ID       string
@@ -102,10 +125,41 @@ func (v *VectorStore) DeleteChunkEmbedding(id string) error {
 
 // DeleteMemoryEmbedding removes a memory embedding.
 func (v *VectorStore) DeleteMemoryEmbedding(id string) error {
-	_, err := v.conn.Exec(`DELETE FROM vec_memories WHERE id = ?`, id)
+	return deleteMemoryEmbedding(v.conn, id)
+}
+
+func deleteMemoryEmbedding(e execer, id string) error {
+	_, err := e.Exec(`DELETE FROM vec_memories WHERE id = ?`, id)
 	return err
 }
 
+// Dimension returns the embedding dimension of the vector tables, or 0 if
+// they don't exist.
+func (v *VectorStore) Dimension() int {
+	return v.db.VectorDimension()
+}
+
+// CountEmbeddings returns how many chunk and memory embeddings are stored.
+func (v *VectorStore) CountEmbeddings() (int, error) {
+	var chunks, memories int
+	if err := v.conn.QueryRow(`SELECT COUNT(*) FROM vec_chunks`).Scan(&chunks); err != nil {
+		return 0, fmt.Errorf("vector: count chunk embeddings: %w", err)
+	}
+	if err := v.conn.QueryRow(`SELECT COUNT(*) FROM vec_memories`).Scan(&memories); err != nil {
+		return 0, fmt.Errorf("vector: count memory embeddings: %w", err)
+	}
+	return chunks + memories, nil
+}
+
+// Reset drops all stored embeddings and recreates the vector tables for
+// the given dimension.
+func (v *VectorStore) Reset(dimension int) error {
+	if err := v.db.ResetVectorTables(dimension); err != nil {
+		return fmt.Errorf("vector: reset tables: %w", err)
+	}
+	return nil
+}
+
 // ---- Helpers ----
 
 func scanMatches(rows *sql.Rows, minSimilarity float64) ([]VectorMatch, error) {
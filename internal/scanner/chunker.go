@@ -11,15 +11,21 @@ const (
 
 // RawChunk holds a slice of a source file before it is persisted.
 type RawChunk struct {
-	Content   string
-	StartLine int // 1-based
-	EndLine   int // 1-based, inclusive
-	ChunkType string
+	Content    string
+	StartLine  int // 1-based
+	EndLine    int // 1-based, inclusive
+	ChunkType  string
+	Symbol     string // top-level declaration covered, if any
+	SymbolKind string // func, method, type, class, ...
 }
 
-// ChunkFile splits the file content into overlapping chunks.
-// chunkType should be one of "code", "config", "test", "docs".
-func ChunkFile(content, chunkType string, maxLines int) []RawChunk {
+// ChunkFile splits the file content into chunks.
+// chunkType should be one of "code", "config", "test", "docs"; lang is the
+// value returned by LanguageForFile and may be empty.
+//
+// Code is split on top-level declarations where the language is supported
+// (see ExtractSymbols); everything else uses overlapping line windows.
+func ChunkFile(content, chunkType, lang string, maxLines int) []RawChunk {
 	if maxLines <= 0 {
 		maxLines = DefaultMaxLines
 	}
@@ -34,9 +40,80 @@ func ChunkFile(content, chunkType string, maxLines int) []RawChunk {
 		return chunkMarkdown(lines, maxLines)
 	}
 
+	if chunkType == "code" || chunkType == "test" {
+		if symbols := ExtractSymbols(content, lang); len(symbols) > 0 {
+			return chunkBySymbols(lines, symbols, chunkType, maxLines)
+		}
+	}
+
 	return chunkByLines(lines, chunkType, maxLines, DefaultOverlap)
 }
 
+// chunkBySymbols emits one chunk per top-level declaration. Code between
+// declarations (package clauses, imports, top-level statements) becomes its
+// own unnamed chunk. Declarations longer than maxLines fall back to
+// overlapping line windows that all carry the declaration's symbol.
+func chunkBySymbols(lines []string, symbols []Symbol, chunkType string, maxLines int) []RawChunk {
+	var chunks []RawChunk
+
+	emit := func(start, end int, sym Symbol) {
+		// Trim blank lines at both edges; skip the span if nothing is left.
+		for start <= end && strings.TrimSpace(lines[start-1]) == "" {
+			start++
+		}
+		for end >= start && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		if start > end {
+			return
+		}
+
+		span := lines[start-1 : end]
+		if len(span) <= maxLines {
+			chunks = append(chunks, RawChunk{
+				Content:    strings.Join(span, "\n"),
+				StartLine:  start,
+				EndLine:    end,
+				ChunkType:  chunkType,
+				Symbol:     sym.Name,
+				SymbolKind: sym.Kind,
+			})
+			return
+		}
+		for _, c := range chunkByLines(span, chunkType, maxLines, DefaultOverlap) {
+			c.StartLine += start - 1
+			c.EndLine += start - 1
+			c.Symbol = sym.Name
+			c.SymbolKind = sym.Kind
+			chunks = append(chunks, c)
+		}
+	}
+
+	next := 1
+	for _, sym := range symbols {
+		start, end := sym.StartLine, sym.EndLine
+		if start < next {
+			start = next // overlapping declarations — keep chunks disjoint
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if start > end {
+			continue
+		}
+		if start > next {
+			emit(next, start-1, Symbol{})
+		}
+		emit(start, end, sym)
+		next = end + 1
+	}
+	if next <= len(lines) {
+		emit(next, len(lines), Symbol{})
+	}
+
+	return chunks
+}
+
 // chunkByLines performs simple line-based chunking with overlap.
 func chunkByLines(lines []string, chunkType string, maxLines, overlap int) []RawChunk {
 	total := len(lines)
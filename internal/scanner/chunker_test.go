@@ -7,7 +7,7 @@ import (
 
 func TestChunkFile_SmallFile(t *testing.T) {
 	content := "line1\nline2\nline3"
-	chunks := ChunkFile(content, "code", 150)
+	chunks := ChunkFile(content, "code", "", 150)
 	if len(chunks) != 1 {
 		t.Fatalf("expected 1 chunk, got %d", len(chunks))
 	}
@@ -29,7 +29,7 @@ func TestChunkFile_LargeFile(t *testing.T) {
 	}
 	content := strings.Join(lines, "\n")
 
-	chunks := ChunkFile(content, "code", 150)
+	chunks := ChunkFile(content, "code", "", 150)
 	if len(chunks) < 2 {
 		t.Fatalf("expected at least 2 chunks for 300 lines, got %d", len(chunks))
 	}
@@ -46,14 +46,14 @@ func TestChunkFile_LargeFile(t *testing.T) {
 
 func TestChunkFile_DefaultMaxLines(t *testing.T) {
 	content := "one\ntwo\nthree"
-	chunks := ChunkFile(content, "code", 0)
+	chunks := ChunkFile(content, "code", "", 0)
 	if len(chunks) != 1 {
 		t.Fatalf("expected 1 chunk with default max lines, got %d", len(chunks))
 	}
 }
 
 func TestChunkFile_EmptyContent(t *testing.T) {
-	chunks := ChunkFile("", "code", 150)
+	chunks := ChunkFile("", "code", "", 150)
 	// Empty string split produces [""] which is 1 line — should produce 1 chunk.
 	if len(chunks) != 1 {
 		t.Fatalf("expected 1 chunk for empty content, got %d", len(chunks))
@@ -78,7 +78,7 @@ Other content.
 
 Details.`
 
-	chunks := ChunkFile(content, "docs", 150)
+	chunks := ChunkFile(content, "docs", "", 150)
 	if len(chunks) < 2 {
 		t.Fatalf("expected multiple chunks for markdown with headings, got %d", len(chunks))
 	}
@@ -98,7 +98,7 @@ func TestChunkFile_MarkdownForceSplit(t *testing.T) {
 	}
 	content := strings.Join(lines, "\n")
 
-	chunks := ChunkFile(content, "docs", 10)
+	chunks := ChunkFile(content, "docs", "", 10)
 	if len(chunks) < 2 {
 		t.Fatalf("expected force-split on large markdown section, got %d chunks", len(chunks))
 	}
@@ -134,3 +134,87 @@ func TestChunkByLines_TinyFinalChunk(t *testing.T) {
 		t.Errorf("last chunk should cover through line 155, got %d", last.EndLine)
 	}
 }
+
+func TestChunkFile_GoSplitsOnDeclarations(t *testing.T) {
+	content := `package main
+
+import "fmt"
+
+// greet prints a greeting.
+func greet(name string) {
+	fmt.Println("hello", name)
+}
+
+type User struct {
+	Name string
+}
+
+func main() {
+	greet("world")
+}`
+
+	chunks := ChunkFile(content, "code", "go", 150)
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks (preamble + 3 declarations), got %d", len(chunks))
+	}
+
+	if chunks[0].Symbol != "" || chunks[0].StartLine != 1 || chunks[0].EndLine != 3 {
+		t.Errorf("preamble chunk: got %q %d-%d", chunks[0].Symbol, chunks[0].StartLine, chunks[0].EndLine)
+	}
+
+	want := []struct {
+		symbol, kind string
+		start, end   int
+	}{
+		{"greet", "func", 5, 8},
+		{"User", "type", 10, 12},
+		{"main", "func", 14, 16},
+	}
+	for i, w := range want {
+		c := chunks[i+1]
+		if c.Symbol != w.symbol || c.SymbolKind != w.kind {
+			t.Errorf("chunk %d: got %s %q, want %s %q", i+1, c.SymbolKind, c.Symbol, w.kind, w.symbol)
+		}
+		if c.StartLine != w.start || c.EndLine != w.end {
+			t.Errorf("chunk %d (%s): got lines %d-%d, want %d-%d", i+1, w.symbol, c.StartLine, c.EndLine, w.start, w.end)
+		}
+	}
+	if !strings.HasPrefix(chunks[1].Content, "// greet prints") {
+		t.Errorf("doc comment should lead the greet chunk, got %q", chunks[1].Content)
+	}
+}
+
+func TestChunkFile_OversizedDeclarationFallsBack(t *testing.T) {
+	var lines []string
+	lines = append(lines, "package main", "", "func big() {")
+	for i := 0; i < 40; i++ {
+		lines = append(lines, "\tx := 1")
+	}
+	lines = append(lines, "}")
+	content := strings.Join(lines, "\n")
+
+	chunks := ChunkFile(content, "code", "go", 20)
+	var bigChunks int
+	for _, c := range chunks {
+		if c.Symbol == "big" {
+			bigChunks++
+			if c.EndLine-c.StartLine+1 > 20 {
+				t.Errorf("chunk %d-%d exceeds maxLines", c.StartLine, c.EndLine)
+			}
+		}
+	}
+	if bigChunks < 2 {
+		t.Fatalf("expected oversized declaration to be split into windows, got %d chunks", bigChunks)
+	}
+	if last := chunks[len(chunks)-1]; last.EndLine != len(lines) {
+		t.Errorf("last chunk should end at line %d, got %d", len(lines), last.EndLine)
+	}
+}
+
+func TestChunkFile_ConfigIgnoresLanguage(t *testing.T) {
+	content := "func a() {}\nfunc b() {}"
+	chunks := ChunkFile(content, "config", "go", 150)
+	if len(chunks) != 1 || chunks[0].Symbol != "" {
+		t.Errorf("config files should use line chunking, got %+v", chunks)
+	}
+}
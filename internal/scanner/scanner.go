@@ -19,10 +19,11 @@ type ScanResult struct {
 	Errors []error
 }
 
-// ScannedFile pairs a file record with its chunks.
+// ScannedFile pairs a file record with its chunks and symbols.
 type ScannedFile struct {
-	File   memory.File
-	Chunks []memory.Chunk // FileID is empty here; set after the file is stored.
+	File    memory.File
+	Chunks  []memory.Chunk  // FileID is empty here; set after the file is stored.
+	Symbols []memory.Symbol // FileID is empty here; set after the file is stored.
 }
 
 // ScanOptions controls scanner behaviour.
@@ -111,15 +112,18 @@ func Scan(opts ScanOptions) ScanResult {
 			},
 		}
 
-		rawChunks := ChunkFile(string(content), chunkType, maxLines)
+		rawChunks := ChunkFile(string(content), chunkType, lang, maxLines)
 		for _, rc := range rawChunks {
 			sf.Chunks = append(sf.Chunks, memory.Chunk{
-				Content:   rc.Content,
-				StartLine: rc.StartLine,
-				EndLine:   rc.EndLine,
-				ChunkType: rc.ChunkType,
+				Content:    rc.Content,
+				StartLine:  rc.StartLine,
+				EndLine:    rc.EndLine,
+				ChunkType:  rc.ChunkType,
+				Symbol:     rc.Symbol,
+				SymbolKind: rc.SymbolKind,
 			})
 		}
+		sf.Symbols = symbolRecords(ExtractSymbols(string(content), lang))
 
 		result.Files = append(result.Files, sf)
 		return nil
@@ -183,7 +187,7 @@ func ScanFile(root, relPath string, maxChunkLines int, ignore *IgnoreMatcher) (*
 	}
 
 	chunkType := ChunkTypeForFile(relPath)
-	rawChunks := ChunkFile(string(content), chunkType, maxChunkLines)
+	rawChunks := ChunkFile(string(content), chunkType, lang, maxChunkLines)
 
 	sf := &ScannedFile{
 		File: memory.File{
@@ -196,16 +200,38 @@ func ScanFile(root, relPath string, maxChunkLines int, ignore *IgnoreMatcher) (*
 
 	for _, rc := range rawChunks {
 		sf.Chunks = append(sf.Chunks, memory.Chunk{
-			Content:   rc.Content,
-			StartLine: rc.StartLine,
-			EndLine:   rc.EndLine,
-			ChunkType: rc.ChunkType,
+			Content:    rc.Content,
+			StartLine:  rc.StartLine,
+			EndLine:    rc.EndLine,
+			ChunkType:  rc.ChunkType,
+			Symbol:     rc.Symbol,
+			SymbolKind: rc.SymbolKind,
 		})
 	}
+	sf.Symbols = symbolRecords(ExtractSymbols(string(content), lang))
 
 	return sf, nil
 }
 
+// symbolRecords converts extracted declarations into storable symbol records.
+// Grouped Go declarations such as "const a, b" yield one record per name so
+// each can be looked up on its own.
+func symbolRecords(syms []Symbol) []memory.Symbol {
+	var out []memory.Symbol
+	for _, sym := range syms {
+		for _, name := range strings.Split(sym.Name, ", ") {
+			out = append(out, memory.Symbol{
+				Name:      name,
+				Kind:      sym.Kind,
+				StartLine: sym.StartLine,
+				EndLine:   sym.EndLine,
+				Signature: sym.Signature,
+			})
+		}
+	}
+	return out
+}
+
 // FindProjectRoot walks up from startDir looking for a project root marker.
 func FindProjectRoot(startDir string) (string, error) {
 	markers := []string{".git", "go.mod", "package.json", "Gemfile", "Cargo.toml",
@@ -115,6 +115,31 @@ func TestScanFile_RecognisedFile(t *testing.T) {
 	}
 }
 
+func TestScanFile_ExtractsSymbols(t *testing.T) {
+	dir := t.TempDir()
+	src := "package main\n\nconst (\n\ta = 1\n\tb = 2\n)\n\nfunc main() {\n}\n"
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0o644)
+
+	sf, err := ScanFile(dir, "main.go", 0, nil)
+	if err != nil {
+		t.Fatalf("ScanFile error: %v", err)
+	}
+
+	// Grouped declarations are stored one name per symbol.
+	want := []string{"a", "b", "main"}
+	if len(sf.Symbols) != len(want) {
+		t.Fatalf("expected %d symbols, got %+v", len(want), sf.Symbols)
+	}
+	for i, name := range want {
+		if sf.Symbols[i].Name != name {
+			t.Errorf("symbol %d: got %q, want %q", i, sf.Symbols[i].Name, name)
+		}
+	}
+	if sf.Symbols[2].Signature != "func main()" {
+		t.Errorf("signature: got %q", sf.Symbols[2].Signature)
+	}
+}
+
 func TestScanFile_SkipsBinary(t *testing.T) {
 	dir := t.TempDir()
 	os.WriteFile(filepath.Join(dir, "image.png"), []byte{0x89, 0x50}, 0o644)
@@ -0,0 +1,547 @@
+package scanner
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Symbol is a top-level declaration found in a source file.
+type Symbol struct {
+	Name      string
+	Kind      string // func, method, type, const, var, class, interface, module, ...
+	StartLine int    // 1-based, includes leading doc comments and decorators
+	EndLine   int    // 1-based, inclusive
+	Signature string // the declaration line, trimmed of any opening brace
+}
+
+// ExtractSymbols returns the top-level declarations in content, ordered by
+// start line. Go is parsed with go/parser; other languages recognised by
+// LanguageForFile use brace- or indentation-based heuristics. Returns nil
+// when the language is not supported or nothing could be found.
+func ExtractSymbols(content, lang string) []Symbol {
+	var syms []Symbol
+	switch {
+	case lang == "go":
+		syms = extractGoSymbols(content)
+		if syms == nil {
+			// Fall back to the brace heuristic for files that don't parse.
+			syms = extractHeuristicSymbols(content, lang)
+		}
+	default:
+		syms = extractHeuristicSymbols(content, lang)
+	}
+	sort.SliceStable(syms, func(i, j int) bool { return syms[i].StartLine < syms[j].StartLine })
+	return syms
+}
+
+// maxSignatureBytes caps the length of a stored signature.
+const maxSignatureBytes = 200
+
+// signature returns the declaration line at the 0-based index i, trimmed of
+// whitespace and a trailing opening brace, and cut to at most
+// maxSignatureBytes without splitting a character.
+func signature(lines []string, i int) string {
+	if i < 0 || i >= len(lines) {
+		return ""
+	}
+	sig := strings.TrimSpace(lines[i])
+	sig = strings.TrimSpace(strings.TrimSuffix(sig, "{"))
+	if len(sig) > maxSignatureBytes {
+		end := maxSignatureBytes
+		for end > 0 && !utf8.RuneStart(sig[end]) {
+			end--
+		}
+		sig = sig[:end]
+	}
+	return sig
+}
+
+// ---- Go ----
+
+func extractGoSymbols(content string) []Symbol {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	lines := strings.Split(content, "\n")
+	var syms []Symbol
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			start := d.Pos()
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+			sym := Symbol{
+				Name:      d.Name.Name,
+				Kind:      "func",
+				StartLine: fset.Position(start).Line,
+				EndLine:   fset.Position(d.End()).Line,
+				Signature: signature(lines, fset.Position(d.Pos()).Line-1),
+			}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				sym.Kind = "method"
+				if recv := goReceiverName(d.Recv.List[0].Type); recv != "" {
+					sym.Name = recv + "." + d.Name.Name
+				}
+			}
+			syms = append(syms, sym)
+
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			var names []string
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if n.Name != "_" {
+							names = append(names, n.Name)
+						}
+					}
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+			start := d.Pos()
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+			syms = append(syms, Symbol{
+				Name:      strings.Join(names, ", "),
+				Kind:      d.Tok.String(),
+				StartLine: fset.Position(start).Line,
+				EndLine:   fset.Position(d.End()).Line,
+				Signature: signature(lines, fset.Position(d.Pos()).Line-1),
+			})
+		}
+	}
+	return syms
+}
+
+// goReceiverName returns the base type name of a method receiver,
+// stripping pointers and type parameters.
+func goReceiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return goReceiverName(t.X)
+	case *ast.IndexExpr:
+		return goReceiverName(t.X)
+	case *ast.IndexListExpr:
+		return goReceiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// ---- Heuristic languages ----
+
+// declPattern matches the first line of a declaration. The first capture
+// group of re must be the symbol name.
+type declPattern struct {
+	re   *regexp.Regexp
+	kind string
+}
+
+func pat(kind, expr string) declPattern {
+	return declPattern{re: regexp.MustCompile(expr), kind: kind}
+}
+
+// Modifier prefixes shared by the C-family patterns below.
+const (
+	jsPrefix   = `^(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?`
+	javaPrefix = `^(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:public|private|protected|internal|static|final|abstract|sealed|partial|open|data|inline|override|suspend|readonly|unsafe|extern|virtual)\s+)*`
+)
+
+// braceLanguages lists declaration patterns for languages whose blocks are
+// delimited by braces. Only lines at brace depth zero are considered.
+var braceLanguages = map[string][]declPattern{
+	"go": {
+		pat("func", `^func\s+(?:\([^)]*\)\s*)?(\w+)`),
+		pat("type", `^type\s+(\w+)`),
+		pat("var", `^var\s+(\w+)`),
+		pat("const", `^const\s+(\w+)`),
+	},
+	"javascript": jsPatterns(),
+	"jsx":        jsPatterns(),
+	"typescript": tsPatterns(),
+	"tsx":        tsPatterns(),
+	"vue":        jsPatterns(),
+	"svelte":     jsPatterns(),
+	"rust": {
+		pat("func", `^(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?(?:extern\s+"[^"]*"\s+)?fn\s+(\w+)`),
+		pat("struct", `^(?:pub(?:\([^)]*\))?\s+)?struct\s+(\w+)`),
+		pat("enum", `^(?:pub(?:\([^)]*\))?\s+)?enum\s+(\w+)`),
+		pat("trait", `^(?:pub(?:\([^)]*\))?\s+)?(?:unsafe\s+)?trait\s+(\w+)`),
+		pat("impl", `^(?:unsafe\s+)?impl(?:<[^>]*>)?\s+(?:[\w:<>, ]+\s+for\s+)?([\w:]+)`),
+		pat("module", `^(?:pub(?:\([^)]*\))?\s+)?mod\s+(\w+)`),
+		pat("type", `^(?:pub(?:\([^)]*\))?\s+)?type\s+(\w+)`),
+		pat("const", `^(?:pub(?:\([^)]*\))?\s+)?(?:const|static)\s+(?:mut\s+)?(\w+)`),
+		pat("macro", `^macro_rules!\s*(\w+)`),
+	},
+	"java": {
+		pat("class", javaPrefix+`class\s+(\w+)`),
+		pat("interface", javaPrefix+`@?interface\s+(\w+)`),
+		pat("enum", javaPrefix+`enum\s+(\w+)`),
+		pat("record", javaPrefix+`record\s+(\w+)`),
+	},
+	"kotlin": {
+		pat("class", javaPrefix+`(?:enum\s+|annotation\s+)?class\s+(\w+)`),
+		pat("interface", javaPrefix+`(?:fun\s+)?interface\s+(\w+)`),
+		pat("object", javaPrefix+`object\s+(\w+)`),
+		pat("func", javaPrefix+`fun\s+(?:<[^>]*>\s*)?(?:[\w.]+\.)?(\w+)`),
+		pat("const", javaPrefix+`(?:const\s+)?va[lr]\s+(\w+)`),
+	},
+	"csharp": {
+		pat("namespace", `^namespace\s+([\w.]+)`),
+		pat("class", javaPrefix+`(?:static\s+)?class\s+(\w+)`),
+		pat("interface", javaPrefix+`interface\s+(\w+)`),
+		pat("struct", javaPrefix+`(?:record\s+)?struct\s+(\w+)`),
+		pat("enum", javaPrefix+`enum\s+(\w+)`),
+		pat("record", javaPrefix+`record\s+(\w+)`),
+	},
+	"c":   cPatterns(),
+	"cpp": append(cPatterns(), pat("class", `^(?:template\s*<[^>]*>\s*)?class\s+(\w+)`), pat("namespace", `^namespace\s+(\w+)`)),
+	"swift": {
+		pat("class", `^(?:(?:public|private|fileprivate|internal|open|final)\s+)*class\s+(\w+)`),
+		pat("struct", `^(?:(?:public|private|fileprivate|internal)\s+)*struct\s+(\w+)`),
+		pat("enum", `^(?:(?:public|private|fileprivate|internal|indirect)\s+)*enum\s+(\w+)`),
+		pat("protocol", `^(?:(?:public|private|fileprivate|internal)\s+)*protocol\s+(\w+)`),
+		pat("extension", `^(?:(?:public|private|fileprivate|internal)\s+)*extension\s+([\w.]+)`),
+		pat("func", `^(?:(?:public|private|fileprivate|internal|static)\s+)*func\s+(\w+)`),
+	},
+	"php": {
+		pat("class", `^(?:(?:abstract|final|readonly)\s+)*class\s+(\w+)`),
+		pat("interface", `^interface\s+(\w+)`),
+		pat("trait", `^trait\s+(\w+)`),
+		pat("enum", `^enum\s+(\w+)`),
+		pat("func", `^function\s+&?(\w+)`),
+	},
+	"scala": {
+		pat("class", `^(?:(?:abstract|final|sealed|case|private|protected|implicit)\s+)*class\s+(\w+)`),
+		pat("trait", `^(?:(?:sealed|private|protected)\s+)*trait\s+(\w+)`),
+		pat("object", `^(?:(?:case|private|protected|implicit)\s+)*object\s+(\w+)`),
+		pat("func", `^(?:(?:private|protected|implicit|override)\s+)*def\s+(\w+)`),
+	},
+	"protobuf": {
+		pat("message", `^message\s+(\w+)`),
+		pat("service", `^service\s+(\w+)`),
+		pat("enum", `^enum\s+(\w+)`),
+	},
+	"graphql": {
+		pat("type", `^(?:extend\s+)?(?:type|input|interface|enum|union|scalar)\s+(\w+)`),
+		pat("schema", `^(schema)\b`),
+		pat("query", `^(?:query|mutation|subscription|fragment)\s+(\w+)`),
+	},
+	"terraform": {
+		pat("resource", `^resource\s+"([^"]+"\s+"[^"]+)"`),
+		pat("data", `^data\s+"([^"]+"\s+"[^"]+)"`),
+		pat("module", `^module\s+"([^"]+)"`),
+		pat("variable", `^variable\s+"([^"]+)"`),
+		pat("output", `^output\s+"([^"]+)"`),
+		pat("provider", `^provider\s+"([^"]+)"`),
+	},
+	"bash": {
+		pat("func", `^(?:function\s+)?([\w-]+)\s*\(\)`),
+		pat("func", `^function\s+([\w-]+)`),
+	},
+}
+
+func jsPatterns() []declPattern {
+	return []declPattern{
+		pat("func", jsPrefix+`function\s*\*?\s*(\w+)`),
+		pat("class", jsPrefix+`class\s+(\w+)`),
+		pat("const", jsPrefix+`(?:const|let|var)\s+(\w+)`),
+	}
+}
+
+func tsPatterns() []declPattern {
+	return append(jsPatterns(),
+		pat("interface", jsPrefix+`interface\s+(\w+)`),
+		pat("type", jsPrefix+`type\s+(\w+)`),
+		pat("enum", jsPrefix+`(?:const\s+)?enum\s+(\w+)`),
+		pat("namespace", jsPrefix+`(?:namespace|module)\s+([\w.]+)`),
+	)
+}
+
+func cPatterns() []declPattern {
+	return []declPattern{
+		pat("struct", `^(?:typedef\s+)?struct\s+(\w+)\s*\{?\s*$`),
+		pat("enum", `^(?:typedef\s+)?enum\s+(?:class\s+)?(\w+)`),
+		pat("macro", `^#define\s+(\w+)`),
+		// Function definitions: a return type, a name, and an opening paren
+		// on a line that doesn't end the statement.
+		pat("func", `^(?:static\s+|inline\s+|extern\s+|const\s+|unsigned\s+|signed\s+|virtual\s+)*[\w:<>*&]+[\s*&]+\**([\w:~]+)\s*\([^;]*$`),
+	}
+}
+
+// indentLanguages lists declaration patterns for languages whose blocks are
+// delimited by indentation or keywords. Only unindented lines are considered.
+var indentLanguages = map[string][]declPattern{
+	"python": {
+		pat("class", `^class\s+(\w+)`),
+		pat("func", `^(?:async\s+)?def\s+(\w+)`),
+	},
+	"ruby": {
+		pat("class", `^class\s+([\w:]+)`),
+		pat("module", `^module\s+([\w:]+)`),
+		pat("func", `^def\s+(?:self\.)?(\w+[?!=]?)`),
+	},
+	"elixir": {
+		pat("module", `^defmodule\s+([\w.]+)`),
+		pat("protocol", `^defprotocol\s+([\w.]+)`),
+		pat("impl", `^defimpl\s+([\w.]+)`),
+	},
+	"lua": {
+		pat("func", `^(?:local\s+)?function\s+([\w.:]+)`),
+		pat("func", `^(?:local\s+)?([\w.]+)\s*=\s*function\b`),
+	},
+	"haskell": {
+		pat("data", `^(?:data|newtype)\s+(\w+)`),
+		pat("type", `^type\s+(?:family\s+)?(\w+)`),
+		pat("class", `^class\s+(?:\([^)]*\)\s*=>\s*)?(\w+)`),
+		pat("instance", `^instance\s+(.+?)\s+where`),
+		pat("func", `^(\w+)\s*::`),
+	},
+}
+
+// extractHeuristicSymbols finds declarations using lang's patterns. In
+// brace languages a declaration runs until the brace depth returns to zero;
+// otherwise it runs until the next unindented line.
+func extractHeuristicSymbols(content, lang string) []Symbol {
+	patterns, braces := braceLanguages[lang], true
+	if patterns == nil {
+		patterns, braces = indentLanguages[lang], false
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+	lines := strings.Split(content, "\n")
+
+	var depths []int
+	if braces {
+		depths = braceDepths(lines, charLiteralLanguages[lang])
+	}
+
+	var syms []Symbol
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if braces && depths[i] != 0 {
+			continue
+		}
+		if !braces && (line == "" || line[0] == ' ' || line[0] == '\t') {
+			continue
+		}
+		name, kind := matchDecl(strings.TrimRight(line, " \t\r"), patterns)
+		if name == "" {
+			continue
+		}
+
+		var end int
+		if braces {
+			end = braceDeclEnd(lines, depths, i, patterns)
+		} else {
+			end = indentDeclEnd(lines, i)
+		}
+
+		syms = append(syms, Symbol{
+			Name:      name,
+			Kind:      kind,
+			StartLine: leadingCommentStart(lines, i) + 1,
+			EndLine:   end + 1,
+			Signature: signature(lines, i),
+		})
+		i = end
+	}
+	return syms
+}
+
+func matchDecl(line string, patterns []declPattern) (name, kind string) {
+	for _, p := range patterns {
+		if m := p.re.FindStringSubmatch(line); m != nil && m[1] != "" {
+			return m[1], p.kind
+		}
+	}
+	return "", ""
+}
+
+// charLiteralLanguages lists the brace languages in which a single quote
+// only opens a character literal. Elsewhere in them it is not a delimiter:
+// Rust uses it for lifetimes ('a) and loop labels ('outer), Scala for
+// symbols.
+var charLiteralLanguages = map[string]bool{
+	"go": true, "rust": true, "c": true, "cpp": true,
+	"java": true, "kotlin": true, "csharp": true, "scala": true,
+}
+
+// braceDepths returns the brace nesting depth at the start of each line.
+// String literals and comments are skipped on a best-effort basis. With
+// charQuotes, single quotes are only taken to delimit character literals.
+func braceDepths(lines []string, charQuotes bool) []int {
+	depths := make([]int, len(lines))
+	depth := 0
+	inBlockComment := false
+	for i, line := range lines {
+		depths[i] = depth
+		var quote byte
+		for j := 0; j < len(line); j++ {
+			ch := line[j]
+			switch {
+			case inBlockComment:
+				if ch == '*' && j+1 < len(line) && line[j+1] == '/' {
+					inBlockComment = false
+					j++
+				}
+			case quote != 0:
+				if ch == '\\' {
+					j++
+				} else if ch == quote {
+					quote = 0
+				}
+			case ch == '/' && j+1 < len(line) && line[j+1] == '/':
+				j = len(line)
+			case ch == '/' && j+1 < len(line) && line[j+1] == '*':
+				inBlockComment = true
+				j++
+			case ch == '\'' && charQuotes:
+				if end := charLiteralEnd(line, j); end > 0 {
+					j = end
+				}
+			case ch == '"' || ch == '\'' || ch == '`':
+				quote = ch
+			case ch == '{':
+				depth++
+			case ch == '}':
+				if depth > 0 {
+					depth--
+				}
+			}
+		}
+		// Backtick strings may span lines; everything else ends at EOL.
+		if quote == '`' {
+			depth = depths[i]
+		}
+	}
+	return depths
+}
+
+// charLiteralEnd returns the index of the quote closing the character
+// literal that opens at line[j], such as 'x', '{' or '\u{7D}', or -1 if the
+// quote at j doesn't open one.
+func charLiteralEnd(line string, j int) int {
+	rest := line[j+1:]
+	if strings.HasPrefix(rest, `\`) && len(rest) > 2 {
+		// The longest escape is Rust's '\u{10FFFF}'.
+		if end := strings.IndexByte(rest[2:], '\''); end >= 0 && end <= len(`u{10FFFF}`) {
+			return j + 3 + end
+		}
+		return -1
+	}
+	_, size := utf8.DecodeRuneInString(rest)
+	if size > 0 && size < len(rest) && rest[size] == '\'' && rest[0] != '\'' {
+		return j + 1 + size
+	}
+	return -1
+}
+
+// braceDeclEnd returns the 0-based index of the last line of the declaration
+// starting at line start. A declaration ends where its braces close, at a
+// statement-terminating semicolon, or — if it never opens a block — before
+// the next blank line or declaration.
+func braceDeclEnd(lines []string, depths []int, start int, patterns []declPattern) int {
+	opened := false
+	for i := start; i < len(lines); i++ {
+		if i > start && depths[i] > 0 {
+			opened = true
+		}
+		next := 0
+		if i+1 < len(depths) {
+			next = depths[i+1]
+		}
+		if next > 0 {
+			opened = true
+			continue
+		}
+		trimmed := strings.TrimSpace(lines[i])
+		if opened {
+			return i
+		}
+		if strings.HasSuffix(trimmed, ";") || strings.HasSuffix(trimmed, "}") {
+			return i
+		}
+		if i+1 < len(lines) {
+			following := strings.TrimRight(lines[i+1], " \t\r")
+			if strings.TrimSpace(following) == "" {
+				return i
+			}
+			if name, _ := matchDecl(following, patterns); name != "" {
+				return i
+			}
+		}
+	}
+	return len(lines) - 1
+}
+
+// indentDeclEnd returns the 0-based index of the last line of the
+// declaration starting at line start: the last indented (or closing
+// keyword) line before the next unindented line.
+func indentDeclEnd(lines []string, start int) int {
+	end := start
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			end = i
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "end" || strings.HasPrefix(trimmed, "end ") ||
+			trimmed == ")" || trimmed == "]" || trimmed == "}" {
+			end = i
+		}
+		break
+	}
+	return end
+}
+
+// leadingCommentStart walks upwards from line start over directly attached
+// comment, doc and decorator/attribute lines, returning the first of them.
+func leadingCommentStart(lines []string, start int) int {
+	first := start
+	for i := start - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || !isCommentOrDecorator(trimmed) {
+			break
+		}
+		first = i
+	}
+	return first
+}
+
+func isCommentOrDecorator(trimmed string) bool {
+	for _, prefix := range []string{"//", "/*", "*", "#", "--", "@"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			// "#include"/"#define" and friends are code, not comments.
+			if prefix == "#" && (strings.HasPrefix(trimmed, "#include") ||
+				strings.HasPrefix(trimmed, "#define") || strings.HasPrefix(trimmed, "#import")) {
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
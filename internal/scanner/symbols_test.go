@@ -0,0 +1,238 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func symbolNames(syms []Symbol) []string {
+	names := make([]string, len(syms))
+	for i, s := range syms {
+		names[i] = s.Kind + " " + s.Name
+	}
+	return names
+}
+
+func assertSymbols(t *testing.T, got []Symbol, want []string) {
+	t.Helper()
+	names := symbolNames(got)
+	if len(names) != len(want) {
+		t.Fatalf("got %d symbols %v, want %d %v", len(names), names, len(want), want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("symbol %d: got %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestExtractSymbols_Go(t *testing.T) {
+	src := `package main
+
+import "fmt"
+
+// Server handles requests.
+type Server struct {
+	addr string
+}
+
+const (
+	a = 1
+	b = 2
+)
+
+// Start runs the server.
+func (s *Server) Start() error {
+	fmt.Println(s.addr)
+	return nil
+}
+
+func main() {
+	_ = (&Server{}).Start()
+}
+`
+	syms := ExtractSymbols(src, "go")
+	assertSymbols(t, syms, []string{"type Server", "const a, b", "method Server.Start", "func main"})
+
+	// Doc comments are attached to the declaration.
+	if syms[0].StartLine != 5 || syms[0].EndLine != 8 {
+		t.Errorf("Server range: got %d-%d, want 5-8", syms[0].StartLine, syms[0].EndLine)
+	}
+	if syms[2].StartLine != 15 || syms[2].EndLine != 19 {
+		t.Errorf("Start range: got %d-%d, want 15-19", syms[2].StartLine, syms[2].EndLine)
+	}
+}
+
+func TestExtractSymbols_GoInvalidFallsBack(t *testing.T) {
+	src := `package main
+
+func broken( {
+}
+
+func ok() {
+}
+`
+	syms := ExtractSymbols(src, "go")
+	if len(syms) == 0 {
+		t.Fatal("expected heuristic fallback to find declarations in unparseable Go")
+	}
+}
+
+func TestExtractSymbols_TypeScript(t *testing.T) {
+	src := `import { x } from "./x";
+
+/** A user. */
+export interface User {
+  id: string;
+}
+
+export const handler = async (req: Request) => {
+  if (req) { return 1; }
+  return 0;
+};
+
+export default class Service {
+  run() {
+    const s = "{";
+  }
+}
+
+type ID = string;
+`
+	syms := ExtractSymbols(src, "typescript")
+	assertSymbols(t, syms, []string{"interface User", "const handler", "class Service", "type ID"})
+
+	if syms[0].StartLine != 3 {
+		t.Errorf("User should include its doc comment: start %d, want 3", syms[0].StartLine)
+	}
+	if syms[2].EndLine != 17 {
+		t.Errorf("Service end: got %d, want 17 (braces inside strings must be ignored)", syms[2].EndLine)
+	}
+}
+
+func TestExtractSymbols_Python(t *testing.T) {
+	src := `import os
+
+
+@dataclass
+class Config:
+    name: str
+
+    def load(self):
+        return os.environ
+
+
+def main():
+    print("hi")
+
+
+if __name__ == "__main__":
+    main()
+`
+	syms := ExtractSymbols(src, "python")
+	assertSymbols(t, syms, []string{"class Config", "func main"})
+
+	if syms[0].StartLine != 4 || syms[0].EndLine != 9 {
+		t.Errorf("Config range: got %d-%d, want 4-9", syms[0].StartLine, syms[0].EndLine)
+	}
+	if syms[1].EndLine != 13 {
+		t.Errorf("main end: got %d, want 13 (top-level statements are not part of it)", syms[1].EndLine)
+	}
+}
+
+func TestExtractSymbols_Ruby(t *testing.T) {
+	src := `require "json"
+
+class User
+  def name
+    @name
+  end
+end
+
+module Helpers
+end
+`
+	syms := ExtractSymbols(src, "ruby")
+	assertSymbols(t, syms, []string{"class User", "module Helpers"})
+	if syms[0].EndLine != 7 {
+		t.Errorf("User end: got %d, want 7 (closing end included)", syms[0].EndLine)
+	}
+}
+
+func TestExtractSymbols_Rust(t *testing.T) {
+	src := `use std::fmt;
+
+#[derive(Debug)]
+pub struct Point {
+    x: i32,
+}
+
+impl fmt::Display for Point {
+    fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+        write!(f, "{}", self.x)
+    }
+}
+
+pub fn origin() -> Point {
+    Point { x: 0 }
+}
+`
+	syms := ExtractSymbols(src, "rust")
+	assertSymbols(t, syms, []string{"struct Point", "impl Point", "func origin"})
+	if syms[0].StartLine != 3 {
+		t.Errorf("Point should include its attribute: start %d, want 3", syms[0].StartLine)
+	}
+}
+
+func TestExtractSymbols_RustLifetimesAndChars(t *testing.T) {
+	src := `pub struct Parser<'a> {
+    input: &'a str,
+}
+
+impl<'a> Parser<'a> {
+    fn open(&self) -> char {
+        '{'
+    }
+
+    fn close(&self, c: char) -> bool {
+        'outer: loop {
+            if c == '}' || c == '\'' || c == '\u{7B}' {
+                break 'outer;
+            }
+        }
+        true
+    }
+}
+
+pub fn parse<'a>(input: &'a str) -> Parser<'a> {
+    Parser { input }
+}
+`
+	syms := ExtractSymbols(src, "rust")
+	assertSymbols(t, syms, []string{"struct Parser", "impl Parser", "func parse"})
+	if syms[1].StartLine != 5 || syms[1].EndLine != 18 {
+		t.Errorf("impl Parser: lines %d-%d, want 5-18", syms[1].StartLine, syms[1].EndLine)
+	}
+}
+
+func TestExtractSymbols_Unsupported(t *testing.T) {
+	if syms := ExtractSymbols("key: value", "yaml"); syms != nil {
+		t.Errorf("expected nil for unsupported language, got %v", syms)
+	}
+	if syms := ExtractSymbols("whatever", ""); syms != nil {
+		t.Errorf("expected nil for unknown language, got %v", syms)
+	}
+}
+
+func TestSignature_TruncatesOnRuneBoundary(t *testing.T) {
+	// 199 ASCII bytes put the 200-byte cut inside the two-byte "é".
+	line := "func f(" + strings.Repeat("a", 192) + "é, b int) {"
+	sig := signature([]string{line}, 0)
+	if !utf8.ValidString(sig) {
+		t.Fatalf("signature is not valid UTF-8: %q", sig)
+	}
+	if len(sig) != 199 || !strings.HasSuffix(sig, "a") {
+		t.Errorf("got %d bytes ending %q, want 199 ending in \"a\"", len(sig), sig[len(sig)-3:])
+	}
+}